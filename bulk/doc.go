@@ -0,0 +1,52 @@
+// The bulk package provides a standard shape for requests that act on many
+// resources at once (i.e. RestfulManyUpdater.UpdateMany and
+// RestfulManyDeleter.DeleteMany), and for the multi-status responses to them.
+//
+// A bulk request body is an array of operations, each identifying the resource
+// by its "id" and (for updates) containing the fields to change:
+//
+//     PATCH /people
+//     [{"id":"1","name":"Mat"},{"id":"2","name":"Tyler"}]
+//
+//     DELETE /people
+//     ["1","2"]
+//
+// Inside an action, the Request is parsed from the context, and each Operation
+// is processed in turn:
+//
+//     func (c *PeopleController) UpdateMany(ctx context.Context) error {
+//
+//       req, err := bulk.FromContext(ctx)
+//       if err != nil {
+//         return goweb.API.RespondWithError(ctx, http.StatusBadRequest, err.Error())
+//     }
+//
+//       response := req.Process(func(op bulk.Operation) bulk.Result {
+//         if err := c.update(op.ID, op.Data); err != nil {
+//           return bulk.Failure(op.ID, http.StatusNotFound, err)
+//         }
+//         return bulk.Success(op.ID, http.StatusOK, nil)
+//     })
+//
+//       return responders.RespondWithBulk(goweb.API, ctx, response)
+//
+//     }
+//
+// If the client adds ?atomic=true to the URL, the request should be all or
+// nothing, and Process stops at the first failure.  The operations processed
+// before it have already been applied, so use ProcessWithRollback to undo them
+// (i.e. by rolling back a transaction):
+//
+//     tx := c.db.Begin()
+//     response := req.ProcessWithRollback(func(op bulk.Operation) bulk.Result {
+//       ...
+//     }, func(applied []bulk.Operation) error {
+//       return tx.Rollback()
+//     })
+//     if !response.Failed() {
+//       tx.Commit()
+//     }
+//
+// The results of the operations that were rolled back are reported as failed
+// dependencies (424), like the ones that were never processed.
+package bulk
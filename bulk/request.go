@@ -0,0 +1,169 @@
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/objx"
+	"strconv"
+)
+
+const (
+	// IDField is the field of each operation that contains the ID of the resource.
+	IDField string = "id"
+
+	// AtomicParameter is the URL query parameter that asks for an all or nothing
+	// bulk request (i.e. ?atomic=true).
+	AtomicParameter string = "atomic"
+)
+
+// ErrNoOperations is returned by Parse when there are no operations in the request.
+var ErrNoOperations = errors.New("bulk: no operations")
+
+// Operation represents the action to take on a single resource.
+type Operation struct {
+	// Index is the position of the operation in the request.
+	Index int
+	// ID is the ID of the resource.
+	ID string
+	// Data contains the fields of the operation other than the ID, or is empty
+	// if the operation was just the ID.
+	Data objx.Map
+}
+
+// Request represents a bulk request.
+type Request struct {
+	// Operations are the operations to process, in order.
+	Operations []Operation
+	// Atomic indicates whether the client wants all of the operations to succeed, or
+	// none of them.
+	Atomic bool
+}
+
+// Parse parses a Request from the items of the request body, which may each be an
+// ID (a string or number) or an object containing an IDField.
+func Parse(items []interface{}, atomic bool) (*Request, error) {
+
+	if len(items) == 0 {
+		return nil, ErrNoOperations
+	}
+
+	request := &Request{Operations: make([]Operation, len(items)), Atomic: atomic}
+
+	for i, item := range items {
+
+		operation := Operation{Index: i, Data: make(objx.Map)}
+
+		switch item := item.(type) {
+		case map[string]interface{}:
+			for key, value := range item {
+				if key == IDField {
+					operation.ID = idString(value)
+				} else {
+					operation.Data[key] = value
+				}
+			}
+		default:
+			operation.ID = idString(item)
+		}
+
+		if len(operation.ID) == 0 {
+			return nil, fmt.Errorf("bulk: operation %d has no %s", i, IDField)
+		}
+
+		request.Operations[i] = operation
+
+	}
+
+	return request, nil
+
+}
+
+// FromContext parses the Request from the body of the request in the specified
+// context (i.e. for the UpdateMany or DeleteMany controller actions), using
+// RequestDataArray.
+//
+// The request is atomic if the URL query contains ?atomic=true.
+func FromContext(ctx context.Context) (*Request, error) {
+
+	items, err := ctx.RequestDataArray()
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(items, ctx.QueryValue(AtomicParameter) == "true")
+
+}
+
+// IDs gets the IDs of all the operations, in order.
+func (r *Request) IDs() []string {
+	ids := make([]string, len(r.Operations))
+	for i, operation := range r.Operations {
+		ids[i] = operation.ID
+	}
+	return ids
+}
+
+// Process calls the specified function for each operation in turn, and collects
+// the Results into a Response.
+//
+// For atomic requests, processing stops at the first failure, and the operations
+// that weren't processed are reported as failed dependencies.  The operations
+// processed before the failure have been applied, so their Results are kept; to
+// undo them, use ProcessWithRollback.
+func (r *Request) Process(fn func(operation Operation) Result) *Response {
+	return r.ProcessWithRollback(fn, nil)
+}
+
+// ProcessWithRollback is like Process, except that if an operation of an atomic
+// request fails, rollback is called with the operations that were applied before
+// it, so they can be undone.
+//
+// If rollback succeeds, those operations are reported as failed dependencies too,
+// as none of them have taken effect.  If it returns an error, their Results are
+// kept, since they are still applied.  rollback is never called for requests that
+// aren't atomic, and may be nil.
+func (r *Request) ProcessWithRollback(fn func(operation Operation) Result, rollback func(applied []Operation) error) *Response {
+
+	response := &Response{Atomic: r.Atomic}
+
+	for i, operation := range r.Operations {
+
+		result := fn(operation)
+		result.ID = operation.ID
+		response.Results = append(response.Results, result)
+
+		if r.Atomic && !result.Succeeded() {
+
+			// the earlier operations only didn't count if they were undone
+			if rollback != nil && rollback(r.Operations[:i]) == nil {
+				for j := range response.Results[:i] {
+					response.Results[j] = failedDependency(response.Results[j].ID)
+				}
+			}
+
+			for _, remaining := range r.Operations[i+1:] {
+				response.Results = append(response.Results, failedDependency(remaining.ID))
+			}
+
+			break
+		}
+
+	}
+
+	return response
+
+}
+
+// idString gets the ID from a string or (JSON) number.
+func idString(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(value)
+	}
+	return ""
+}
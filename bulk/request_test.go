@@ -0,0 +1,161 @@
+package bulk
+
+import (
+	"errors"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/objx"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	request, err := Parse([]interface{}{
+		map[string]interface{}{"id": "1", "name": "Mat"},
+		map[string]interface{}{"id": float64(2)},
+		"3",
+	}, false)
+
+	if assert.NoError(t, err) {
+		assert.False(t, request.Atomic)
+		assert.Equal(t, []string{"1", "2", "3"}, request.IDs())
+		assert.Equal(t, Operation{0, "1", objx.Map{"name": "Mat"}}, request.Operations[0])
+		assert.Equal(t, 2, request.Operations[2].Index)
+		assert.Equal(t, 0, len(request.Operations[2].Data))
+	}
+
+	request, _ = Parse([]interface{}{"1"}, true)
+	assert.True(t, request.Atomic)
+
+}
+
+func TestParse_Errors(t *testing.T) {
+
+	_, err := Parse([]interface{}{}, false)
+	assert.Equal(t, ErrNoOperations, err)
+
+	_, err = Parse([]interface{}{"1", map[string]interface{}{"name": "Mat"}}, false)
+	if assert.Error(t, err) {
+		assert.Equal(t, "bulk: operation 1 has no id", err.Error())
+	}
+
+	_, err = Parse([]interface{}{true}, false)
+	assert.Error(t, err)
+
+}
+
+func TestRequest_Process(t *testing.T) {
+
+	request, _ := Parse([]interface{}{"1", "2", "3"}, false)
+
+	var processed []string
+	response := request.Process(func(operation Operation) Result {
+		processed = append(processed, operation.ID)
+		if operation.ID == "2" {
+			return Failure(operation.ID, 404, errors.New("not found"))
+		}
+		return Success(operation.ID, 200, nil)
+	})
+
+	assert.Equal(t, []string{"1", "2", "3"}, processed)
+	assert.True(t, response.Failed())
+	assert.Equal(t, []Result{
+		{"1", 200, nil, nil},
+		{"2", 404, nil, []string{"not found"}},
+		{"3", 200, nil, nil},
+	}, response.Results)
+
+}
+
+func TestRequest_Process_Atomic(t *testing.T) {
+
+	request, _ := Parse([]interface{}{"1", "2", "3"}, true)
+
+	var processed []string
+	response := request.Process(func(operation Operation) Result {
+		processed = append(processed, operation.ID)
+		if operation.ID == "2" {
+			return Failure(operation.ID, 404, errors.New("not found"))
+		}
+		return Success(operation.ID, 200, nil)
+	})
+
+	assert.Equal(t, []string{"1", "2"}, processed)
+	assert.True(t, response.Atomic)
+	assert.True(t, response.Failed())
+	assert.Equal(t, []Result{
+		{"1", 200, nil, nil},
+		{"2", 404, nil, []string{"not found"}},
+		{"3", 424, nil, []string{FailedDependencyError}},
+	}, response.Results, "Operations that were applied keep their results")
+
+	// all succeed
+	response = request.Process(func(operation Operation) Result {
+		return Success(operation.ID, 204, nil)
+	})
+	assert.False(t, response.Failed())
+	assert.Equal(t, 3, len(response.Results))
+
+}
+
+func TestRequest_ProcessWithRollback(t *testing.T) {
+
+	request, _ := Parse([]interface{}{"1", "2", "3"}, true)
+
+	process := func(operation Operation) Result {
+		if operation.ID == "2" {
+			return Failure(operation.ID, 404, errors.New("not found"))
+		}
+		return Success(operation.ID, 200, nil)
+	}
+
+	var rolledBack []string
+	response := request.ProcessWithRollback(process, func(applied []Operation) error {
+		for _, operation := range applied {
+			rolledBack = append(rolledBack, operation.ID)
+		}
+		return nil
+	})
+
+	assert.Equal(t, []string{"1"}, rolledBack)
+	assert.Equal(t, []Result{
+		{"1", 424, nil, []string{FailedDependencyError}},
+		{"2", 404, nil, []string{"not found"}},
+		{"3", 424, nil, []string{FailedDependencyError}},
+	}, response.Results)
+
+	// failed rollbacks leave the operations applied
+	response = request.ProcessWithRollback(process, func(applied []Operation) error {
+		return errors.New("connection lost")
+	})
+	assert.Equal(t, Result{"1", 200, nil, nil}, response.Results[0])
+
+	// requests that aren't atomic are never rolled back
+	request.Atomic = false
+	response = request.ProcessWithRollback(process, func(applied []Operation) error {
+		t.Error("rollback should not be called")
+		return nil
+	})
+	assert.Equal(t, 3, len(response.Results))
+
+}
+
+func TestFromContext(t *testing.T) {
+
+	ctx := context_test.MakeTestContextWithFullDetails("http://goweb.org/people?atomic=true", "PATCH", `[{"id":"1","name":"Mat"},"2"]`)
+	ctx.HttpRequest().Header.Set("Content-Type", "application/json")
+
+	request, err := FromContext(ctx)
+	if assert.NoError(t, err) {
+		assert.True(t, request.Atomic)
+		assert.Equal(t, []string{"1", "2"}, request.IDs())
+		assert.Equal(t, "Mat", request.Operations[0].Data.Get("name").Str())
+	}
+
+	ctx = context_test.MakeTestContextWithFullDetails("http://goweb.org/people", "PATCH", `{"id":"1"}`)
+	ctx.HttpRequest().Header.Set("Content-Type", "application/json")
+
+	_, err = FromContext(ctx)
+	assert.Error(t, err, "Not an array")
+
+}
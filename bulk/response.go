@@ -0,0 +1,92 @@
+package bulk
+
+import (
+	"net/http"
+)
+
+// FailedDependencyError is the error reported for the operations of an atomic
+// request that has failed which weren't applied (or were rolled back), other than
+// the one that failed.
+const FailedDependencyError string = "not applied because another operation failed"
+
+// Result represents the outcome of a single operation.
+type Result struct {
+	// ID is the ID of the resource.
+	ID string
+	// Status is the HTTP status code for the operation.
+	Status int
+	// Data is optional data to return for the operation (i.e. the updated resource).
+	Data interface{}
+	// Errors are the errors that occurred, if any.
+	Errors []string
+}
+
+// Success makes a successful Result with the specified status code and optional data.
+func Success(id string, status int, data interface{}) Result {
+	return Result{ID: id, Status: status, Data: data}
+}
+
+// Failure makes a failed Result with the specified status code and error.
+func Failure(id string, status int, err error) Result {
+	return Result{ID: id, Status: status, Errors: []string{err.Error()}}
+}
+
+// failedDependency makes the Result for an operation of a failed atomic request.
+func failedDependency(id string) Result {
+	return Result{ID: id, Status: http.StatusFailedDependency, Errors: []string{FailedDependencyError}}
+}
+
+// Succeeded gets whether the operation succeeded, i.e. it has no errors and a
+// 2xx status code.
+func (r Result) Succeeded() bool {
+	return len(r.Errors) == 0 && r.Status >= 200 && r.Status <= 299
+}
+
+// Object gets the object to write in responses for the Result.
+func (r Result) Object() map[string]interface{} {
+
+	object := map[string]interface{}{IDField: r.ID, "status": r.Status}
+
+	if r.Data != nil {
+		object["data"] = r.Data
+	}
+	if len(r.Errors) > 0 {
+		object["errors"] = r.Errors
+	}
+
+	return object
+
+}
+
+// Response represents the outcome of a bulk request.
+type Response struct {
+	// Results are the results of each operation, in order.
+	Results []Result
+	// Atomic indicates whether the request was all or nothing.
+	Atomic bool
+}
+
+// Failed gets whether any of the operations failed.
+func (r *Response) Failed() bool {
+	for _, result := range r.Results {
+		if !result.Succeeded() {
+			return true
+		}
+	}
+	return false
+}
+
+// Status gets the HTTP status code to respond with, which is always 207 Multi-Status
+// as each Result has its own status.
+func (r *Response) Status() int {
+	return http.StatusMultiStatus
+}
+
+// Objects gets the objects to write in responses for each of the Results.
+func (r *Response) Objects() []interface{} {
+	objects := make([]interface{}, len(r.Results))
+	for i, result := range r.Results {
+		objects[i] = result.Object()
+	}
+	return objects
+}
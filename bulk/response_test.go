@@ -0,0 +1,31 @@
+package bulk
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestResult_Succeeded(t *testing.T) {
+
+	assert.True(t, Success("1", 200, nil).Succeeded())
+	assert.True(t, Success("1", 204, nil).Succeeded())
+	assert.False(t, Success("1", 404, nil).Succeeded())
+	assert.False(t, Failure("1", 200, errors.New("oops")).Succeeded())
+
+}
+
+func TestResponse_Objects(t *testing.T) {
+
+	response := &Response{Results: []Result{
+		Success("1", 200, map[string]interface{}{"name": "Mat"}),
+		Failure("2", 422, errors.New("name is required")),
+	}}
+
+	assert.Equal(t, 207, response.Status())
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "1", "status": 200, "data": map[string]interface{}{"name": "Mat"}},
+		map[string]interface{}{"id": "2", "status": 422, "errors": []string{"name is required"}},
+	}, response.Objects())
+
+}
@@ -3,4 +3,44 @@ package context
 const (
 	// DataKeyPathParameters represents the data key for URL parameter values.
 	DataKeyPathParameters string = "urlparams"
+
+	// DataKeyRoute represents the data key for the raw path pattern of the
+	// mapping that handled the request (i.e. "people/{id}").
+	DataKeyRoute string = "route"
+
+	// DataKeySpan represents the data key for the current tracing span when
+	// requests are being traced.  See the tracing package.
+	DataKeySpan string = "span"
+
+	// DataKeyRequestID represents the data key for the ID that correlates the
+	// request with responses, error pages and logs.  See
+	// handlers.RequestIDHandler.
+	DataKeyRequestID string = "requestid"
+
+	// DataKeyCSPNonce represents the data key for the per-request nonce to put
+	// in the nonce attribute of inline <script> and <style> tags.  See
+	// handlers.SecurityHeadersHandler.
+	DataKeyCSPNonce string = "cspnonce"
+
+	// DataKeyOriginalMethod represents the data key for the HTTP method the
+	// request was actually sent with, when it has been overridden.  See
+	// handlers.MethodOverridePolicy.
+	DataKeyOriginalMethod string = "originalmethod"
+
+	// DataKeyParentIDs represents the data key for the IDs of the parent
+	// resources of a nested controller action.  See handlers.ParentIDs.
+	DataKeyParentIDs string = "parentids"
+
+	// DataKeyAction represents the data key for the name of the controller
+	// action (i.e. "Create", "ReadMany" or the name of a custom action) that is
+	// handling the request.  See handlers.ActionName.
+	DataKeyAction string = "action"
+
+	// DataKeyListQueryOptions represents the data key for the *query.Options
+	// used to parse the ListQuery of requests.  See query.FromContext.
+	DataKeyListQueryOptions string = "listqueryoptions"
+
+	// DataKeyVersion represents the data key for the version of the API that the
+	// request is for.  See handlers.VersioningPolicy.
+	DataKeyVersion string = "version"
 )
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"github.com/stretchr/goweb/context"
+)
+
+// The names of the standard RESTful actions.
+const (
+	ActionCreate     string = "Create"
+	ActionRead       string = "Read"
+	ActionReadMany   string = "ReadMany"
+	ActionDelete     string = "Delete"
+	ActionDeleteMany string = "DeleteMany"
+	ActionUpdate     string = "Update"
+	ActionUpdateMany string = "UpdateMany"
+	ActionReplace    string = "Replace"
+	ActionHead       string = "Head"
+	ActionOptions    string = "Options"
+)
+
+// ActionScope describes whether a custom Action works on the whole collection of
+// resources, or on a single member of it.
+type ActionScope int8
+
+const (
+	// CollectionScope actions are mapped beneath the controller's path:
+	//     GET /orders/search
+	CollectionScope ActionScope = iota
+
+	// MemberScope actions are mapped beneath a single resource:
+	//     POST /orders/{id}/cancel
+	MemberScope
+)
+
+// Action describes a custom action that a controller provides in addition to the
+// standard RESTful ones.
+type Action struct {
+
+	// Name is the name of the action, such as "Cancel".
+	Name string
+
+	// Method is the HTTP method the action responds to.  It is required;
+	// MapController returns an error for actions without one.
+	Method string
+
+	// Path is the path of the action, relative to the collection or the member.
+	Path string
+
+	// Scope says whether the action is for the collection or a single member.
+	Scope ActionScope
+
+	// Handler is the func that performs the action.  It is required;
+	// MapController returns an error for actions without one.
+	//
+	// MemberScope actions can get the ID of the resource from the path parameter
+	// named by the IDParameterName of the handlers.ControllerMapping.  That is
+	// handlers.RestfulIDParameterName ("id" by default), unless the controller
+	// implements RestfulIDParameterNamer:
+	//     mapping, _ := goweb.MapControllerMapping("orders", ordersController)
+	//     ...
+	//     id := ctx.PathValue(mapping.IDParameterName)
+	Handler func(ctx context.Context) error
+
+	// Description is an optional human readable description of the action.
+	Description string
+}
+
+// RestfulActions represents a controller that provides custom actions.
+//
+// For example:
+//
+//     func (c *OrdersController) Actions() []controllers.Action {
+//       return []controllers.Action{
+//         {Name: "Cancel", Method: "POST", Path: "cancel", Scope: controllers.MemberScope, Handler: c.Cancel},
+//         {Name: "Search", Method: "GET", Path: "search", Scope: controllers.CollectionScope, Handler: c.Search},
+//       }
+//     }
+//
+// would map:
+//     POST /orders/{id}/cancel
+//     GET /orders/search
+type RestfulActions interface {
+	// Actions gets the custom actions of the controller.
+	Actions() []Action
+}
@@ -8,6 +8,21 @@ import (
 //
 // Before handlers will be mapped to any actions of this controller and will
 // be called before any of the main methods.
+//
+// Per-action hooks
+//
+// Controllers can also have hooks for specific actions, named after the action,
+// such as:
+//
+//     BeforeCreate(context.Context) error
+//     AfterDelete(context.Context) error
+//     BeforeCancel(context.Context) error // for a custom "Cancel" action
+//
+// Before an action, the Before handler is called, then any BeforeFilters, then
+// the action's own Before hook.  After an action, the action's own After hook is
+// called, then any AfterFilters, then the After handler.
+//
+// The name of the action is available to all of them with handlers.ActionName(ctx).
 type BeforeHandler interface {
 
 	// Before is called after any other mapped methods.
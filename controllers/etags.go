@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"github.com/stretchr/goweb/context"
+)
+
+// RestfulETagger represents a controller that knows the current version of its
+// resources, allowing clients to make conditional requests.
+//
+// When a controller implements RestfulETagger, Goweb checks the conditional
+// request headers before the Read, Update, Replace and Delete actions are run:
+//
+//     GET /resources/{id} with If-None-Match  -  304 Not Modified if the version matches
+//     PATCH, PUT or DELETE with If-Match      -  412 Precondition Failed unless it matches
+//
+// The ETag header is set on responses from Read automatically.  Other actions
+// (i.e. Update) can set the new version with handlers.SetETag.
+type RestfulETagger interface {
+	// ETag gets the current version of the resource with the specified ID, or an
+	// empty string if there is no such resource.
+	ETag(id string, ctx context.Context) (string, error)
+}
+
+// RestfulPreconditionRequirer represents a RestfulETagger that demands clients
+// say which version of a resource they are changing.
+//
+// If PreconditionRequired returns true, Update, Replace and Delete requests without
+// an If-Match header get a 428 Precondition Required response.
+type RestfulPreconditionRequirer interface {
+	// PreconditionRequired gets whether requests to change resources must have an
+	// If-Match header.
+	PreconditionRequired() bool
+}
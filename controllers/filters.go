@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"github.com/stretchr/goweb/context"
+)
+
+// Filter is a func that is called before or after some of a controller's actions.
+//
+// If Only is not empty, the filter is only called for the named actions.  The filter
+// is never called for the actions named in Except.
+type Filter struct {
+
+	// Func is the func to call.
+	Func func(ctx context.Context) error
+
+	// Only holds the names of the only actions the filter applies to.
+	Only []string
+
+	// Except holds the names of actions the filter does not apply to.
+	Except []string
+}
+
+// AppliesTo gets whether the filter should be called for the named action or not.
+func (f Filter) AppliesTo(actionName string) bool {
+
+	for _, except := range f.Except {
+		if except == actionName {
+			return false
+		}
+	}
+
+	if len(f.Only) == 0 {
+		return true
+	}
+
+	for _, only := range f.Only {
+		if only == actionName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BeforeFilterHandler represents a controller with filters to call before some of its
+// actions.
+//
+//     func (c *PeopleController) BeforeFilters() []controllers.Filter {
+//       return []controllers.Filter{
+//         {Func: c.RequireLogin, Except: []string{controllers.ActionRead, controllers.ActionReadMany}},
+//         {Func: c.LoadPerson, Only: []string{controllers.ActionRead, controllers.ActionUpdate}},
+//       }
+//     }
+type BeforeFilterHandler interface {
+
+	// BeforeFilters gets the filters to call before actions.
+	BeforeFilters() []Filter
+}
+
+// AfterFilterHandler represents a controller with filters to call after some of its
+// actions.
+type AfterFilterHandler interface {
+
+	// AfterFilters gets the filters to call after actions.
+	AfterFilters() []Filter
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFilter_AppliesTo(t *testing.T) {
+
+	f := Filter{}
+	assert.True(t, f.AppliesTo(ActionCreate))
+
+	f = Filter{Only: []string{ActionCreate, ActionUpdate}}
+	assert.True(t, f.AppliesTo(ActionCreate))
+	assert.True(t, f.AppliesTo(ActionUpdate))
+	assert.False(t, f.AppliesTo(ActionRead))
+
+	f = Filter{Except: []string{ActionRead}}
+	assert.True(t, f.AppliesTo(ActionCreate))
+	assert.False(t, f.AppliesTo(ActionRead))
+
+	f = Filter{Only: []string{ActionRead}, Except: []string{ActionRead}}
+	assert.False(t, f.AppliesTo(ActionRead))
+
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"strconv"
+	"sync"
+)
+
+// MemoryRepository is a Repository that keeps items in memory.
+//
+// It is useful for prototyping and writing tests.  IDs are assigned in
+// sequence, starting at "1".
+type MemoryRepository[T any] struct {
+	mutex  sync.RWMutex
+	items  map[string]T
+	ids    []string
+	lastID int
+}
+
+// NewMemoryRepository makes a new, empty, MemoryRepository.
+func NewMemoryRepository[T any]() *MemoryRepository[T] {
+	return &MemoryRepository[T]{items: make(map[string]T)}
+}
+
+// Get gets the item with the specified ID, or returns ErrNotFound.
+func (r *MemoryRepository[T]) Get(id string) (T, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return item, ErrNotFound
+	}
+	return item, nil
+}
+
+// List gets all the items, in the order in which they were created.
+func (r *MemoryRepository[T]) List() ([]T, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	items := make([]T, 0, len(r.ids))
+	for _, id := range r.ids {
+		items = append(items, r.items[id])
+	}
+	return items, nil
+}
+
+// Create stores a new item, and returns its ID.
+func (r *MemoryRepository[T]) Create(item T) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastID++
+	id := strconv.Itoa(r.lastID)
+	r.items[id] = item
+	r.ids = append(r.ids, id)
+	return id, nil
+}
+
+// Update stores the item with the specified ID, or returns ErrNotFound.
+func (r *MemoryRepository[T]) Update(id string, item T) error {
+	return r.Replace(id, item)
+}
+
+// Replace replaces the item with the specified ID, or returns ErrNotFound.
+func (r *MemoryRepository[T]) Replace(id string, item T) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.items[id]; !ok {
+		return ErrNotFound
+	}
+	r.items[id] = item
+	return nil
+}
+
+// Delete deletes the item with the specified ID, or returns ErrNotFound.
+func (r *MemoryRepository[T]) Delete(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.items[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.items, id)
+	for i, existing := range r.ids {
+		if existing == id {
+			r.ids = append(r.ids[:i], r.ids[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMemoryRepository_Interface(t *testing.T) {
+
+	assert.Implements(t, (*Repository[string])(nil), NewMemoryRepository[string]())
+
+}
+
+func TestMemoryRepository(t *testing.T) {
+
+	r := NewMemoryRepository[string]()
+
+	id1, _ := r.Create("one")
+	id2, _ := r.Create("two")
+	assert.Equal(t, "1", id1)
+	assert.Equal(t, "2", id2)
+
+	item, err := r.Get("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "one", item)
+
+	_, err = r.Get("3")
+	assert.Equal(t, ErrNotFound, err)
+
+	items, _ := r.List()
+	assert.Equal(t, []string{"one", "two"}, items)
+
+	assert.NoError(t, r.Update("1", "uno"))
+	assert.NoError(t, r.Replace("2", "dos"))
+	assert.Equal(t, ErrNotFound, r.Update("3", "tres"))
+	assert.Equal(t, ErrNotFound, r.Replace("3", "tres"))
+
+	items, _ = r.List()
+	assert.Equal(t, []string{"uno", "dos"}, items)
+
+	assert.NoError(t, r.Delete("1"))
+	assert.Equal(t, ErrNotFound, r.Delete("1"))
+
+	items, _ = r.List()
+	assert.Equal(t, []string{"dos"}, items)
+
+	// IDs are never reused
+	id3, _ := r.Create("three")
+	assert.Equal(t, "3", id3)
+
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned by Repositories when the requested item does not exist.
+// Repositories may wrap it (i.e. with fmt.Errorf and %w) to add details.
+var ErrNotFound = errors.New("controllers: item not found")
+
+// Repository represents a store of items of type T, that a Resource uses to
+// provide RESTful access to them.
+type Repository[T any] interface {
+
+	// Get gets the item with the specified ID, or returns ErrNotFound.
+	Get(id string) (T, error)
+
+	// List gets all the items.
+	List() ([]T, error)
+
+	// Create stores a new item, and returns its ID.
+	Create(item T) (string, error)
+
+	// Update stores the changes made to the item with the specified ID, or returns
+	// ErrNotFound.
+	Update(id string, item T) error
+
+	// Replace replaces the item with the specified ID, or returns ErrNotFound.
+	Replace(id string, item T) error
+
+	// Delete deletes the item with the specified ID, or returns ErrNotFound.
+	Delete(id string) error
+}
+
+// Validator represents an item that can check whether it is valid or not.
+//
+// Resources will not store items that are not valid.
+type Validator interface {
+
+	// Validate returns an error describing why the item is not valid, or nil.
+	Validate() error
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/responders"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Resource is a RESTful controller for items of type T, stored in a Repository.
+//
+// It implements RestfulCreator, RestfulReader, RestfulManyReader, RestfulUpdater,
+// RestfulReplacer, RestfulDeletor and RestfulExistenceChecker, so it can be mapped
+// (or nested) just like any other controller:
+//
+//     people := controllers.NewResource[Person](controllers.NewMemoryRepository[Person](), goweb.API)
+//     goweb.MapController("people", people)
+//
+// Request bodies are decoded into T using the codec for the request's Content-Type.
+// If T (or *T) implements Validator, invalid items are rejected with a 400 response.
+//
+// Responses are made with the APIResponder:
+//
+//     POST /people          201 Created, with a Location header
+//     GET /people           200 OK
+//     GET /people/{id}      200 OK, or 404 Not Found
+//     PATCH /people/{id}    200 OK, or 404 Not Found
+//     PUT /people/{id}      200 OK, or 404 Not Found
+//     DELETE /people/{id}   204 No Content, or 404 Not Found
+//
+// PATCH requests decode the body on top of a copy of the existing item, so only
+// the fields present in the body are changed, and the stored item is left alone
+// if the result isn't valid.
+type Resource[T any] struct {
+
+	// Repository is where the items are stored.
+	Repository Repository[T]
+
+	// API is the APIResponder used to respond.
+	API responders.APIResponder
+}
+
+// NewResource makes a new Resource for the items in the specified Repository.
+func NewResource[T any](repository Repository[T], api responders.APIResponder) *Resource[T] {
+	return &Resource[T]{Repository: repository, API: api}
+}
+
+// Create creates a new item from the request body.
+func (r *Resource[T]) Create(ctx context.Context) error {
+
+	var item T
+	if decodeErr := r.decode(ctx, &item); decodeErr != nil {
+		return r.API.RespondWithError(ctx, http.StatusBadRequest, decodeErr.Error())
+	}
+
+	id, createErr := r.Repository.Create(item)
+	if createErr != nil {
+		return createErr
+	}
+
+	if created, getErr := r.Repository.Get(id); getErr == nil {
+		item = created
+	}
+
+	ctx.HttpResponseWriter().Header().Set("Location", fmt.Sprintf("%s/%s", requestPath(ctx), url.PathEscape(id)))
+	return r.API.Respond(ctx, http.StatusCreated, item, nil)
+
+}
+
+// Read responds with the item with the specified ID.
+func (r *Resource[T]) Read(id string, ctx context.Context) error {
+
+	item, getErr := r.Repository.Get(id)
+	if getErr != nil {
+		return r.respondWithError(ctx, getErr)
+	}
+
+	return r.API.RespondWithData(ctx, item)
+
+}
+
+// ReadMany responds with all of the items.
+func (r *Resource[T]) ReadMany(ctx context.Context) error {
+
+	items, listErr := r.Repository.List()
+	if listErr != nil {
+		return listErr
+	}
+
+	return r.API.RespondWithData(ctx, items)
+
+}
+
+// Update changes the item with the specified ID with the fields in the request
+// body.
+func (r *Resource[T]) Update(id string, ctx context.Context) error {
+
+	stored, getErr := r.Repository.Get(id)
+	if getErr != nil {
+		return r.respondWithError(ctx, getErr)
+	}
+
+	// T may be a pointer (or map or slice) to the stored item, which mustn't
+	// change unless the updated item is valid
+	item := copyItem(stored)
+	if decodeErr := r.decode(ctx, &item); decodeErr != nil {
+		return r.API.RespondWithError(ctx, http.StatusBadRequest, decodeErr.Error())
+	}
+
+	if updateErr := r.Repository.Update(id, item); updateErr != nil {
+		return r.respondWithError(ctx, updateErr)
+	}
+
+	return r.API.RespondWithData(ctx, item)
+
+}
+
+// Replace replaces the item with the specified ID with the request body.
+func (r *Resource[T]) Replace(id string, ctx context.Context) error {
+
+	var item T
+	if decodeErr := r.decode(ctx, &item); decodeErr != nil {
+		return r.API.RespondWithError(ctx, http.StatusBadRequest, decodeErr.Error())
+	}
+
+	if replaceErr := r.Repository.Replace(id, item); replaceErr != nil {
+		return r.respondWithError(ctx, replaceErr)
+	}
+
+	return r.API.RespondWithData(ctx, item)
+
+}
+
+// Delete deletes the item with the specified ID.
+func (r *Resource[T]) Delete(id string, ctx context.Context) error {
+
+	if deleteErr := r.Repository.Delete(id); deleteErr != nil {
+		return r.respondWithError(ctx, deleteErr)
+	}
+
+	ctx.HttpResponseWriter().WriteHeader(http.StatusNoContent)
+	return nil
+
+}
+
+// Exists gets whether the item with the specified ID exists or not, so that
+// Resources can have other controllers nested beneath them.
+func (r *Resource[T]) Exists(id string, ctx context.Context) (bool, error) {
+
+	_, getErr := r.Repository.Get(id)
+
+	if errors.Is(getErr, ErrNotFound) {
+		return false, nil
+	}
+
+	return getErr == nil, getErr
+
+}
+
+// decode decodes the request body into the item, and validates it.
+func (r *Resource[T]) decode(ctx context.Context, item *T) error {
+
+	body, bodyErr := ctx.RequestBody()
+	if bodyErr != nil {
+		return bodyErr
+	}
+
+	codec, codecErr := ctx.CodecService().GetCodec(ctx.HttpRequest().Header.Get("Content-Type"))
+	if codecErr != nil {
+		return codecErr
+	}
+
+	if unmarshalErr := ctx.CodecService().UnmarshalWithCodec(codec, body, item); unmarshalErr != nil {
+		return unmarshalErr
+	}
+
+	if validator, ok := interface{}(item).(Validator); ok {
+		return validator.Validate()
+	}
+	if validator, ok := interface{}(*item).(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+// respondWithError responds with 404 Not Found for ErrNotFound (or errors that
+// wrap it), and returns any other errors.
+func (r *Resource[T]) respondWithError(ctx context.Context, err error) error {
+
+	if errors.Is(err, ErrNotFound) {
+		return r.API.RespondWithError(ctx, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	}
+
+	return err
+}
+
+// requestPath gets the (escaped) path of the request as the client made it,
+// without a trailing slash.  Unlike ctx.Path(), it still has any prefixes that
+// were removed before the path was matched, such as the version prefix removed
+// by handlers.VersionFromPath.
+func requestPath(ctx context.Context) string {
+
+	if requestURI := ctx.HttpRequest().RequestURI; strings.HasPrefix(requestURI, "/") {
+		if u, err := url.ParseRequestURI(requestURI); err == nil {
+			return strings.TrimRight(u.EscapedPath(), "/")
+		}
+	}
+
+	return "/" + ctx.Path().RawPath
+
+}
+
+// copyItem makes a deep copy of the item, so decoding onto the copy never
+// changes the original.  Unexported fields, which aren't decoded, are copied
+// as they are.
+func copyItem[T any](item T) T {
+	value := reflect.ValueOf(&item).Elem()
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(copyValue(value))
+	return copied.Interface().(T)
+}
+
+// copyValue makes a deep copy of the value.
+func copyValue(value reflect.Value) reflect.Value {
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(copyValue(value.Elem()))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(copyValue(value.Elem()))
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, copyValue(value.MapIndex(key)))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyValue(value.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyValue(value.Index(i)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(copyValue(value.Field(i)))
+			}
+		}
+		return copied
+	}
+
+	return value
+
+}
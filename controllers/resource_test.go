@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/responders"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testPerson struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (p testPerson) Validate() error {
+	if len(p.Name) == 0 {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func makeTestResource() (*Resource[testPerson], *MemoryRepository[testPerson]) {
+	repository := NewMemoryRepository[testPerson]()
+	api := responders.NewGowebAPIResponder(codecsservices.NewWebCodecService(), new(responders.GowebHTTPResponder))
+	return NewResource[testPerson](repository, api), repository
+}
+
+func TestResource_Interfaces(t *testing.T) {
+
+	resource, _ := makeTestResource()
+
+	assert.Implements(t, (*RestfulCreator)(nil), resource)
+	assert.Implements(t, (*RestfulReader)(nil), resource)
+	assert.Implements(t, (*RestfulManyReader)(nil), resource)
+	assert.Implements(t, (*RestfulUpdater)(nil), resource)
+	assert.Implements(t, (*RestfulReplacer)(nil), resource)
+	assert.Implements(t, (*RestfulDeletor)(nil), resource)
+	assert.Implements(t, (*RestfulExistenceChecker)(nil), resource)
+
+}
+
+func TestResource_Create(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	ctx := context_test.MakeTestContextWithFullDetails("http://stretchr.org/people", "POST", `{"name":"Mat","age":30}`)
+	ctx.HttpRequest().Header.Set("Content-Type", "application/json")
+
+	assert.NoError(t, resource.Create(ctx))
+
+	assert.Equal(t, http.StatusCreated, context_test.TestResponseWriter.StatusCode)
+	assert.Equal(t, "/people/1", context_test.TestResponseWriter.Header().Get("Location"))
+	assert.Equal(t, `{"d":{"name":"Mat","age":30},"s":201}`, context_test.TestResponseWriter.Output)
+
+	person, _ := repository.Get("1")
+	assert.Equal(t, testPerson{"Mat", 30}, person)
+
+}
+
+func TestResource_Create_OriginalPath(t *testing.T) {
+
+	resource, _ := makeTestResource()
+
+	// the path the client asked for, before a version prefix was removed
+	ctx := context_test.MakeTestContextWithFullDetails("http://stretchr.org/people", "POST", `{"name":"Mat","age":30}`)
+	ctx.HttpRequest().RequestURI = "/v2/people/?fields=name"
+
+	assert.NoError(t, resource.Create(ctx))
+	assert.Equal(t, "/v2/people/1", context_test.TestResponseWriter.Header().Get("Location"))
+
+}
+
+func TestResource_Create_Invalid(t *testing.T) {
+
+	resource, repository := makeTestResource()
+
+	ctx := context_test.MakeTestContextWithFullDetails("http://stretchr.org/people", "POST", `{"age":30}`)
+	assert.NoError(t, resource.Create(ctx))
+	assert.Equal(t, http.StatusBadRequest, context_test.TestResponseWriter.StatusCode)
+	assert.Contains(t, context_test.TestResponseWriter.Output, "name is required")
+
+	ctx = context_test.MakeTestContextWithFullDetails("http://stretchr.org/people", "POST", `not json`)
+	assert.NoError(t, resource.Create(ctx))
+	assert.Equal(t, http.StatusBadRequest, context_test.TestResponseWriter.StatusCode)
+
+	items, _ := repository.List()
+	assert.Equal(t, 0, len(items))
+
+}
+
+func TestResource_Read(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	repository.Create(testPerson{"Mat", 30})
+
+	ctx := context_test.MakeTestContextWithDetails("people/1", "GET")
+	assert.NoError(t, resource.Read("1", ctx))
+	assert.Equal(t, http.StatusOK, context_test.TestResponseWriter.StatusCode)
+	assert.Equal(t, `{"d":{"name":"Mat","age":30},"s":200}`, context_test.TestResponseWriter.Output)
+
+	ctx = context_test.MakeTestContextWithDetails("people/2", "GET")
+	assert.NoError(t, resource.Read("2", ctx))
+	assert.Equal(t, http.StatusNotFound, context_test.TestResponseWriter.StatusCode)
+
+}
+
+func TestResource_ReadMany(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	repository.Create(testPerson{"Mat", 30})
+	repository.Create(testPerson{"Tyler", 29})
+
+	ctx := context_test.MakeTestContextWithDetails("people", "GET")
+	assert.NoError(t, resource.ReadMany(ctx))
+	assert.Equal(t, http.StatusOK, context_test.TestResponseWriter.StatusCode)
+	assert.Equal(t, `{"d":[{"name":"Mat","age":30},{"name":"Tyler","age":29}],"s":200}`, context_test.TestResponseWriter.Output)
+
+}
+
+func TestResource_Update(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	repository.Create(testPerson{"Mat", 30})
+
+	ctx := context_test.MakeTestContextWithFullDetails("http://stretchr.org/people/1", "PATCH", `{"age":31}`)
+	assert.NoError(t, resource.Update("1", ctx))
+	assert.Equal(t, http.StatusOK, context_test.TestResponseWriter.StatusCode)
+
+	person, _ := repository.Get("1")
+	assert.Equal(t, testPerson{"Mat", 31}, person)
+
+	ctx = context_test.MakeTestContextWithFullDetails("http://stretchr.org/people/2", "PATCH", `{"age":31}`)
+	assert.NoError(t, resource.Update("2", ctx))
+	assert.Equal(t, http.StatusNotFound, context_test.TestResponseWriter.StatusCode)
+
+}
+
+func TestResource_Update_Invalid(t *testing.T) {
+
+	repository := NewMemoryRepository[*testPerson]()
+	api := responders.NewGowebAPIResponder(codecsservices.NewWebCodecService(), new(responders.GowebHTTPResponder))
+	resource := NewResource[*testPerson](repository, api)
+	repository.Create(&testPerson{"Mat", 30})
+
+	ctx := context_test.MakeTestContextWithFullDetails("http://stretchr.org/people/1", "PATCH", `{"name":"","age":31}`)
+	assert.NoError(t, resource.Update("1", ctx))
+	assert.Equal(t, http.StatusBadRequest, context_test.TestResponseWriter.StatusCode)
+
+	person, _ := repository.Get("1")
+	assert.Equal(t, &testPerson{"Mat", 30}, person, "Invalid updates don't change the stored item")
+
+	ctx = context_test.MakeTestContextWithFullDetails("http://stretchr.org/people/1", "PATCH", `{"age":31}`)
+	assert.NoError(t, resource.Update("1", ctx))
+	assert.Equal(t, http.StatusOK, context_test.TestResponseWriter.StatusCode)
+
+	person, _ = repository.Get("1")
+	assert.Equal(t, &testPerson{"Mat", 31}, person)
+
+}
+
+func TestCopyItem(t *testing.T) {
+
+	type nested struct {
+		Tags    []string
+		Scores  map[string]int
+		Parent  *testPerson
+		private *testPerson
+	}
+
+	original := &nested{[]string{"a"}, map[string]int{"a": 1}, &testPerson{"Mat", 30}, &testPerson{"Tyler", 29}}
+	copied := copyItem(original)
+
+	assert.Equal(t, original, copied)
+
+	copied.Tags[0] = "b"
+	copied.Scores["a"] = 2
+	copied.Parent.Name = "Ryan"
+	assert.Equal(t, "a", original.Tags[0])
+	assert.Equal(t, 1, original.Scores["a"])
+	assert.Equal(t, "Mat", original.Parent.Name)
+	assert.True(t, original.private == copied.private, "Unexported fields are copied as they are")
+
+}
+
+func TestResource_Replace(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	repository.Create(testPerson{"Mat", 30})
+
+	ctx := context_test.MakeTestContextWithFullDetails("http://stretchr.org/people/1", "PUT", `{"name":"Tyler"}`)
+	assert.NoError(t, resource.Replace("1", ctx))
+	assert.Equal(t, http.StatusOK, context_test.TestResponseWriter.StatusCode)
+
+	person, _ := repository.Get("1")
+	assert.Equal(t, testPerson{"Tyler", 0}, person)
+
+	ctx = context_test.MakeTestContextWithFullDetails("http://stretchr.org/people/2", "PUT", `{"name":"Tyler"}`)
+	assert.NoError(t, resource.Replace("2", ctx))
+	assert.Equal(t, http.StatusNotFound, context_test.TestResponseWriter.StatusCode)
+
+}
+
+func TestResource_Delete(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	repository.Create(testPerson{"Mat", 30})
+
+	ctx := context_test.MakeTestContextWithDetails("people/1", "DELETE")
+	assert.NoError(t, resource.Delete("1", ctx))
+	assert.Equal(t, http.StatusNoContent, context_test.TestResponseWriter.StatusCode)
+
+	exists, _ := resource.Exists("1", ctx)
+	assert.False(t, exists)
+
+	ctx = context_test.MakeTestContextWithDetails("people/1", "DELETE")
+	assert.NoError(t, resource.Delete("1", ctx))
+	assert.Equal(t, http.StatusNotFound, context_test.TestResponseWriter.StatusCode)
+
+}
+
+func TestResource_Exists(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	repository.Create(testPerson{"Mat", 30})
+	ctx := context_test.MakeTestContext()
+
+	exists, err := resource.Exists("1", ctx)
+	assert.True(t, exists)
+	assert.NoError(t, err)
+
+	exists, err = resource.Exists("2", ctx)
+	assert.False(t, exists)
+	assert.NoError(t, err)
+
+}
+
+// wrappingRepository wraps the errors of another Repository, like repositories
+// backed by databases often do.
+type wrappingRepository[T any] struct {
+	Repository[T]
+}
+
+func (r wrappingRepository[T]) Get(id string) (T, error) {
+	item, err := r.Repository.Get(id)
+	if err != nil {
+		return item, fmt.Errorf("getting %s: %w", id, err)
+	}
+	return item, nil
+}
+
+func TestResource_WrappedErrNotFound(t *testing.T) {
+
+	resource, repository := makeTestResource()
+	resource.Repository = wrappingRepository[testPerson]{repository}
+
+	ctx := context_test.MakeTestContextWithDetails("people/1", "GET")
+	assert.NoError(t, resource.Read("1", ctx))
+	assert.Equal(t, http.StatusNotFound, context_test.TestResponseWriter.StatusCode)
+
+	exists, err := resource.Exists("1", ctx)
+	assert.False(t, exists)
+	assert.NoError(t, err)
+
+}
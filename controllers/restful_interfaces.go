@@ -11,6 +11,27 @@ type RestfulController interface {
 	Path() string
 }
 
+// RestfulIDParameterNamer represents a controller that chooses the name of
+// the path parameter that holds the ID of its resources.
+//
+// This is mostly useful for nested controllers, where each level needs a
+// different name:
+//     GET /people/{personId}/books/{bookId}
+type RestfulIDParameterNamer interface {
+	// IDParameterName gets the name of the ID path parameter.
+	IDParameterName() string
+}
+
+// RestfulExistenceChecker represents a controller that can say whether a single
+// resource exists or not.
+//
+// When other controllers are nested beneath it, Exists is called before the nested
+// controller's actions, and a 404 response is written if the resource doesn't exist.
+type RestfulExistenceChecker interface {
+	// Exists gets whether the resource with the specified ID exists.
+	Exists(id string, ctx context.Context) (bool, error)
+}
+
 /*
   RESTful actions
 */
@@ -55,6 +76,9 @@ type RestfulDeletor interface {
 //
 // This is usually mapped to the following kind of request:
 //     DELETE /resources
+//
+// Use bulk.FromContext to get the IDs of the resources to delete, and
+// responders.RespondWithBulk to report the outcome of each.
 type RestfulManyDeleter interface {
 	// DeleteMany deletes many resources.
 	DeleteMany(ctx context.Context) error
@@ -82,6 +106,9 @@ type RestfulReplacer interface {
 //
 // This is usually mapped to the following kind of request:
 //     PATCH /resources
+//
+// Use bulk.FromContext to get the changes to make to each resource, and
+// responders.RespondWithBulk to report the outcome of each.
 type RestfulManyUpdater interface {
 	// UpdateMany updates many resources at once.
 	UpdateMany(ctx context.Context) error
@@ -102,6 +129,10 @@ type RestfulOptions interface {
 // This is usually mapped to the following kind of requests:
 //     HEAD /resources
 //     HEAD /resources/{id}
+//
+// Controllers don't need to implement RestfulHead to answer HEAD requests, as
+// the HttpHandler will use the Read and ReadMany actions (without sending the
+// body) unless its AutomaticHead field is false.
 type RestfulHead interface {
 	// Head gets the headers only for the request.
 	Head(ctx context.Context) error
@@ -77,6 +77,13 @@
 //
 //     *** - Three `*`'s matches anything in this segment, and any subsequent segments.
 //           For example, `/people/***` would match `/people`, `/people/123` and `/people/123/books/456`.
+//           Name it (i.e. `***rest` or `{rest...}`) to make whatever it matched, such as
+//           `123/books/456`, available via the `context.PathParam` method.  Catch-alls can
+//           also go in the middle of paths, i.e. `/files/***path/edit`.
+//
+// Paths are matched case insensitively, and trailing and repeated slashes are ignored, so
+// `/People/` matches `people`.  Set CaseSensitive on the HttpHandler to match case, and set a
+// handlers.CanonicalPathPolicy to redirect clients to one form of each path.
 //
 // For some real examples of mapping paths, see the goweb.Map function, or check out the
 // example_webapp in the code.
@@ -106,9 +113,18 @@
 //
 //     uri?always200=true
 //
-// Additionally, if you need to override the HTTP Method used, simply pass it in a similar manner:
+// Additionally, if you need to override the HTTP Method used, allow it with a MethodOverridePolicy:
+//
+//     policy := handlers.NewMethodOverridePolicy(handlers.MethodOverrideQuery)
+//     policy.OverridableMethods = []string{"GET"}
+//     policy.AllowedMethods = []string{"POST", "PUT", "DELETE"}
+//     goweb.DefaultHttpHandler().MethodOverridePolicy = policy
+//
+// and then simply pass it in a similar manner:
+//
+//     uri?method=POST
 //
-//     uri?method=GET
+// Only do this if you understand the risks; it allows a simple link to make changes.
 //
 // Changes from Goweb 1
 //
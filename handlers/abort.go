@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+)
+
+// dataKeyAborted is the data key that indicates no more handlers should be
+// run for the request.
+const dataKeyAborted string = "aborted"
+
+// Abort stops any more handlers (in any of the pipes) from being run for the
+// request in the specified context.
+//
+// Use Abort in a pre handler that has responded to the request itself, and
+// doesn't want the request to be processed any further, i.e. because the
+// request was invalid, or the response was found in a cache.
+func Abort(ctx context.Context) {
+	ctx.Data().Set(dataKeyAborted, true)
+}
+
+// IsAborted gets whether Abort has been called for the request in the specified
+// context.
+func IsAborted(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	return ctx.Data().Get(dataKeyAborted).Bool()
+}
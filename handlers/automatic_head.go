@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/http"
+	nethttp "net/http"
+	"strconv"
+)
+
+// dataKeyAutomaticHead is the data key that indicates a HEAD request is being
+// answered by the handlers mapped for GET.
+const dataKeyAutomaticHead string = "automatichead"
+
+// IsAutomaticHead gets whether the request in the specified context is a HEAD
+// request that is being answered by the handlers mapped for GET.
+func IsAutomaticHead(ctx context.Context) bool {
+	return ctx.Data().Get(dataKeyAutomaticHead).Bool()
+}
+
+// headResponseWriter is an http.ResponseWriter that discards the body of a
+// response, keeping the headers and working out the Content-Length.
+//
+// Because the body isn't sent, writing the headers is deferred until finish
+// is called, so the Content-Length is known.
+type headResponseWriter struct {
+	nethttp.ResponseWriter
+	status int
+	length int
+}
+
+// newHeadResponseWriter makes a new headResponseWriter that writes the headers
+// to the specified http.ResponseWriter.
+func newHeadResponseWriter(responseWriter nethttp.ResponseWriter) *headResponseWriter {
+	return &headResponseWriter{ResponseWriter: responseWriter}
+}
+
+// WriteHeader remembers the status code, which will be written by finish.
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write counts the bytes but discards them.
+func (w *headResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = nethttp.StatusOK
+	}
+	w.length += len(data)
+	return len(data), nil
+}
+
+// finish writes the headers, including the Content-Length unless it has
+// already been set.
+func (w *headResponseWriter) finish() {
+
+	if w.status == 0 {
+		w.status = nethttp.StatusOK
+	}
+
+	header := w.ResponseWriter.Header()
+	if len(header.Get("Content-Length")) == 0 && bodyAllowedForStatus(w.status) {
+		header.Set("Content-Length", strconv.Itoa(w.length))
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+}
+
+// bodyAllowedForStatus gets whether responses with the specified status code
+// may have a body, and therefore a Content-Length.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == nethttp.StatusNoContent, status == nethttp.StatusNotModified:
+		return false
+	}
+	return true
+}
+
+// handlesHeadExplicitly gets whether a PathMatchHandler mapped specifically
+// for HEAD (i.e. not just for any method) will handle the request in the
+// specified context.
+func handlesHeadExplicitly(pipe Pipe, ctx context.Context) (bool, error) {
+
+	for _, handler := range pipe {
+
+		switch handler := handler.(type) {
+		case Pipe:
+			if explicit, err := handlesHeadExplicitly(handler, ctx); explicit || err != nil {
+				return explicit, err
+			}
+		case *PathMatchHandler:
+			if !containsMethod(handler.HttpMethods, http.MethodHead) {
+				continue
+			}
+			if willHandle, err := handler.WillHandle(ctx); willHandle || err != nil {
+				return willHandle, err
+			}
+		}
+
+	}
+
+	return false, nil
+
+}
+
+// withHead gets the specified list of methods with HEAD added after GET, unless
+// it is already there or the HttpHandler doesn't answer HEAD automatically.
+func (h *HttpHandler) withHead(methods []string) []string {
+
+	if !h.AutomaticHead || containsMethod(methods, http.MethodHead) {
+		return methods
+	}
+
+	for i, method := range methods {
+		if method == http.MethodGet {
+			withHead := make([]string, 0, len(methods)+1)
+			withHead = append(withHead, methods[:i+1]...)
+			withHead = append(withHead, http.MethodHead)
+			return append(withHead, methods[i+1:]...)
+		}
+	}
+
+	return methods
+
+}
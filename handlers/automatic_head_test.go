@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAutomaticHead(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	assert.True(t, h.AutomaticHead)
+
+	var automatic bool
+	h.Map("GET", "people/{id}", func(c context.Context) error {
+		automatic = IsAutomaticHead(c)
+		c.HttpResponseWriter().Header().Set("X-Person", c.PathValue("id"))
+		c.HttpResponseWriter().WriteHeader(203)
+		c.HttpResponseWriter().Write([]byte("Mat Ryer"))
+		return nil
+	})
+
+	response := serveTestRequest(h, "HEAD", "http://goweb.org/people/123")
+	assert.True(t, automatic)
+	assert.Equal(t, 203, response.StatusCode)
+	assert.Equal(t, "123", response.Header().Get("X-Person"))
+	assert.Equal(t, "8", response.Header().Get("Content-Length"))
+	assert.Equal(t, "", response.Output)
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.False(t, automatic)
+	assert.Equal(t, "Mat Ryer", response.Output)
+
+}
+
+func TestAutomaticHead_KeepsContentLength(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("GET", "file", func(c context.Context) error {
+		c.HttpResponseWriter().Header().Set("Content-Length", "100")
+		c.HttpResponseWriter().Write([]byte("partial"))
+		return nil
+	})
+	h.Map("GET", "nothing", func(c context.Context) error {
+		c.HttpResponseWriter().WriteHeader(204)
+		return nil
+	})
+
+	response := serveTestRequest(h, "HEAD", "http://goweb.org/file")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "100", response.Header().Get("Content-Length"))
+
+	response = serveTestRequest(h, "HEAD", "http://goweb.org/nothing")
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Equal(t, "", response.Header().Get("Content-Length"))
+
+}
+
+func TestAutomaticHead_ExplicitHeadMapping(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var called string
+	h.Map("GET", "people", func(c context.Context) error {
+		called = "get"
+		return nil
+	})
+	h.Map("HEAD", "people", func(c context.Context) error {
+		called = "head"
+		return nil
+	})
+
+	serveTestRequest(h, "HEAD", "http://goweb.org/people")
+	assert.Equal(t, "head", called)
+
+}
+
+func TestAutomaticHead_Disabled(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.AutomaticHead = false
+
+	var called bool
+	h.Map("GET", "people", func(c context.Context) error {
+		called = true
+		return nil
+	})
+
+	serveTestRequest(h, "HEAD", "http://goweb.org/people")
+	assert.False(t, called)
+
+}
+
+func TestAutomaticHead_Allow(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", new(testPeopleController))
+
+	response := serveTestRequest(h, "OPTIONS", "http://goweb.org/people/123")
+	assert.Equal(t, "GET,HEAD,OPTIONS", response.Header().Get("Allow"))
+
+	response = serveTestRequest(h, "HEAD", "http://goweb.org/people/123")
+	assert.Equal(t, "", response.Output)
+
+	h = NewHttpHandler(codecsservices.NewWebCodecService())
+	h.AutomaticHead = false
+	h.MapController("people", new(testPeopleController))
+
+	response = serveTestRequest(h, "OPTIONS", "http://goweb.org/people/123")
+	assert.Equal(t, "GET,OPTIONS", response.Header().Get("Allow"))
+
+}
+
+func TestWithHead(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	assert.Equal(t, []string{"POST", "GET", "HEAD", "OPTIONS"}, h.withHead([]string{"POST", "GET", "OPTIONS"}))
+	assert.Equal(t, []string{"GET", "HEAD", "OPTIONS"}, h.withHead([]string{"GET", "HEAD", "OPTIONS"}))
+	assert.Equal(t, []string{"POST", "OPTIONS"}, h.withHead([]string{"POST", "OPTIONS"}))
+
+	h.AutomaticHead = false
+	assert.Equal(t, []string{"GET", "OPTIONS"}, h.withHead([]string{"GET", "OPTIONS"}))
+
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+	"net/http"
+	"strings"
+)
+
+// TrailingSlash describes what a CanonicalPathPolicy does with the slash at the
+// end of paths.
+type TrailingSlash int
+
+const (
+	// TrailingSlashIgnore leaves paths with or without a trailing slash alone.
+	TrailingSlashIgnore TrailingSlash = iota
+
+	// TrailingSlashRemove redirects /people/ to /people.
+	TrailingSlashRemove
+
+	// TrailingSlashAdd redirects /people to /people/.  Paths whose last segment
+	// has a file extension, such as /people.json, are left alone.
+	TrailingSlashAdd
+)
+
+// CanonicalPathPolicy describes the canonical form of paths, and redirects
+// requests for other forms of a path to it.  Goweb matches /People/, /people
+// and //people the same way, so without a policy the same resource has many
+// URLs, which is bad for caches and search engines.
+//
+// Paths are only redirected if a policy is set on the HttpHandler:
+//
+//     handler.CanonicalPathPolicy = handlers.NewCanonicalPathPolicy()
+//
+// Trailing slashes are left alone for requests handled by MapStatic or
+// MapStaticFile, as http.ServeFile redirects requests for directories to add one
+// (and for files to remove one), which would otherwise never stop redirecting.
+//
+// The query string is kept when requests are redirected.  Redirected requests
+// are not given to any handlers, although Observers are still told about them.
+type CanonicalPathPolicy struct {
+
+	// TrailingSlash is what to do with the slash at the end of paths.  The root
+	// path (/) is always left alone.
+	TrailingSlash TrailingSlash
+
+	// CollapseSlashes indicates whether repeated slashes (i.e. /people//123)
+	// should be collapsed into one.
+	CollapseSlashes bool
+
+	// Lowercase indicates whether paths should be lowercase.  Be careful; this
+	// also lowercases the values in paths, such as IDs.
+	Lowercase bool
+
+	// RedirectStatus is the status code to redirect with.  If zero (the default),
+	// GET and HEAD requests are redirected with 301 Moved Permanently, and others
+	// with 308 Permanent Redirect, so clients repeat them with the same method
+	// and body.
+	RedirectStatus int
+}
+
+// NewCanonicalPathPolicy makes a new CanonicalPathPolicy that removes trailing
+// slashes and collapses repeated slashes.
+func NewCanonicalPathPolicy() *CanonicalPathPolicy {
+	return &CanonicalPathPolicy{
+		TrailingSlash:   TrailingSlashRemove,
+		CollapseSlashes: true}
+}
+
+// Canonical gets the canonical form of the specified (escaped) path.
+//
+// Whatever the policy, the canonical path starts with exactly one slash, so
+// redirects can never take clients to another host (i.e. //example.com).
+func (p *CanonicalPathPolicy) Canonical(path string) string {
+
+	if p.CollapseSlashes {
+		for strings.Contains(path, "//") {
+			path = strings.Replace(path, "//", "/", -1)
+		}
+	}
+
+	if p.Lowercase {
+		path = strings.ToLower(path)
+	}
+
+	// the root path can't lose or gain a slash
+	if len(strings.Trim(path, "/")) > 0 {
+		switch p.TrailingSlash {
+		case TrailingSlashRemove:
+			path = strings.TrimRight(path, "/")
+		case TrailingSlashAdd:
+			lastSegment := path[strings.LastIndex(path, "/")+1:]
+			if len(lastSegment) > 0 && !strings.Contains(lastSegment, ".") {
+				path = path + "/"
+			}
+		}
+	}
+
+	return "/" + strings.TrimLeft(path, "/")
+
+}
+
+// redirectFor gets the URL the request should be redirected to, or false if it
+// is already for the canonical path.
+func (p *CanonicalPathPolicy) redirectFor(request *http.Request) (string, bool) {
+
+	// i.e. OPTIONS *
+	path := request.URL.EscapedPath()
+	if !strings.HasPrefix(path, "/") {
+		return "", false
+	}
+
+	canonical := p.Canonical(path)
+	if canonical == path {
+		return "", false
+	}
+
+	if len(request.URL.RawQuery) > 0 {
+		canonical = canonical + "?" + request.URL.RawQuery
+	}
+
+	return canonical, true
+
+}
+
+// ignoringTrailingSlash gets a copy of the policy that leaves trailing slashes
+// alone.
+func (p *CanonicalPathPolicy) ignoringTrailingSlash() *CanonicalPathPolicy {
+	policy := *p
+	policy.TrailingSlash = TrailingSlashIgnore
+	return &policy
+}
+
+// servesFiles gets whether a handler mapped by MapStatic or MapStaticFile in
+// the pipe will handle the request in the specified context.
+func servesFiles(pipe Pipe, ctx context.Context) (bool, error) {
+
+	for _, handler := range pipe {
+
+		switch handler := handler.(type) {
+		case Pipe:
+			if static, err := servesFiles(handler, ctx); static || err != nil {
+				return static, err
+			}
+		case *PathMatchHandler:
+			if !handler.servesFiles {
+				continue
+			}
+			if willHandle, err := handler.WillHandle(ctx); willHandle || err != nil {
+				return willHandle, err
+			}
+		}
+
+	}
+
+	return false, nil
+
+}
+
+// redirect redirects the client to the canonical URL.
+func (p *CanonicalPathPolicy) redirect(ctx context.Context, canonicalURL string) {
+
+	status := p.RedirectStatus
+	if status == 0 {
+		switch ctx.MethodString() {
+		case http.MethodGet, http.MethodHead:
+			status = http.StatusMovedPermanently
+		default:
+			status = http.StatusPermanentRedirect
+		}
+	}
+
+	ctx.HttpResponseWriter().Header().Set("Location", canonicalURL)
+	ctx.HttpResponseWriter().WriteHeader(status)
+
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCanonicalPathPolicy_Canonical(t *testing.T) {
+
+	policy := NewCanonicalPathPolicy()
+
+	assert.Equal(t, "/people", policy.Canonical("/people/"))
+	assert.Equal(t, "/people/123", policy.Canonical("/people//123///"))
+	assert.Equal(t, "/People", policy.Canonical("/People"))
+	assert.Equal(t, "/", policy.Canonical("/"))
+	assert.Equal(t, "/", policy.Canonical("//"))
+	assert.Equal(t, "/files/a%2Fb", policy.Canonical("/files/a%2Fb/"))
+
+	policy = &CanonicalPathPolicy{TrailingSlash: TrailingSlashAdd, Lowercase: true}
+
+	assert.Equal(t, "/people/", policy.Canonical("/People"))
+	assert.Equal(t, "/people/me.json", policy.Canonical("/People/me.json"))
+	assert.Equal(t, "/people//123/", policy.Canonical("/people//123"), "Slashes are only collapsed if asked")
+	assert.Equal(t, "/evil/", policy.Canonical("//evil"), "Paths never start with two slashes")
+
+}
+
+func TestHttpHandler_CanonicalPathPolicy(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.CanonicalPathPolicy = NewCanonicalPathPolicy()
+
+	var events []string
+	h.AddObserver(&testObserver{name: "observer", events: &events})
+
+	var called bool
+	h.Map("people/{id}", func(c context.Context) error {
+		called = true
+		return nil
+	})
+
+	response := serveTestRequest(h, "GET", "http://goweb.org/people//123/?fields=name")
+	assert.Equal(t, http.StatusMovedPermanently, response.StatusCode)
+	assert.Equal(t, "/people/123?fields=name", response.Header().Get("Location"))
+	assert.False(t, called, "Redirected requests aren't handled")
+	assert.Equal(t, []string{"observer started", "observer finished"}, events)
+
+	response = serveTestRequest(h, "POST", "http://goweb.org/people/123/")
+	assert.Equal(t, http.StatusPermanentRedirect, response.StatusCode)
+	assert.Equal(t, "/people/123", response.Header().Get("Location"))
+
+	h.CanonicalPathPolicy.RedirectStatus = http.StatusFound
+	response = serveTestRequest(h, "POST", "http://goweb.org/people/123/")
+	assert.Equal(t, http.StatusFound, response.StatusCode)
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.Equal(t, "", response.Header().Get("Location"))
+	assert.True(t, called)
+
+}
+
+func TestHttpHandler_CanonicalPathPolicy_Static(t *testing.T) {
+
+	systemPath, _ := ioutil.TempDir("", "goweb-static")
+	defer os.RemoveAll(systemPath)
+	os.MkdirAll(filepath.Join(systemPath, "docs"), 0755)
+	ioutil.WriteFile(filepath.Join(systemPath, "docs", "readme.txt"), []byte("hello"), 0644)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.CanonicalPathPolicy = NewCanonicalPathPolicy()
+	h.MapStatic("/static", systemPath)
+	h.Map("people", func(c context.Context) error {
+		return nil
+	})
+
+	// ServeFile adds the slash to directories, which the policy leaves alone
+	response := serveTestRequest(h, "GET", "http://goweb.org/static/docs")
+	assert.Equal(t, http.StatusMovedPermanently, response.StatusCode)
+	assert.Equal(t, "docs/", response.Header().Get("Location"))
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/static/docs/")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Output, "readme.txt")
+
+	// repeated slashes are still collapsed
+	response = serveTestRequest(h, "GET", "http://goweb.org/static//docs/")
+	assert.Equal(t, "/static/docs/", response.Header().Get("Location"))
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/people/")
+	assert.Equal(t, "/people", response.Header().Get("Location"))
+
+}
+
+func TestHttpHandler_CaseSensitive(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.CaseSensitive = true
+
+	var called string
+	h.Map("GET", "People", func(c context.Context) error {
+		called = "People"
+		return nil
+	})
+	h.Map("GET", "people", func(c context.Context) error {
+		called = "people"
+		return nil
+	})
+
+	assert.Empty(t, h.Conflicts(), "People and people are different paths")
+
+	serveTestRequest(h, "GET", "http://goweb.org/people")
+	assert.Equal(t, "people", called)
+
+	serveTestRequest(h, "GET", "http://goweb.org/People")
+	assert.Equal(t, "People", called)
+
+	called = ""
+	serveTestRequest(h, "GET", "http://goweb.org/PEOPLE")
+	assert.Equal(t, "", called)
+
+}
+
+func TestHttpHandler_EncodedSlashes(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var name string
+	h.Map("GET", "files/{name}", func(c context.Context) error {
+		name = c.PathValue("name")
+		return nil
+	})
+
+	name = ""
+	serveTestRequest(h, "GET", "http://goweb.org/files/reports%2F2013.pdf")
+	assert.Equal(t, "", name, "Encoded slashes are decoded unless asked")
+
+	h.EncodedSlashes = true
+
+	serveTestRequest(h, "GET", "http://goweb.org/files/reports%2F2013.pdf")
+	assert.Equal(t, "reports/2013", name)
+
+	serveTestRequest(h, "GET", "http://goweb.org/files/..%2F..%2Fetc%2Fpasswd")
+	assert.Equal(t, "..%2F..%2Fetc%2Fpasswd", name, "Segments with dot segments stay escaped")
+
+}
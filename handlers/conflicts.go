@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/stretchr/goweb/paths"
+	"log"
+	"strings"
+)
+
+// ConflictKind describes how a mapping conflicts with one mapped before it.
+type ConflictKind string
+
+const (
+	// ConflictUnreachable means every request the mapping would handle (for
+	// some or all of its methods) is handled by an earlier mapping instead.
+	ConflictUnreachable ConflictKind = "unreachable"
+
+	// ConflictAmbiguous means some requests match both mappings, but neither
+	// pattern is more specific than the other, so the earlier mapping handles
+	// them because it is tried first.
+	ConflictAmbiguous ConflictKind = "ambiguous"
+)
+
+// Conflict describes a mapping that conflicts with one that is tried before it.
+//
+// Only mappings made with Map are checked, as they stop the pipe once they have
+// handled a request.  Mappings with MatcherFuncs are ignored, because they may
+// decide to let requests through.
+type Conflict struct {
+
+	// Kind is how the mappings conflict.
+	Kind ConflictKind
+
+	// Handler is the mapping that is tried later.
+	Handler *PathMatchHandler
+
+	// Earlier is the mapping that is tried first.  Unless the HttpHandler orders
+	// handlers by specificity, it is the one that was mapped first.
+	Earlier *PathMatchHandler
+
+	// Methods are the HTTP methods the conflict is about, or empty for all methods.
+	Methods []string
+}
+
+// Error gets a description of the conflict.
+func (c *Conflict) Error() string {
+
+	methods := "Requests"
+	if len(c.Methods) > 0 {
+		methods = fmt.Sprintf("%s requests", strings.Join(c.Methods, "|"))
+	}
+
+	switch c.Kind {
+	case ConflictUnreachable:
+		return fmt.Sprintf("goweb: %s for %s will never be handled by it, because %s is tried first and handles them all.", methods, conflictDescription(c.Handler), conflictDescription(c.Earlier))
+	default:
+		return fmt.Sprintf("goweb: %s that match both %s and %s will be handled by the one tried first, as neither is more specific.", methods, conflictDescription(c.Earlier), conflictDescription(c.Handler))
+	}
+
+}
+
+// conflictDescription describes a mapping in a Conflict.
+func conflictDescription(handler *PathMatchHandler) string {
+	if len(handler.Name) > 0 {
+		return fmt.Sprintf("%q (%s)", handler.Name, handler.PathPattern.RawPath)
+	}
+	return handler.PathPattern.RawPath
+}
+
+// Conflicts gets the conflicts that were found when mappings were made.
+func (h *HttpHandler) Conflicts() []*Conflict {
+	return h.conflicts
+}
+
+// checkConflicts checks the new handler, which is about to be put at the
+// specified index of the processing pipe, against the handlers already in it.
+// Conflicts are logged and remembered, unless StrictMapping is set, in which case
+// the first conflict is returned as an error.
+func (h *HttpHandler) checkConflicts(handler Handler, index int) error {
+
+	pathMatchHandler, ok := handler.(*PathMatchHandler)
+	if !ok {
+		return nil
+	}
+
+	var conflicts []*Conflict
+	for existingIndex, existing := range h.HandlersPipe() {
+		if existing, ok := existing.(*PathMatchHandler); ok {
+
+			// the handlers after it are tried later
+			var conflict *Conflict
+			if existingIndex < index {
+				conflict = conflictBetween(existing, pathMatchHandler)
+			} else {
+				conflict = conflictBetween(pathMatchHandler, existing)
+			}
+
+			if conflict != nil {
+				conflicts = append(conflicts, conflict)
+			}
+
+		}
+	}
+
+	if len(conflicts) > 0 && h.StrictMapping {
+		return conflicts[0]
+	}
+
+	for _, conflict := range conflicts {
+		log.Print(conflict.Error())
+	}
+	h.conflicts = append(h.conflicts, conflicts...)
+
+	return nil
+
+}
+
+// conflictBetween gets the Conflict between a handler and one that is tried
+// before it, or nil if they don't conflict.
+func conflictBetween(existing, handler *PathMatchHandler) *Conflict {
+
+	if !existing.BreakCurrentPipeline || len(existing.MatcherFuncs) > 0 {
+		return nil
+	}
+	if existing.PathPattern == nil || handler.PathPattern == nil {
+		return nil
+	}
+
+	// handlers for different versions never conflict
+	if len(existing.Version) > 0 && existing.Version != handler.Version {
+		return nil
+	}
+
+	// nor do handlers for hosts that can't be the same
+	if !hostsOverlap(existing.HostPattern, handler.HostPattern) {
+		return nil
+	}
+
+	methods, sharesMethods := sharedMethods(existing.HttpMethods, handler.HttpMethods)
+	if !sharesMethods {
+		return nil
+	}
+
+	if hostsCover(existing.HostPattern, handler.HostPattern) && existing.PathPattern.Covers(handler.PathPattern) {
+		return &Conflict{Kind: ConflictUnreachable, Handler: handler, Earlier: existing, Methods: methods}
+	}
+
+	// earlier mappings that are more specific are fine, i.e. people/me before
+	// people/{id}, or api.example.com before {tenant}.example.com
+	handlerCovers := hostsCover(handler.HostPattern, existing.HostPattern) && handler.PathPattern.Covers(existing.PathPattern)
+	if len(handler.MatcherFuncs) == 0 && !handlerCovers && existing.PathPattern.Overlaps(handler.PathPattern) {
+		return &Conflict{Kind: ConflictAmbiguous, Handler: handler, Earlier: existing, Methods: methods}
+	}
+
+	return nil
+
+}
+
+// hostsCover gets whether every host matched by the other host pattern is also
+// matched by the first, where nil matches all hosts.
+func hostsCover(hostPattern, otherHostPattern *paths.HostPattern) bool {
+	switch {
+	case hostPattern == nil:
+		return true
+	case otherHostPattern == nil:
+		return false
+	}
+	return hostPattern.Covers(otherHostPattern)
+}
+
+// hostsOverlap gets whether there are hosts that both host patterns match, where
+// nil matches all hosts.
+func hostsOverlap(hostPattern, otherHostPattern *paths.HostPattern) bool {
+	if hostPattern == nil || otherHostPattern == nil {
+		return true
+	}
+	return hostPattern.Overlaps(otherHostPattern)
+}
+
+// sharedMethods gets the methods that both lists of methods match, where an
+// empty list matches all methods.
+func sharedMethods(methods, otherMethods []string) ([]string, bool) {
+
+	switch {
+	case len(methods) == 0:
+		return otherMethods, true
+	case len(otherMethods) == 0:
+		return methods, true
+	}
+
+	var shared []string
+	for _, method := range otherMethods {
+		if containsMethod(methods, method) {
+			shared = append(shared, method)
+		}
+	}
+
+	return shared, len(shared) > 0
+
+}
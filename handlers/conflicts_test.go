@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func noopHandler(c context.Context) error {
+	return nil
+}
+
+func TestMap_Conflicts(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	h.Map("GET", "people/me", noopHandler)
+	h.Map("GET", "people/{id}", noopHandler)
+	assert.Empty(t, h.Conflicts(), "More specific mappings first is fine")
+
+	h.Map("people/***", noopHandler, Name("people"))
+	_, err := h.Map([]string{"GET", "POST"}, "people/{id}/photo", noopHandler)
+	assert.NoError(t, err)
+
+	if assert.Equal(t, 1, len(h.Conflicts())) {
+		conflict := h.Conflicts()[0]
+		assert.Equal(t, ConflictUnreachable, conflict.Kind)
+		assert.Equal(t, "people/***", conflict.Earlier.PathPattern.RawPath)
+		assert.Equal(t, "people/{id}/photo", conflict.Handler.PathPattern.RawPath)
+		assert.Equal(t, []string{"GET", "POST"}, conflict.Methods)
+		assert.Equal(t, `goweb: GET|POST requests for people/{id}/photo will never be handled by it, because "people" (people/***) is tried first and handles them all.`, conflict.Error())
+	}
+
+	assert.Equal(t, 4, len(h.HandlersPipe()), "Conflicting mappings are still made")
+
+}
+
+func TestMap_Conflicts_Ambiguous(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	h.Map("GET", "people/{id}/photo", noopHandler)
+	h.Map("POST", "people/me/{size}", noopHandler)
+	h.Map("GET", "people/me/{size}", noopHandler)
+
+	if assert.Equal(t, 1, len(h.Conflicts())) {
+		conflict := h.Conflicts()[0]
+		assert.Equal(t, ConflictAmbiguous, conflict.Kind)
+		assert.Equal(t, []string{"GET"}, conflict.Methods)
+	}
+
+}
+
+func TestMap_Conflicts_Ignored(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.StrictMapping = true
+
+	h.MapBefore("people/***", noopHandler)
+	h.Map("GET", "people/***", noopHandler, func(c context.Context) (MatcherFuncDecision, error) {
+		return DontCare, nil
+	})
+	h.Map("GET", "people/{id}", noopHandler, Version("1"))
+	_, err := h.Map("GET", "people/{id}", noopHandler, Version("2"))
+
+	assert.NoError(t, err, "Pre handlers, mappings with matcher funcs and different versions don't conflict")
+
+}
+
+func TestMap_StrictMapping(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.StrictMapping = true
+
+	h.Map("GET", "people/{id}", noopHandler)
+	handler, err := h.Map("GET", "people/{name}", noopHandler)
+
+	assert.Nil(t, handler)
+	if assert.IsType(t, new(Conflict), err) {
+		assert.Equal(t, ConflictUnreachable, err.(*Conflict).Kind)
+	}
+	assert.Equal(t, 1, len(h.HandlersPipe()))
+	assert.Empty(t, h.Conflicts())
+
+}
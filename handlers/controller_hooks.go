@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	"github.com/stretchr/goweb/http"
+	"github.com/stretchr/goweb/paths"
+	"reflect"
+)
+
+// ActionName gets the name of the controller action (i.e. "Create", "ReadMany"
+// or the name of a custom action) that is handling the request in the specified
+// context, or an empty string if the request isn't being handled by a controller.
+//
+// The name is available to the controller's Before and After hooks too.
+func ActionName(ctx context.Context) string {
+	return ctx.Data().Get(context.DataKeyAction).Str()
+}
+
+// standardActionNames gets the names of the standard actions that will handle
+// each of the specified HTTP methods, for either the collection or single
+// resources.
+func (m *ControllerMapping) standardActionNames(methods []string, singular bool) map[string]string {
+
+	h := m.httpHandler
+	names := make(map[string]string)
+
+	for _, method := range methods {
+
+		var name string
+
+		switch {
+		case method == h.HttpMethodForHead:
+			name = controllers.ActionHead
+		case method == h.HttpMethodForOptions, method == http.MethodOptions:
+			name = controllers.ActionOptions
+		case singular && method == h.HttpMethodForReadOne:
+			name = controllers.ActionRead
+		case singular && method == h.HttpMethodForDeleteOne:
+			name = controllers.ActionDelete
+		case singular && method == h.HttpMethodForUpdateOne:
+			name = controllers.ActionUpdate
+		case singular && method == h.HttpMethodForReplace:
+			name = controllers.ActionReplace
+		case !singular && method == h.HttpMethodForCreate:
+			name = controllers.ActionCreate
+		case !singular && method == h.HttpMethodForReadMany:
+			name = controllers.ActionReadMany
+		case !singular && method == h.HttpMethodForDeleteMany:
+			name = controllers.ActionDeleteMany
+		case !singular && method == h.HttpMethodForUpdateMany:
+			name = controllers.ActionUpdateMany
+		}
+
+		names[method] = name
+
+	}
+
+	return names
+}
+
+// hook gets a HandlerExecutionFunc that calls the before (or after) hooks of
+// the controller for the actions named for each HTTP method, along with the
+// methods that have hooks.
+//
+// If no actions have any hooks, nil is returned.
+func (m *ControllerMapping) hook(before bool, methods []string, actionNames map[string]string) (HandlerExecutionFunc, []string) {
+
+	var hookMethods []string
+	hooks := make(map[string][]HandlerExecutionFunc)
+
+	for _, method := range methods {
+		name := actionNames[method]
+		if funcs := m.hookFuncs(before, name); len(funcs) > 0 {
+			hooks[name] = funcs
+			hookMethods = append(hookMethods, method)
+		}
+	}
+
+	if len(hookMethods) == 0 {
+		return nil, nil
+	}
+
+	return func(ctx context.Context) error {
+
+		// automatic HEAD requests are handled by the GET action, so they
+		// get its hooks too
+		method := ctx.MethodString()
+		if IsAutomaticHead(ctx) {
+			method = http.MethodGet
+		}
+
+		name := actionNames[method]
+		ctx.Data().Set(context.DataKeyAction, name)
+
+		for _, hookFunc := range hooks[name] {
+			if hookErr := hookFunc(ctx); hookErr != nil {
+				return hookErr
+			}
+		}
+
+		return nil
+	}, hookMethods
+
+}
+
+// notCustomAction gets a MatcherFunc that doesn't match requests for any of the
+// custom actions, which are mapped to the specified paths.
+//
+// The hooks of the standard actions use it, as their paths may match custom
+// actions too (people/{id} matches people/search), which would otherwise run
+// the hooks of both.
+func (m *ControllerMapping) notCustomAction(actions []controllers.Action, actionPaths []string) (MatcherFunc, error) {
+
+	patterns := make([]*paths.PathPattern, len(actions))
+	for i, actionPath := range actionPaths {
+		pattern, patternErr := paths.NewPathPattern(actionPath)
+		if patternErr != nil {
+			return nil, patternErr
+		}
+		pattern.CaseSensitive = m.httpHandler.CaseSensitive
+		patterns[i] = pattern
+	}
+
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		method := ctx.MethodString()
+		if IsAutomaticHead(ctx) {
+			method = http.MethodGet
+		}
+
+		for i, action := range actions {
+			if action.Method == method && patterns[i].GetPathMatch(ctx.Path()).Matches {
+				return NoMatch, nil
+			}
+		}
+
+		return DontCare, nil
+
+	}, nil
+
+}
+
+// hookFuncs gets the funcs to call before (or after) the named action, in the
+// order in which they should be called.
+func (m *ControllerMapping) hookFuncs(before bool, actionName string) []HandlerExecutionFunc {
+
+	var general HandlerExecutionFunc
+	var filters []controllers.Filter
+	var prefix string
+
+	if before {
+		prefix = "Before"
+		if beforeController, ok := m.Controller.(controllers.BeforeHandler); ok {
+			general = beforeController.Before
+		}
+		if filterController, ok := m.Controller.(controllers.BeforeFilterHandler); ok {
+			filters = filterController.BeforeFilters()
+		}
+	} else {
+		prefix = "After"
+		if afterController, ok := m.Controller.(controllers.AfterHandler); ok {
+			general = afterController.After
+		}
+		if filterController, ok := m.Controller.(controllers.AfterFilterHandler); ok {
+			filters = filterController.AfterFilters()
+		}
+	}
+
+	var funcs []HandlerExecutionFunc
+
+	for _, filter := range filters {
+		if filter.AppliesTo(actionName) {
+			funcs = append(funcs, filter.Func)
+		}
+	}
+
+	// look for a method named after the action, i.e. BeforeCreate
+	var specific HandlerExecutionFunc
+	if len(actionName) > 0 {
+		if method := reflect.ValueOf(m.Controller).MethodByName(prefix + actionName); method.IsValid() {
+			if specificFunc, ok := method.Interface().(func(context.Context) error); ok {
+				specific = specificFunc
+			}
+		}
+	}
+
+	if before {
+		if general != nil {
+			funcs = append([]HandlerExecutionFunc{general}, funcs...)
+		}
+		if specific != nil {
+			funcs = append(funcs, specific)
+		}
+	} else {
+		if specific != nil {
+			funcs = append([]HandlerExecutionFunc{specific}, funcs...)
+		}
+		if general != nil {
+			funcs = append(funcs, general)
+		}
+	}
+
+	return funcs
+}
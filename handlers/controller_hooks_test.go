@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+)
+
+type testHookedController struct {
+	events []string
+}
+
+func (c *testHookedController) record(event string, ctx context.Context) {
+	c.events = append(c.events, event+":"+ActionName(ctx))
+}
+
+func (c *testHookedController) Create(ctx context.Context) error {
+	c.record("Create", ctx)
+	return nil
+}
+
+func (c *testHookedController) Read(id string, ctx context.Context) error {
+	c.record("Read", ctx)
+	return nil
+}
+
+func (c *testHookedController) Delete(id string, ctx context.Context) error {
+	c.record("Delete", ctx)
+	return nil
+}
+
+func (c *testHookedController) Before(ctx context.Context) error {
+	c.record("Before", ctx)
+	return nil
+}
+
+func (c *testHookedController) After(ctx context.Context) error {
+	c.record("After", ctx)
+	return nil
+}
+
+func (c *testHookedController) BeforeCreate(ctx context.Context) error {
+	c.record("BeforeCreate", ctx)
+	return nil
+}
+
+func (c *testHookedController) AfterDelete(ctx context.Context) error {
+	c.record("AfterDelete", ctx)
+	return nil
+}
+
+func (c *testHookedController) BeforeFilters() []controllers.Filter {
+	return []controllers.Filter{
+		{Func: func(ctx context.Context) error {
+			c.record("OnlyRead", ctx)
+			return nil
+		}, Only: []string{controllers.ActionRead}},
+		{Func: func(ctx context.Context) error {
+			c.record("ExceptRead", ctx)
+			return nil
+		}, Except: []string{controllers.ActionRead, controllers.ActionOptions}},
+	}
+}
+
+type testFilteredController struct {
+	events []string
+}
+
+func (c *testFilteredController) ReadMany(ctx context.Context) error {
+	c.events = append(c.events, "ReadMany")
+	return nil
+}
+
+func (c *testFilteredController) Read(id string, ctx context.Context) error {
+	c.events = append(c.events, "Read")
+	return nil
+}
+
+func (c *testFilteredController) AfterReadMany(ctx context.Context) error {
+	c.events = append(c.events, "AfterReadMany")
+	return nil
+}
+
+func serveTestRequest(h *HttpHandler, method, url string) *http_test.TestResponseWriter {
+	request, _ := http.NewRequest(method, url, nil)
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+	return response
+}
+
+func TestControllerHooks(t *testing.T) {
+
+	c := new(testHookedController)
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", c)
+
+	serveTestRequest(h, "POST", "http://goweb.org/people")
+	assert.Equal(t, []string{"Before:Create", "ExceptRead:Create", "BeforeCreate:Create", "Create:Create", "After:Create"}, c.events)
+
+	c.events = nil
+	serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.Equal(t, []string{"Before:Read", "OnlyRead:Read", "Read:Read", "After:Read"}, c.events)
+
+	c.events = nil
+	serveTestRequest(h, "HEAD", "http://goweb.org/people/123")
+	assert.Equal(t, []string{"Before:Read", "OnlyRead:Read", "Read:Read", "After:Read"}, c.events, "Automatic HEAD requests get the hooks of the GET action")
+
+	c.events = nil
+	serveTestRequest(h, "DELETE", "http://goweb.org/people/123")
+	assert.Equal(t, []string{"Before:Delete", "ExceptRead:Delete", "Delete:Delete", "AfterDelete:Delete", "After:Delete"}, c.events)
+
+	c.events = nil
+	serveTestRequest(h, "OPTIONS", "http://goweb.org/people/123")
+	assert.Equal(t, []string{"Before:Options", "After:Options"}, c.events)
+
+}
+
+func TestControllerHooks_OnlyMappedWhenNeeded(t *testing.T) {
+
+	c := new(testFilteredController)
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", c)
+
+	assert.Equal(t, 0, len(h.PreHandlersPipe()))
+	if assert.Equal(t, 1, len(h.PostHandlersPipe())) {
+		after := h.PostHandlersPipe()[0].(*PathMatchHandler)
+		assert.Equal(t, []string{"GET"}, after.HttpMethods)
+		assert.Equal(t, "people", after.PathPattern.RawPath)
+	}
+
+	serveTestRequest(h, "GET", "http://goweb.org/people")
+	serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.Equal(t, []string{"ReadMany", "AfterReadMany", "Read"}, c.events)
+
+}
+
+func TestControllerMapping_ActionNames(t *testing.T) {
+
+	rest := new(testHookedController)
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	mapping, _ := h.MapControllerMapping("people", rest)
+
+	var names []string
+	for _, handler := range h.HandlersPipe() {
+		pathMatchHandler := handler.(*PathMatchHandler)
+		assert.Equal(t, rest, pathMatchHandler.Controller)
+		names = append(names, pathMatchHandler.ActionName)
+	}
+	assert.Equal(t, []string{"Create", "Read", "Delete", "Options", "Options"}, names)
+
+	for _, handler := range h.PreHandlersPipe() {
+		assert.Equal(t, "", handler.(*PathMatchHandler).ActionName)
+	}
+
+	assert.Equal(t, len(h.HandlersPipe())+len(h.PreHandlersPipe())+len(h.PostHandlersPipe()), len(mapping.Handlers))
+
+}
+
+func TestControllerHooks_CustomActions(t *testing.T) {
+
+	orders := new(testOrdersController)
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("orders", orders)
+
+	var actionName string
+	h.MapAfter(func(ctx context.Context) error {
+		actionName = ActionName(ctx)
+		return nil
+	})
+
+	serveTestRequest(h, "POST", "http://goweb.org/orders/123/cancel")
+	assert.Equal(t, "Cancel", actionName)
+
+	serveTestRequest(h, "GET", "http://goweb.org/orders/search")
+	assert.Equal(t, "Search", actionName)
+
+}
+
+// testHookedActionsController has hooks for both standard and custom actions.
+type testHookedActionsController struct {
+	testHookedController
+}
+
+func (c *testHookedActionsController) BeforeRead(ctx context.Context) error {
+	c.record("BeforeRead", ctx)
+	return nil
+}
+
+func (c *testHookedActionsController) AfterRead(ctx context.Context) error {
+	c.record("AfterRead", ctx)
+	return nil
+}
+
+func (c *testHookedActionsController) BeforeSearch(ctx context.Context) error {
+	c.record("BeforeSearch", ctx)
+	return nil
+}
+
+func (c *testHookedActionsController) AfterCancel(ctx context.Context) error {
+	c.record("AfterCancel", ctx)
+	return nil
+}
+
+func (c *testHookedActionsController) BeforeFilters() []controllers.Filter {
+	return nil
+}
+
+func (c *testHookedActionsController) AfterFilters() []controllers.Filter {
+	return nil
+}
+
+func (c *testHookedActionsController) Actions() []controllers.Action {
+	return []controllers.Action{
+		{Name: "Search", Method: "GET", Path: "search", Handler: func(ctx context.Context) error {
+			c.record("Search", ctx)
+			return nil
+		}},
+		{Name: "Cancel", Method: "POST", Path: "cancel", Scope: controllers.MemberScope, Handler: func(ctx context.Context) error {
+			c.record("Cancel", ctx)
+			return nil
+		}},
+	}
+}
+
+func TestControllerHooks_CustomActions_RunOnce(t *testing.T) {
+
+	c := new(testHookedActionsController)
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", c)
+
+	serveTestRequest(h, "GET", "http://goweb.org/people/search")
+	assert.Equal(t, []string{"Before:Search", "BeforeSearch:Search", "Search:Search", "After:Search"}, c.events)
+
+	c.events = nil
+	serveTestRequest(h, "HEAD", "http://goweb.org/people/search")
+	assert.Equal(t, []string{"Before:Search", "BeforeSearch:Search", "Search:Search", "After:Search"}, c.events)
+
+	c.events = nil
+	serveTestRequest(h, "POST", "http://goweb.org/people/123/cancel")
+	assert.Equal(t, []string{"Before:Cancel", "Cancel:Cancel", "AfterCancel:Cancel", "After:Cancel"}, c.events)
+
+	c.events = nil
+	serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.Equal(t, []string{"Before:Read", "BeforeRead:Read", "Read:Read", "AfterRead:Read", "After:Read"}, c.events)
+
+}
+
+func TestActionName(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+	assert.Equal(t, "", ActionName(ctx))
+
+	ctx.Data().Set(context.DataKeyAction, "Create")
+	assert.Equal(t, "Create", ActionName(ctx))
+
+}
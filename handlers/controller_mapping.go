@@ -0,0 +1,540 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	"github.com/stretchr/goweb/http"
+	"github.com/stretchr/goweb/paths"
+	"github.com/stretchr/objx"
+	stewstrings "github.com/stretchr/stew/strings"
+	nethttp "net/http"
+	"strings"
+)
+
+const (
+	// dataKeyParentMissing is the data key for the flag that says a parent
+	// of a nested controller doesn't exist.
+	dataKeyParentMissing string = "parentmissing"
+)
+
+// ParentIDs gets the IDs of the parent resources of a nested controller action,
+// keyed by the name of their ID parameters.
+//
+// For example, with books nested under people:
+//
+//     people, _ := goweb.MapControllerMapping("people", peopleController)
+//     people.Nest("books", booksController)
+//
+// a request for GET /people/123/books/456 would call booksController.Read("456", ctx),
+// and ParentIDs(ctx).Get("personId").Str() would be "123".
+func ParentIDs(ctx context.Context) objx.Map {
+	if parentIDs, ok := ctx.Data().Get(context.DataKeyParentIDs).Data().(objx.Map); ok {
+		return parentIDs
+	}
+	return objx.Map{}
+}
+
+// ControllerMapping represents a controller that has been mapped with
+// MapControllerMapping, or nested beneath another controller with Nest.
+type ControllerMapping struct {
+
+	// Path is the path of the controller, relative to its parent if it is
+	// nested.
+	Path string
+
+	// Controller is the controller object.
+	Controller interface{}
+
+	// Parent is the ControllerMapping this controller is nested under, or nil.
+	Parent *ControllerMapping
+
+	// IDParameterName is the name of the path parameter that holds the ID in
+	// this controller's own single resource paths.
+	IDParameterName string
+
+	// Handlers are the Handlers that were mapped for this controller.
+	Handlers []Handler
+
+	// httpHandler is the HttpHandler the controller is mapped in.
+	httpHandler *HttpHandler
+
+	// matcherFuncs are the MatcherFuncs used when mapping the controller.
+	matcherFuncs []MatcherFunc
+
+	// handlerOptions are the HandlerOptions used when mapping the controller.
+	handlerOptions []HandlerOption
+}
+
+// newControllerMapping makes a new ControllerMapping from the options passed
+// to MapController or Nest.
+func newControllerMapping(h *HttpHandler, parent *ControllerMapping, options ...interface{}) *ControllerMapping {
+
+	var matcherFuncStartPos int = -1
+
+	m := &ControllerMapping{httpHandler: h, Parent: parent}
+
+	switch options[0].(type) {
+	case string: // (path, controller)
+		if len(options) == 1 {
+			// we need more than just a string
+			panic("goweb: Cannot call MapController without a Controller")
+		}
+		m.Path = options[0].(string)
+		m.Controller = options[1]
+		matcherFuncStartPos = 2
+	default: // (controller)
+		if restfulController, ok := options[0].(controllers.RestfulController); ok {
+			m.Controller = restfulController
+			m.Path = restfulController.Path()
+		} else {
+			// use the default path
+			m.Controller = options[0]
+			m.Path = paths.PathPrefixForClass(options[0])
+		}
+		matcherFuncStartPos = 1
+	}
+
+	// nested controllers are matched by their parent's matcher funcs too, and
+	// have the same handler options
+	if parent != nil {
+		m.matcherFuncs = append(m.matcherFuncs, parent.matcherFuncs...)
+		m.handlerOptions = append(m.handlerOptions, parent.handlerOptions...)
+	}
+	m.matcherFuncs = append(m.matcherFuncs, findMatcherFuncs(options[matcherFuncStartPos:]...)...)
+	m.handlerOptions = append(m.handlerOptions, findHandlerOptions(options[matcherFuncStartPos:]...)...)
+
+	if namer, ok := m.Controller.(controllers.RestfulIDParameterNamer); ok {
+		m.IDParameterName = namer.IDParameterName()
+	} else {
+		m.IDParameterName = RestfulIDParameterName
+	}
+
+	return m
+}
+
+// Nest maps a controller beneath a single resource of this one.
+//
+//     people, _ := goweb.MapControllerMapping("people", peopleController)
+//     people.Nest("books", booksController)
+//
+// will map paths like:
+//
+//     people/{personId}/books
+//     people/{personId}/books/{id}
+//
+// See ControllerMapping.NestedIDParameterName for details of how the parent's ID
+// parameter is named, and ParentIDs for how to get the parent IDs in the nested
+// controller's actions.
+//
+// If a parent controller implements controllers.RestfulExistenceChecker, it
+// will be asked whether the parent resource exists before each of the nested
+// controller's actions are called.  If it doesn't, a 404 response is written
+// and the action is not called.
+//
+// Nest takes the same options as MapController.
+func (m *ControllerMapping) Nest(options ...interface{}) (*ControllerMapping, error) {
+
+	if len(options) == 0 {
+		// no arguments is an error
+		panic("goweb: Cannot call Nest with no arguments")
+	}
+
+	nested := newControllerMapping(m.httpHandler, m, options...)
+
+	// make sure all the ID parameters are distinct
+	names := map[string]bool{nested.IDParameterName: true}
+	for parent := m; parent != nil; parent = parent.Parent {
+		name := parent.NestedIDParameterName()
+		if names[name] {
+			return nil, errors.New(fmt.Sprintf("goweb: Cannot nest \"%s\" because the ID parameter name \"%s\" is used more than once in \"%s\".  Implement controllers.RestfulIDParameterNamer to choose another name.", nested.Path, name, nested.singularPath()))
+		}
+		names[name] = true
+	}
+
+	if mapErr := nested.mapActions(); mapErr != nil {
+		return nil, mapErr
+	}
+
+	return nested, nil
+
+}
+
+// NestedIDParameterName gets the name of the path parameter that holds this
+// controller's ID in the paths of controllers nested beneath it.
+//
+// If the controller implements controllers.RestfulIDParameterNamer, its
+// IDParameterName is used.  Otherwise, the name is made from the singular
+// form of the last segment of the path followed by "Id", so "people" becomes
+// "personId" and "books" becomes "bookId".
+func (m *ControllerMapping) NestedIDParameterName() string {
+
+	if _, ok := m.Controller.(controllers.RestfulIDParameterNamer); ok {
+		return m.IDParameterName
+	}
+
+	segments := paths.NewPath(m.Path).Segments()
+	return stewstrings.MergeStrings(singular(segments[len(segments)-1]), "Id")
+
+}
+
+// collectionPath gets the full path of the collection of resources this
+// controller handles, including the paths of any parents.  e.g. people/{personId}/books
+func (m *ControllerMapping) collectionPath() string {
+	if m.Parent == nil {
+		return m.Path
+	}
+	return stewstrings.MergeStrings(m.Parent.collectionPath(), "/{", m.Parent.NestedIDParameterName(), "}/", m.Path)
+}
+
+// singularPath gets the full path of a single resource this controller handles,
+// including the paths of any parents.  e.g. people/{personId}/books/{id}
+func (m *ControllerMapping) singularPath() string {
+	return stewstrings.MergeStrings(m.collectionPath(), "/{", m.IDParameterName, "}")
+}
+
+// optionalSingularPath gets the full path of this controller with an optional
+// ID.  e.g. people/{personId}/books/[id]
+func (m *ControllerMapping) optionalSingularPath() string {
+	return stewstrings.MergeStrings(m.collectionPath(), "/[", m.IDParameterName, "]")
+}
+
+// parents gets the parents of this controller, starting with the outermost.
+func (m *ControllerMapping) parents() []*ControllerMapping {
+	var parents []*ControllerMapping
+	for parent := m.Parent; parent != nil; parent = parent.Parent {
+		parents = append([]*ControllerMapping{parent}, parents...)
+	}
+	return parents
+}
+
+// action wraps the executor of one of the controller's actions so that the
+// name of the action and parent IDs are available in the context, and parents
+// exist (and any conditional request headers are satisfied) before the action
+// is called.
+func (m *ControllerMapping) action(name string, executor HandlerExecutionFunc) HandlerExecutionFunc {
+	executor = m.withPreconditions(name, executor)
+	return m.withParents(func(ctx context.Context) error {
+		ctx.Data().Set(context.DataKeyAction, name)
+		return executor(ctx)
+	})
+}
+
+// withParents wraps the executor so that parent IDs are available in the
+// context, and parents exist before it is called.
+func (m *ControllerMapping) withParents(executor HandlerExecutionFunc) HandlerExecutionFunc {
+
+	parents := m.parents()
+
+	if len(parents) == 0 {
+		return executor
+	}
+
+	return func(ctx context.Context) error {
+
+		// has a parent already been found to be missing?
+		if ctx.Data().Get(dataKeyParentMissing).Bool() {
+			return nil
+		}
+
+		// have the parents already been checked (i.e. by a before hook)?
+		if _, checked := ctx.Data()[context.DataKeyParentIDs]; checked {
+			return executor(ctx)
+		}
+
+		parentIDs := make(objx.Map)
+
+		for _, parent := range parents {
+
+			name := parent.NestedIDParameterName()
+			id := ctx.PathParams().Get(name).Str()
+			parentIDs[name] = id
+
+			if checker, ok := parent.Controller.(controllers.RestfulExistenceChecker); ok {
+
+				exists, existsErr := checker.Exists(id, ctx)
+
+				if existsErr != nil {
+					return existsErr
+				}
+
+				if !exists {
+					ctx.Data().Set(dataKeyParentMissing, true)
+					ctx.HttpResponseWriter().WriteHeader(nethttp.StatusNotFound)
+					return nil
+				}
+
+			}
+
+		}
+
+		ctx.Data().Set(context.DataKeyParentIDs, parentIDs)
+
+		return executor(ctx)
+	}
+
+}
+
+// mapHandler maps a handler in the specified way, and keeps track of it.
+//
+// The matcherFuncs are checked before those the controller was mapped with.
+func (m *ControllerMapping) mapHandler(mapFunc func(options ...interface{}) (Handler, error), methods []string, path string, executor HandlerExecutionFunc, matcherFuncs []MatcherFunc, actionName, description string) error {
+
+	handler, mapErr := mapFunc(methods, path, (func(context.Context) error)(executor), matcherFuncs, m.matcherFuncs, m.handlerOptions)
+
+	if mapErr != nil {
+		return mapErr
+	}
+
+	pathMatchHandler := handler.(*PathMatchHandler)
+	pathMatchHandler.Controller = m.Controller
+	pathMatchHandler.ActionName = actionName
+	if len(description) > 0 {
+		pathMatchHandler.Description = description
+	}
+
+	m.Handlers = append(m.Handlers, handler)
+	return nil
+
+}
+
+// customActions gets the custom actions of the controller, and the full
+// paths they will be mapped to.  An error is returned if an action has no
+// Method or no Handler.
+func (m *ControllerMapping) customActions() ([]controllers.Action, []string, error) {
+
+	actionsController, ok := m.Controller.(controllers.RestfulActions)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	actions := actionsController.Actions()
+	actionPaths := make([]string, len(actions))
+
+	for i, action := range actions {
+		if len(action.Method) == 0 || action.Handler == nil {
+			return nil, nil, errors.New(fmt.Sprintf("goweb: Cannot map the \"%s\" action of \"%s\" because it has no Method or no Handler.", action.Name, m.Path))
+		}
+		if action.Scope == controllers.MemberScope {
+			actionPaths[i] = stewstrings.MergeStrings(m.singularPath(), "/", action.Path)
+		} else {
+			actionPaths[i] = stewstrings.MergeStrings(m.collectionPath(), "/", action.Path)
+		}
+	}
+
+	return actions, actionPaths, nil
+}
+
+// allowFunc gets a HandlerExecutionFunc that responds to OPTIONS requests with
+// the specified allowed methods.
+func allowFunc(methods []string) HandlerExecutionFunc {
+	return func(ctx context.Context) error {
+		ctx.HttpResponseWriter().Header().Set("Allow", strings.Join(methods, ","))
+		ctx.HttpResponseWriter().WriteHeader(200)
+		return nil
+	}
+}
+
+// mapActions maps the handlers for the controller.
+func (m *ControllerMapping) mapActions() error {
+
+	h := m.httpHandler
+	controller := m.Controller
+
+	// get the specialised paths that we might need
+	path := m.collectionPath()                     // e.g.  people
+	pathWithID := m.singularPath()                 // e.g.  people/123
+	pathWithOptionalID := m.optionalSingularPath() // e.g.  people/[123]
+	idParameterName := m.IDParameterName
+
+	// get the HTTP methods that we will end up mapping
+	collectiveMethods := optionsListForResourceCollection(h, controller)
+	singularMethods := optionsListForSingleResource(h, controller)
+
+	// mappings collects the handlers to map, in order
+	type mapping struct {
+		mapFunc      func(options ...interface{}) (Handler, error)
+		methods      []string
+		path         string
+		executor     HandlerExecutionFunc
+		actionName   string
+		description  string
+		matcherFuncs []MatcherFunc
+	}
+	var mappings []mapping
+
+	// get any custom actions
+	actions, actionPaths, actionsErr := m.customActions()
+	if actionsErr != nil {
+		return actionsErr
+	}
+
+	// the paths of the standard actions may match custom actions too (i.e.
+	// people/{id} matches people/search), so their hooks skip those requests
+	var standardMatcherFuncs []MatcherFunc
+	if len(actions) > 0 {
+		notCustomAction, matcherErr := m.notCustomAction(actions, actionPaths)
+		if matcherErr != nil {
+			return matcherErr
+		}
+		standardMatcherFuncs = []MatcherFunc{notCustomAction}
+	}
+
+	// before and after hooks for the collection, single resources and each
+	// of the custom actions
+	type hookTarget struct {
+		path         string
+		methods      []string
+		actionNames  map[string]string
+		matcherFuncs []MatcherFunc
+	}
+	hookTargets := []hookTarget{
+		{path, collectiveMethods, m.standardActionNames(collectiveMethods, false), standardMatcherFuncs},
+		{pathWithID, singularMethods, m.standardActionNames(singularMethods, true), standardMatcherFuncs},
+	}
+	for i, action := range actions {
+		hookTargets = append(hookTargets, hookTarget{actionPaths[i], []string{action.Method}, map[string]string{action.Method: action.Name}, nil})
+	}
+
+	for _, before := range []bool{true, false} {
+
+		mapFunc := h.MapBefore
+		if !before {
+			mapFunc = h.MapAfter
+		}
+
+		for _, target := range hookTargets {
+			if hook, hookMethods := m.hook(before, target.methods, target.actionNames); hook != nil {
+				mappings = append(mappings, mapping{mapFunc, hookMethods, target.path, m.withParents(hook), "", "", target.matcherFuncs})
+			}
+		}
+
+	}
+
+	// custom actions are mapped before the standard ones, so that paths
+	// like /resource/search aren't mistaken for /resource/{id}
+	var actionPathOrder []string
+	actionPathMethods := make(map[string][]string)
+	for i, action := range actions {
+
+		description := action.Description
+		if len(description) == 0 && len(action.Name) > 0 {
+			description = fmt.Sprintf("%s action", action.Name)
+		}
+
+		mappings = append(mappings, mapping{h.Map, []string{action.Method}, actionPaths[i], m.action(action.Name, action.Handler), action.Name, description, nil})
+
+		if _, exists := actionPathMethods[actionPaths[i]]; !exists {
+			actionPathOrder = append(actionPathOrder, actionPaths[i])
+		}
+		actionPathMethods[actionPaths[i]] = append(actionPathMethods[actionPaths[i]], action.Method)
+
+	}
+
+	// POST /resource  -  Create
+	if restfulController, ok := controller.(controllers.RestfulCreator); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForCreate}, path, m.action(controllers.ActionCreate, restfulController.Create), controllers.ActionCreate, "", nil})
+	}
+
+	// GET /resource/{id}  -  Read
+	if restfulController, ok := controller.(controllers.RestfulReader); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForReadOne}, pathWithID, m.action(controllers.ActionRead, func(ctx context.Context) error {
+			return restfulController.Read(ctx.PathParams().Get(idParameterName).Str(), ctx)
+		}), controllers.ActionRead, "", nil})
+	}
+
+	// GET /resource  -  ReadMany
+	if restfulController, ok := controller.(controllers.RestfulManyReader); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForReadMany}, path, m.action(controllers.ActionReadMany, restfulController.ReadMany), controllers.ActionReadMany, "", nil})
+	}
+
+	// DELETE /resource/{id}  -  Delete
+	if restfulController, ok := controller.(controllers.RestfulDeletor); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForDeleteOne}, pathWithID, m.action(controllers.ActionDelete, func(ctx context.Context) error {
+			return restfulController.Delete(ctx.PathParams().Get(idParameterName).Str(), ctx)
+		}), controllers.ActionDelete, "", nil})
+	}
+
+	// DELETE /resource  -  DeleteMany
+	if restfulController, ok := controller.(controllers.RestfulManyDeleter); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForDeleteMany}, path, m.action(controllers.ActionDeleteMany, restfulController.DeleteMany), controllers.ActionDeleteMany, "", nil})
+	}
+
+	// PATCH /resource/{id}  -  Update
+	if restfulController, ok := controller.(controllers.RestfulUpdater); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForUpdateOne}, pathWithID, m.action(controllers.ActionUpdate, func(ctx context.Context) error {
+			return restfulController.Update(ctx.PathParams().Get(idParameterName).Str(), ctx)
+		}), controllers.ActionUpdate, "", nil})
+	}
+
+	// PATCH /resource  -  UpdateMany
+	if restfulController, ok := controller.(controllers.RestfulManyUpdater); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForUpdateMany}, path, m.action(controllers.ActionUpdateMany, restfulController.UpdateMany), controllers.ActionUpdateMany, "", nil})
+	}
+
+	// PUT /resource/{id}  -  Replace
+	if restfulController, ok := controller.(controllers.RestfulReplacer); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForReplace}, pathWithID, m.action(controllers.ActionReplace, func(ctx context.Context) error {
+			return restfulController.Replace(ctx.PathParams().Get(idParameterName).Str(), ctx)
+		}), controllers.ActionReplace, "", nil})
+	}
+
+	// HEAD /resource/[id]  -  Head
+	if restfulController, ok := controller.(controllers.RestfulHead); ok {
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForHead}, pathWithOptionalID, m.action(controllers.ActionHead, restfulController.Head), controllers.ActionHead, "", nil})
+	}
+
+	// OPTIONS for the custom actions, before the standard ones for the same
+	// reason as above
+	for _, actionPath := range actionPathOrder {
+		mappings = append(mappings, mapping{h.Map, []string{http.MethodOptions}, actionPath, m.action(controllers.ActionOptions, allowFunc(h.withHead(append(actionPathMethods[actionPath], http.MethodOptions)))), controllers.ActionOptions, "", nil})
+	}
+
+	// OPTIONS /resource/[id]  -  Options
+	if restfulController, ok := controller.(controllers.RestfulOptions); ok {
+
+		mappings = append(mappings, mapping{h.Map, []string{h.HttpMethodForOptions}, pathWithOptionalID, m.action(controllers.ActionOptions, restfulController.Options), controllers.ActionOptions, "", nil})
+
+	} else {
+
+		// use the default options implementation
+
+		mappings = append(mappings, mapping{h.Map, []string{http.MethodOptions}, path, m.action(controllers.ActionOptions, allowFunc(h.withHead(collectiveMethods))), controllers.ActionOptions, "", nil})
+
+		mappings = append(mappings, mapping{h.Map, []string{http.MethodOptions}, pathWithID, m.action(controllers.ActionOptions, allowFunc(h.withHead(singularMethods))), controllers.ActionOptions, "", nil})
+
+	}
+
+	for _, mapping := range mappings {
+		if mapErr := m.mapHandler(mapping.mapFunc, mapping.methods, mapping.path, mapping.executor, mapping.matcherFuncs, mapping.actionName, mapping.description); mapErr != nil {
+			return mapErr
+		}
+	}
+
+	// everything ok
+	return nil
+
+}
+
+// singular gets the singular form of the specified (English) plural word.
+//
+// It only knows about the common cases; controllers with unusual names
+// should implement controllers.RestfulIDParameterNamer.
+func singular(word string) string {
+
+	lower := strings.ToLower(word)
+
+	switch {
+	case lower == "people":
+		return word[:1] + "erson"
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && len(word) > 1:
+		return word[:len(word)-1]
+	}
+
+	return word
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"fmt"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	controllers_test "github.com/stretchr/goweb/controllers/test"
+	"github.com/stretchr/objx"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+)
+
+type testPeopleController struct {
+	missing bool
+}
+
+func (c *testPeopleController) Read(id string, ctx context.Context) error {
+	return nil
+}
+
+func (c *testPeopleController) Exists(id string, ctx context.Context) (bool, error) {
+	return !c.missing, nil
+}
+
+type testBooksController struct {
+	readID    string
+	parentIDs objx.Map
+}
+
+func (c *testBooksController) Read(id string, ctx context.Context) error {
+	c.readID = id
+	c.parentIDs = ParentIDs(ctx)
+	return nil
+}
+
+func (c *testBooksController) ReadMany(ctx context.Context) error {
+	c.parentIDs = ParentIDs(ctx)
+	return nil
+}
+
+type testNamedIDController struct {
+	testBooksController
+	name string
+}
+
+func (c *testNamedIDController) IDParameterName() string {
+	return c.name
+}
+
+func TestMapController_ReturnsMapping(t *testing.T) {
+
+	rest := new(controllers_test.TestController)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	mapping, err := h.MapControllerMapping("something", rest)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "something", mapping.Path)
+		assert.Equal(t, rest, mapping.Controller)
+		assert.Nil(t, mapping.Parent)
+		assert.Equal(t, "id", mapping.IDParameterName)
+		assert.Equal(t, "somethingId", mapping.NestedIDParameterName())
+		assert.Equal(t, len(h.HandlersPipe())+len(h.PreHandlersPipe())+len(h.PostHandlersPipe()), len(mapping.Handlers))
+	}
+
+}
+
+func TestControllerMapping_Nest(t *testing.T) {
+
+	people := new(testPeopleController)
+	books := new(testBooksController)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	peopleMapping, _ := h.MapControllerMapping("people", people)
+	booksMapping, err := peopleMapping.Nest("books", books)
+
+	if assert.NoError(t, err) {
+
+		assert.Equal(t, peopleMapping, booksMapping.Parent)
+
+		assertPathMatchHandler(t, booksMapping.Handlers[0].(*PathMatchHandler), "/people/123/books/456", "GET", "read one")
+		assertPathMatchHandler(t, booksMapping.Handlers[1].(*PathMatchHandler), "/people/123/books", "GET", "read many")
+		assert.Equal(t, "people/{personId}/books/{id}", booksMapping.Handlers[0].(*PathMatchHandler).PathPattern.RawPath)
+
+		request, _ := http.NewRequest("GET", "http://goweb.org/people/123/books/456", nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+
+		assert.Equal(t, "456", books.readID)
+		assert.Equal(t, "123", books.parentIDs.Get("personId").Str())
+
+		request, _ = http.NewRequest("GET", "http://goweb.org/people/789/books", nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+
+		assert.Equal(t, "789", books.parentIDs.Get("personId").Str())
+
+	}
+
+}
+
+func TestControllerMapping_Nest_Deeply(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	peopleMapping, _ := h.MapControllerMapping("people", new(testPeopleController))
+	booksMapping, _ := peopleMapping.Nest("books", new(testPeopleController))
+	pages := new(testBooksController)
+	pagesMapping, err := booksMapping.Nest("pages", pages)
+
+	if assert.NoError(t, err) {
+
+		assert.Equal(t, "people/{personId}/books/{bookId}/pages/{id}", pagesMapping.Handlers[0].(*PathMatchHandler).PathPattern.RawPath)
+
+		request, _ := http.NewRequest("GET", "http://goweb.org/people/1/books/2/pages/3", nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+
+		assert.Equal(t, "3", pages.readID)
+		assert.Equal(t, "1", pages.parentIDs.Get("personId").Str())
+		assert.Equal(t, "2", pages.parentIDs.Get("bookId").Str())
+
+	}
+
+}
+
+func TestControllerMapping_Nest_ParentMissing(t *testing.T) {
+
+	people := &testPeopleController{missing: true}
+	books := new(testBooksController)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	peopleMapping, _ := h.MapControllerMapping("people", people)
+	peopleMapping.Nest("books", books)
+
+	request, _ := http.NewRequest("GET", "http://goweb.org/people/123/books/456", nil)
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "", books.readID)
+
+}
+
+func TestControllerMapping_Nest_NamedIDParameters(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	owners := &testNamedIDController{name: "ownerId"}
+	ownersMapping, _ := h.MapControllerMapping("people", owners)
+	assert.Equal(t, "ownerId", ownersMapping.IDParameterName)
+	assert.Equal(t, "ownerId", ownersMapping.NestedIDParameterName())
+	assert.Equal(t, "people/{ownerId}", ownersMapping.Handlers[0].(*PathMatchHandler).PathPattern.RawPath)
+
+	books := &testNamedIDController{name: "bookId"}
+	booksMapping, err := ownersMapping.Nest("books", books)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "people/{ownerId}/books/{bookId}", booksMapping.Handlers[0].(*PathMatchHandler).PathPattern.RawPath)
+	}
+
+	// the same name can't be used twice
+	_, err = ownersMapping.Nest("pets", &testNamedIDController{name: "ownerId"})
+	assert.Error(t, err)
+
+}
+
+func TestSingular(t *testing.T) {
+
+	assert.Equal(t, "person", singular("people"))
+	assert.Equal(t, "book", singular("books"))
+	assert.Equal(t, "category", singular("categories"))
+	assert.Equal(t, "address", singular("addresses"))
+	assert.Equal(t, "box", singular("boxes"))
+	assert.Equal(t, "address", singular("address"))
+	assert.Equal(t, "sheep", singular("sheep"))
+
+}
+
+type testOrdersController struct {
+	called string
+	id     string
+}
+
+func (c *testOrdersController) Read(id string, ctx context.Context) error {
+	c.called = "read"
+	return nil
+}
+
+func (c *testOrdersController) Before(ctx context.Context) error {
+	return nil
+}
+
+func (c *testOrdersController) Actions() []controllers.Action {
+	return []controllers.Action{
+		{Name: "Cancel", Method: "POST", Path: "cancel", Scope: controllers.MemberScope, Handler: func(ctx context.Context) error {
+			c.called = "cancel"
+			c.id = ctx.PathValue("id")
+			return nil
+		}},
+		{Name: "Search", Method: "GET", Path: "search", Scope: controllers.CollectionScope, Handler: func(ctx context.Context) error {
+			c.called = "search"
+			return nil
+		}, Description: "Searches the orders"},
+	}
+}
+
+func TestMapController_CustomActions(t *testing.T) {
+
+	orders := new(testOrdersController)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	err := h.MapController("orders", orders)
+
+	if assert.NoError(t, err) {
+
+		// before handlers for the custom actions
+		if assert.Equal(t, 4, len(h.PreHandlersPipe())) {
+			assertPathMatchHandler(t, h.PreHandlersPipe()[2].(*PathMatchHandler), "/orders/123/cancel", "POST", "before cancel")
+			assertPathMatchHandler(t, h.PreHandlersPipe()[3].(*PathMatchHandler), "/orders/search", "GET", "before search")
+		}
+
+		// the custom actions come first
+		cancel := h.HandlersPipe()[0].(*PathMatchHandler)
+		assertPathMatchHandler(t, cancel, "/orders/123/cancel", "POST", "cancel")
+		assert.Equal(t, "Cancel action", cancel.Description)
+		search := h.HandlersPipe()[1].(*PathMatchHandler)
+		assertPathMatchHandler(t, search, "/orders/search", "GET", "search")
+		assert.Equal(t, "Searches the orders", search.Description)
+		assert.Contains(t, h.String(), "Searches the orders")
+
+		request, _ := http.NewRequest("GET", "http://goweb.org/orders/search", nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+		assert.Equal(t, "search", orders.called)
+
+		request, _ = http.NewRequest("GET", "http://goweb.org/orders/123", nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+		assert.Equal(t, "read", orders.called)
+
+		request, _ = http.NewRequest("POST", "http://goweb.org/orders/123/cancel", nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+		assert.Equal(t, "cancel", orders.called)
+		assert.Equal(t, "123", orders.id)
+
+		// OPTIONS
+		request, _ = http.NewRequest("OPTIONS", "http://goweb.org/orders/123/cancel", nil)
+		response := new(http_test.TestResponseWriter)
+		h.ServeHTTP(response, request)
+		assert.Equal(t, "POST,OPTIONS", response.Header().Get("Allow"))
+
+		request, _ = http.NewRequest("OPTIONS", "http://goweb.org/orders/search", nil)
+		response = new(http_test.TestResponseWriter)
+		h.ServeHTTP(response, request)
+		assert.Equal(t, "GET,HEAD,OPTIONS", response.Header().Get("Allow"))
+
+		assert.Empty(t, h.Conflicts(), "The custom actions should all be reachable")
+
+	}
+
+}
+
+// testBrokenActionsController has custom actions that can't be mapped.
+type testBrokenActionsController struct {
+	actions []controllers.Action
+}
+
+func (c *testBrokenActionsController) Read(id string, ctx context.Context) error {
+	return nil
+}
+
+func (c *testBrokenActionsController) Actions() []controllers.Action {
+	return c.actions
+}
+
+func TestMapController_CustomActions_Invalid(t *testing.T) {
+
+	handler := func(ctx context.Context) error {
+		return nil
+	}
+
+	for _, action := range []controllers.Action{
+		{Name: "Cancel", Path: "cancel", Scope: controllers.MemberScope, Handler: handler},
+		{Name: "Search", Method: "GET", Path: "search"},
+	} {
+
+		h := NewHttpHandler(codecsservices.NewWebCodecService())
+		err := h.MapController("orders", &testBrokenActionsController{[]controllers.Action{action}})
+
+		if assert.Error(t, err, action.Name) {
+			assert.Contains(t, err.Error(), fmt.Sprintf("\"%s\" action", action.Name))
+		}
+		assert.Equal(t, 0, len(h.HandlersPipe()), "Nothing is mapped")
+
+	}
+
+}
+
+func TestControllerMapping_Nest_CustomActions(t *testing.T) {
+
+	orders := new(testOrdersController)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	people, _ := h.MapControllerMapping("people", new(testPeopleController))
+	ordersMapping, _ := people.Nest("orders", orders)
+
+	assert.Equal(t, "people/{personId}/orders/{id}/cancel", ordersMapping.Handlers[4].(*PathMatchHandler).PathPattern.RawPath)
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people/1/orders/2/cancel", nil)
+	h.ServeHTTP(new(http_test.TestResponseWriter), request)
+	assert.Equal(t, "cancel", orders.called)
+	assert.Equal(t, "2", orders.id)
+
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	nethttp "net/http"
+	"strings"
+)
+
+// SetETag sets the ETag header of the response to the specified version of
+// the resource, quoting it if necessary.
+func SetETag(ctx context.Context, etag string) {
+	ctx.HttpResponseWriter().Header().Set("ETag", quoteETag(etag))
+}
+
+// quoteETag gets the specified ETag with quotes, unless it is already quoted
+// or is a weak ETag (i.e. W/"123").
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, "\"") || strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return fmt.Sprintf("\"%s\"", etag)
+}
+
+// etagMatches gets whether any of the ETags in the specified If-Match or
+// If-None-Match header value match the ETag.
+//
+// Weak comparison ignores the W/ prefix, whereas strong comparison never
+// matches weak ETags.
+func etagMatches(header, etag string, weak bool) bool {
+
+	etag = quoteETag(etag)
+
+	for _, candidate := range strings.Split(header, ",") {
+
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" {
+			return true
+		}
+
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if !strings.HasPrefix(candidate, "W/") && candidate == etag {
+			return true
+		}
+
+	}
+
+	return false
+
+}
+
+// withPreconditions wraps the executor for the named action so that the
+// conditional request headers are checked before it is called, if the
+// controller implements controllers.RestfulETagger.
+func (m *ControllerMapping) withPreconditions(name string, executor HandlerExecutionFunc) HandlerExecutionFunc {
+
+	tagger, ok := m.Controller.(controllers.RestfulETagger)
+	if !ok {
+		return executor
+	}
+
+	var safe bool
+	switch name {
+	case controllers.ActionRead:
+		safe = true
+	case controllers.ActionUpdate, controllers.ActionReplace, controllers.ActionDelete:
+		safe = false
+	default:
+		return executor
+	}
+
+	return func(ctx context.Context) error {
+
+		etag, etagErr := tagger.ETag(ctx.PathParams().Get(m.IDParameterName).Str(), ctx)
+		if etagErr != nil {
+			return etagErr
+		}
+
+		header := ctx.HttpRequest().Header
+
+		if safe {
+
+			// no version means no resource, which the action will deal with
+			if len(etag) == 0 {
+				return executor(ctx)
+			}
+
+			SetETag(ctx, etag)
+
+			if ifNoneMatch := header.Get("If-None-Match"); len(ifNoneMatch) > 0 && etagMatches(ifNoneMatch, etag, true) {
+				ctx.HttpResponseWriter().WriteHeader(nethttp.StatusNotModified)
+				return nil
+			}
+
+			return executor(ctx)
+		}
+
+		ifMatch := header.Get("If-Match")
+
+		if len(ifMatch) == 0 {
+
+			if requirer, ok := m.Controller.(controllers.RestfulPreconditionRequirer); ok && requirer.PreconditionRequired() {
+				ctx.HttpResponseWriter().WriteHeader(nethttp.StatusPreconditionRequired)
+				return nil
+			}
+
+			return executor(ctx)
+		}
+
+		if len(etag) == 0 || !etagMatches(ifMatch, etag, false) {
+			ctx.HttpResponseWriter().WriteHeader(nethttp.StatusPreconditionFailed)
+			return nil
+		}
+
+		return executor(ctx)
+	}
+
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+)
+
+type testVersionedController struct {
+	version  string
+	required bool
+	called   string
+}
+
+func (c *testVersionedController) ETag(id string, ctx context.Context) (string, error) {
+	if id != "1" {
+		return "", nil
+	}
+	return c.version, nil
+}
+
+func (c *testVersionedController) PreconditionRequired() bool {
+	return c.required
+}
+
+func (c *testVersionedController) Read(id string, ctx context.Context) error {
+	c.called = "read"
+	return nil
+}
+
+func (c *testVersionedController) Update(id string, ctx context.Context) error {
+	c.called = "update"
+	c.version = "v2"
+	SetETag(ctx, c.version)
+	return nil
+}
+
+func (c *testVersionedController) Delete(id string, ctx context.Context) error {
+	c.called = "delete"
+	return nil
+}
+
+func serveConditionalTestRequest(h *HttpHandler, method, url, header, value string) *http_test.TestResponseWriter {
+	request, _ := http.NewRequest(method, url, nil)
+	if len(header) > 0 {
+		request.Header.Set(header, value)
+	}
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+	return response
+}
+
+func TestETagHelpers(t *testing.T) {
+
+	assert.Equal(t, `"v1"`, quoteETag("v1"))
+	assert.Equal(t, `"v1"`, quoteETag(`"v1"`))
+	assert.Equal(t, `W/"v1"`, quoteETag(`W/"v1"`))
+
+	assert.True(t, etagMatches(`"v0", "v1"`, "v1", false))
+	assert.True(t, etagMatches(`*`, "v1", false))
+	assert.False(t, etagMatches(`W/"v1"`, "v1", false))
+	assert.True(t, etagMatches(`W/"v1"`, "v1", true))
+	assert.False(t, etagMatches(`"v2"`, "v1", true))
+
+}
+
+func TestControllerPreconditions_Read(t *testing.T) {
+
+	c := &testVersionedController{version: "v1"}
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", c)
+
+	response := serveConditionalTestRequest(h, "GET", "http://goweb.org/people/1", "", "")
+	assert.Equal(t, "read", c.called)
+	assert.Equal(t, `"v1"`, response.Header().Get("ETag"))
+
+	c.called = ""
+	response = serveConditionalTestRequest(h, "GET", "http://goweb.org/people/1", "If-None-Match", `"v1"`)
+	assert.Equal(t, "", c.called)
+	assert.Equal(t, http.StatusNotModified, response.StatusCode)
+
+	response = serveConditionalTestRequest(h, "GET", "http://goweb.org/people/1", "If-None-Match", `"v0"`)
+	assert.Equal(t, "read", c.called)
+
+	// missing resources are left to the action
+	c.called = ""
+	response = serveConditionalTestRequest(h, "GET", "http://goweb.org/people/2", "If-None-Match", `*`)
+	assert.Equal(t, "read", c.called)
+	assert.Equal(t, "", response.Header().Get("ETag"))
+
+}
+
+func TestControllerPreconditions_Write(t *testing.T) {
+
+	c := &testVersionedController{version: "v1"}
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", c)
+
+	response := serveConditionalTestRequest(h, "PATCH", "http://goweb.org/people/1", "If-Match", `"v0"`)
+	assert.Equal(t, "", c.called)
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+
+	response = serveConditionalTestRequest(h, "PATCH", "http://goweb.org/people/1", "If-Match", `"v1"`)
+	assert.Equal(t, "update", c.called)
+	assert.Equal(t, `"v2"`, response.Header().Get("ETag"))
+
+	c.called = ""
+	response = serveConditionalTestRequest(h, "DELETE", "http://goweb.org/people/2", "If-Match", `*`)
+	assert.Equal(t, "", c.called)
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+
+	// without If-Match, the action is called unless preconditions are required
+	serveConditionalTestRequest(h, "DELETE", "http://goweb.org/people/1", "", "")
+	assert.Equal(t, "delete", c.called)
+
+	c.called = ""
+	c.required = true
+	response = serveConditionalTestRequest(h, "DELETE", "http://goweb.org/people/1", "", "")
+	assert.Equal(t, "", c.called)
+	assert.Equal(t, http.StatusPreconditionRequired, response.StatusCode)
+
+}
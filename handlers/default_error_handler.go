@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 	"github.com/stretchr/goweb/context"
+	"html"
 	"net/http"
 	"os"
 	"reflect"
@@ -42,6 +43,9 @@ func (h *DefaultErrorHandler) Handle(ctx context.Context) (stop bool, err error)
 	w.Write([]byte(fmt.Sprintf("<h1>Error in <code>%s</code></h1><h2>%s</h2>", handlerError.Handler, handlerError)))
 	w.Write([]byte(fmt.Sprintf("<h3><code>%s</code> error in Handler <code>%v</code></h3> <code><pre>%s</pre></code>", reflect.TypeOf(handlerError.OriginalError), &handlerError.Handler, handlerError.Handler)))
 	w.Write([]byte(fmt.Sprintf("on %s", hostname)))
+	if requestID := RequestID(ctx); len(requestID) > 0 {
+		w.Write([]byte(fmt.Sprintf("<p>Request ID: <code>%s</code></p>", html.EscapeString(requestID))))
+	}
 	w.Write([]byte("<footer>Learn more about <a href='http://github.com/stretchr/goweb' target='_blank'>Goweb</a></footer>"))
 	w.Write([]byte("</body></html>"))
 
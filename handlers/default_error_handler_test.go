@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/stretchr/goweb/context"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDefaultErrorHandler_Handle(t *testing.T) {
+
+	h := new(DefaultErrorHandler)
+	ctx := context_test.MakeTestContext()
+	ctx.Data().Set(DataKeyForError, HandlerError{nil, errors.New("Test error")})
+
+	h.Handle(ctx)
+
+	assert.Equal(t, http.StatusInternalServerError, context_test.TestResponseWriter.StatusCode)
+	assert.True(t, strings.Contains(context_test.TestResponseWriter.Output, "Test error"))
+	assert.False(t, strings.Contains(context_test.TestResponseWriter.Output, "Request ID"))
+
+}
+
+func TestDefaultErrorHandler_Handle_WithRequestID(t *testing.T) {
+
+	h := new(DefaultErrorHandler)
+	ctx := context_test.MakeTestContext()
+	ctx.Data().Set(DataKeyForError, HandlerError{nil, errors.New("Test error")})
+	ctx.Data().Set(context.DataKeyRequestID, "abc123")
+
+	h.Handle(ctx)
+
+	assert.True(t, strings.Contains(context_test.TestResponseWriter.Output, "Request ID: <code>abc123</code>"))
+
+}
+
+func TestDefaultErrorHandler_Handle_WithUnsafeRequestID(t *testing.T) {
+
+	h := new(DefaultErrorHandler)
+	ctx := context_test.MakeTestContext()
+	ctx.Data().Set(DataKeyForError, HandlerError{nil, errors.New("Test error")})
+	ctx.Data().Set(context.DataKeyRequestID, "<script>alert(1)</script>")
+
+	h.Handle(ctx)
+
+	assert.False(t, strings.Contains(context_test.TestResponseWriter.Output, "<script>"))
+	assert.True(t, strings.Contains(context_test.TestResponseWriter.Output, "Request ID: <code>&lt;script&gt;alert(1)&lt;/script&gt;</code>"))
+
+}
+
+func TestDefaultErrorHandler_Handle_WithCustomRequestID(t *testing.T) {
+
+	h := new(DefaultErrorHandler)
+	ctx := context_test.MakeTestContext()
+	ctx.Data().Set(DataKeyForError, HandlerError{nil, errors.New("Test error")})
+
+	// i.e. made by a custom Generator, which DefaultRequestIDValidator rejects
+	requestID := "trace:" + strings.Repeat("0123456789", 20)
+	ctx.Data().Set(context.DataKeyRequestID, requestID)
+
+	h.Handle(ctx)
+
+	assert.True(t, strings.Contains(context_test.TestResponseWriter.Output, "Request ID: <code>"+requestID+"</code>"))
+
+}
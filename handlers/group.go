@@ -0,0 +1,88 @@
+package handlers
+
+// Group maps handlers and controllers in an HttpHandler with a common set of
+// HandlerOptions, such as the host they are for:
+//
+//     api := goweb.DefaultHttpHandler().Group(handlers.Host("api.example.com"))
+//     api.Map("GET", "status", statusHandler)
+//     api.MapController("people", peopleController)
+//
+// The Map functions of a Group take the same arguments as those of the
+// HttpHandler.
+type Group struct {
+
+	// HttpHandler is the HttpHandler the handlers are mapped in.
+	HttpHandler *HttpHandler
+
+	// Options are the HandlerOptions applied to every mapping in the group, after
+	// any options passed to the Map function.
+	Options []HandlerOption
+}
+
+// Group makes a Group that maps handlers in the HttpHandler with the specified
+// HandlerOptions.
+func (h *HttpHandler) Group(options ...HandlerOption) *Group {
+	return &Group{HttpHandler: h, Options: options}
+}
+
+// Group makes a Group nested in this one, which maps handlers with the options
+// of this group as well as the specified ones.
+func (g *Group) Group(options ...HandlerOption) *Group {
+	return &Group{HttpHandler: g.HttpHandler, Options: append(append([]HandlerOption(nil), g.Options...), options...)}
+}
+
+// Map maps a handler in the processing pipe, with the options of the group.
+//
+// For usage information, see goweb.Map.
+func (g *Group) Map(options ...interface{}) (Handler, error) {
+	return g.HttpHandler.Map(g.withOptions(options)...)
+}
+
+// MapBefore maps a handler to be executed before processing, with the options
+// of the group.
+//
+// For usage information, see goweb.Map.
+func (g *Group) MapBefore(options ...interface{}) (Handler, error) {
+	return g.HttpHandler.MapBefore(g.withOptions(options)...)
+}
+
+// MapAfter maps a handler to be executed after processing, with the options of
+// the group.
+//
+// For usage information, see goweb.Map.
+func (g *Group) MapAfter(options ...interface{}) (Handler, error) {
+	return g.HttpHandler.MapAfter(g.withOptions(options)...)
+}
+
+// MapController maps a controller, with the options of the group.
+//
+// For usage information, see goweb.MapController.
+func (g *Group) MapController(options ...interface{}) error {
+	return g.HttpHandler.MapController(g.withOptions(options)...)
+}
+
+// MapControllerMapping maps a controller, with the options of the group, and
+// returns its ControllerMapping.
+//
+// For usage information, see goweb.MapControllerMapping.
+func (g *Group) MapControllerMapping(options ...interface{}) (*ControllerMapping, error) {
+	return g.HttpHandler.MapControllerMapping(g.withOptions(options)...)
+}
+
+// withOptions gets the arguments for a Map function with the options of the
+// group added to the end.
+func (g *Group) withOptions(options []interface{}) []interface{} {
+
+	// handler objects are mapped as they are
+	if len(options) == 0 || len(g.Options) == 0 {
+		return options
+	}
+	if _, ok := options[0].(Handler); ok {
+		return options
+	}
+
+	withOptions := make([]interface{}, 0, len(options)+1)
+	withOptions = append(withOptions, options...)
+	return append(withOptions, g.Options)
+
+}
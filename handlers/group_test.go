@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	api := h.Group(Host("api.example.com"))
+	v2 := api.Group(Version("2"))
+
+	var called []string
+	record := func(name string) func(c context.Context) error {
+		return func(c context.Context) error {
+			called = append(called, name)
+			return nil
+		}
+	}
+
+	api.MapBefore("***", record("before"))
+	api.Map("GET", "status", record("status"))
+	v2.Map("GET", "people", record("people"), Name("people.v2"))
+	api.MapAfter("***", record("after"))
+	api.MapController("books", new(testBooksController))
+
+	status := h.HandlersPipe()[0].(*PathMatchHandler)
+	assert.Equal(t, "api.example.com", status.HostPattern.RawHost)
+	assert.Equal(t, "", status.Version)
+
+	people := h.HandlersPipe()[1].(*PathMatchHandler)
+	assert.Equal(t, "api.example.com", people.HostPattern.RawHost)
+	assert.Equal(t, "2", people.Version)
+	assert.Equal(t, "people.v2", people.Name)
+
+	for _, route := range h.Routes() {
+		assert.Equal(t, "api.example.com", route.Host, route.Pattern)
+	}
+
+	serveTestRequest(h, "GET", "http://api.example.com/status")
+	assert.Equal(t, []string{"before", "status", "after"}, called)
+
+	called = nil
+	serveTestRequest(h, "GET", "http://www.example.com/status")
+	assert.Empty(t, called)
+
+}
@@ -0,0 +1,41 @@
+package handlers
+
+// HandlerOption is a func that configures the PathMatchHandler made by Map,
+// MapBefore, MapAfter or MapController.  HandlerOptions can be passed to them
+// alongside MatcherFuncs:
+//
+//     goweb.Map("GET", "people", peopleHandler, handlers.Version("2"))
+//
+// If a HandlerOption returns an error, the mapping fails with that error.
+type HandlerOption func(handler *PathMatchHandler) error
+
+// findHandlerOptions gets the HandlerOptions from the specified options.
+func findHandlerOptions(options ...interface{}) []HandlerOption {
+	var handlerOptions []HandlerOption
+	for _, option := range options {
+		switch option := option.(type) {
+		case HandlerOption:
+			handlerOptions = append(handlerOptions, option)
+		case []HandlerOption:
+			handlerOptions = append(handlerOptions, option...)
+		}
+	}
+	return handlerOptions
+}
+
+// Name is a HandlerOption that names the mapping, so it can be identified in
+// the route table (see HttpHandler.Routes).
+func Name(name string) HandlerOption {
+	return func(handler *PathMatchHandler) error {
+		handler.Name = name
+		return nil
+	}
+}
+
+// Description is a HandlerOption that describes the mapping.
+func Description(description string) HandlerOption {
+	return func(handler *PathMatchHandler) error {
+		handler.Description = description
+		return nil
+	}
+}
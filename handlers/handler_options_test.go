@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFindHandlerOptions(t *testing.T) {
+
+	option1 := Version("1")
+	option2 := Version("2")
+
+	options := findHandlerOptions("GET", option1, []HandlerOption{option2}, []MatcherFunc{})
+	assert.Equal(t, 2, len(options))
+
+}
+
+func TestMap_WithHandlerOptions(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var applied *PathMatchHandler
+	handler, err := h.Map("GET", "people", func(c context.Context) error {
+		return nil
+	}, HandlerOption(func(handler *PathMatchHandler) error {
+		applied = handler
+		return nil
+	}))
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, handler, applied)
+	}
+
+	optionErr := errors.New("bad option")
+	_, err = h.Map("GET", "people", func(c context.Context) error {
+		return nil
+	}, HandlerOption(func(handler *PathMatchHandler) error {
+		return optionErr
+	}))
+
+	assert.Equal(t, optionErr, err)
+	assert.Equal(t, 1, len(h.HandlersPipe()))
+
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/paths"
+)
+
+// Host is a HandlerOption that maps handlers (or controllers) for requests to
+// hosts that match the specified pattern.  {dynamic} labels in the pattern are
+// added to the PathParams:
+//
+//     goweb.Map("GET", "dashboard", dashboardHandler, handlers.Host("{tenant}.example.com"))
+//
+//     // in the handler
+//     tenant := ctx.PathValue("tenant")
+//
+// See paths.HostPattern for the patterns that can be used.  To map lots of
+// handlers for the same host, use a Group.
+func Host(pattern string) HandlerOption {
+	return func(handler *PathMatchHandler) error {
+		hostPattern, err := paths.NewHostPattern(pattern)
+		if err != nil {
+			return err
+		}
+		handler.HostPattern = hostPattern
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMap_Host(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var called, tenant, id string
+	h.Map("GET", "people/{id}", func(c context.Context) error {
+		called, tenant, id = "tenant", c.PathValue("tenant"), c.PathValue("id")
+		return nil
+	}, Host("{tenant}.example.com"))
+	h.Map("GET", "people/{id}", func(c context.Context) error {
+		called, tenant, id = "any", c.PathValue("tenant"), c.PathValue("id")
+		return nil
+	})
+
+	serveTestRequest(h, "GET", "http://acme.example.com/people/123")
+	assert.Equal(t, "tenant", called)
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, "123", id)
+
+	serveTestRequest(h, "GET", "http://example.com/people/456")
+	assert.Equal(t, "any", called)
+	assert.Equal(t, "", tenant)
+	assert.Equal(t, "456", id)
+
+	assert.Empty(t, h.Conflicts(), "Handlers for specific hosts don't hide the others")
+	assert.Contains(t, h.String(), "people/{id} (host {tenant}.example.com)")
+
+	explanations, _ := h.Explain("GET", "http://example.com/people/456")
+	assert.Equal(t, "host example.com does not match {tenant}.example.com", explanations[0].Reason)
+	assert.Equal(t, "{tenant}.example.com", explanations[0].Host)
+
+}
+
+func TestMap_Host_Invalid(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	_, err := h.Map("GET", "people", func(c context.Context) error {
+		return nil
+	}, Host("[tenant].example.com"))
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(h.HandlersPipe()))
+
+}
+
+func TestMap_Host_Conflicts(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("GET", "people/{id}", noopHandler, Host("api.example.com"))
+	h.Map("GET", "people/{id}", noopHandler, Host("{tenant}.example.com"))
+	h.Map("GET", "people/{id}", noopHandler, Host("www.example.org"))
+	assert.Empty(t, h.Conflicts(), "More specific hosts first is fine")
+
+	h = NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("GET", "people/{id}", noopHandler, Host("{tenant}.example.com"))
+	h.Map("GET", "people/{id}", noopHandler, Host("api.example.com"))
+
+	if assert.Equal(t, 1, len(h.Conflicts())) {
+		assert.Equal(t, ConflictUnreachable, h.Conflicts()[0].Kind)
+	}
+
+	h = NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("GET", "people/{id}", noopHandler, Host("{tenant}.example.com"))
+	h.Map("GET", "people/me", noopHandler, Host("api.*.com"))
+
+	if assert.Equal(t, 1, len(h.Conflicts())) {
+		assert.Equal(t, ConflictAmbiguous, h.Conflicts()[0].Kind)
+	}
+
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"fmt"
 	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
 	gowebhttp "github.com/stretchr/goweb/http"
+	"github.com/stretchr/goweb/paths"
 	"github.com/stretchr/goweb/webcontext"
 	"github.com/stretchr/objx"
 	"net/http"
@@ -15,6 +17,10 @@ const (
 	// an error that has occurred.  Then, the error Handler can Get(DataKeyForError)
 	// to do work on the error.
 	DataKeyForError string = "error"
+
+	// dataKeyHandlerObservers is the data key for the HandlerObservers that
+	// the Pipes will notify.
+	dataKeyHandlerObservers string = "handlerobservers"
 )
 
 type HttpHandler struct {
@@ -30,10 +36,67 @@ type HttpHandler struct {
 	// errorHandler represents the Handler that will be used to handle errors.
 	errorHandler Handler
 
+	// observers are notified when each request starts and finishes.
+	observers []Observer
+
 	// Data contains the initial data object that gets copied to each
 	// context object.
 	Data objx.Map
 
+	// MethodOverridePolicy decides whether clients may override the HTTP
+	// method of requests or not.  If nil (the default), the method is never
+	// overridden.
+	MethodOverridePolicy *MethodOverridePolicy
+
+	// CanonicalPathPolicy decides the canonical form of paths, and redirects
+	// requests for other forms of them.  If nil (the default), requests are never
+	// redirected.
+	CanonicalPathPolicy *CanonicalPathPolicy
+
+	// CaseSensitive indicates whether the literal segments of path patterns only
+	// match paths with the same case (so people doesn't match People).  Change it
+	// before mapping any handlers, as it applies to the mappings made after it is
+	// changed.
+	CaseSensitive bool
+
+	// EncodedSlashes indicates whether percent-encoded slashes (%2F) in request
+	// paths should stay part of the segment they are in, so files/a%2Fb matches
+	// files/{name} with a name of "a/b".  Otherwise (the default), they are
+	// decoded before paths are split into segments, like any other character.
+	// See paths.NewPathFromURL.
+	EncodedSlashes bool
+
+	// VersioningPolicy decides which version of the API requests are for.  If nil
+	// (the default), handlers mapped with a Version never handle requests.
+	VersioningPolicy *VersioningPolicy
+
+	// AutomaticHead indicates whether HEAD requests should be answered by the
+	// handlers mapped for GET (with the response body discarded) when no
+	// handler has been mapped for HEAD explicitly.  NewHttpHandler sets it to
+	// true.
+	//
+	// Change it before calling MapController, as it also decides whether HEAD
+	// appears in the Allow headers of the generated OPTIONS handlers.
+	AutomaticHead bool
+
+	// StrictMapping indicates whether Map should fail with a *Conflict error when
+	// a mapping conflicts with one made before it (i.e. people/{id}/photo mapped
+	// after people/***, which handles all its requests).  Otherwise, conflicts are
+	// logged, and kept for the Conflicts method.
+	StrictMapping bool
+
+	// OrderBySpecificity indicates whether Map should put handlers in the processing
+	// pipe in order of how specific their path patterns are, rather than the order
+	// they were mapped in, so people/me is tried before people/{id} whichever is
+	// mapped first.  See paths.PathPattern.MoreSpecificThan.
+	//
+	// Handlers with equally specific patterns (and handlers that aren't
+	// PathMatchHandlers) stay in the order they were mapped.
+	OrderBySpecificity bool
+
+	// conflicts are the conflicts found when mappings were made.
+	conflicts []*Conflict
+
 	// HttpMethodForCreate is the HTTP method to use for this action when mapping controllers.
 	HttpMethodForCreate string
 	// HttpMethodForReadOne is the HTTP method to use for this action when mapping controllers.
@@ -90,6 +153,9 @@ func NewHttpHandler(codecService codecsservices.CodecService) *HttpHandler {
 	h.HttpMethodForHead = gowebhttp.MethodHead
 	h.HttpMethodForOptions = gowebhttp.MethodOptions
 
+	// answer HEAD requests using GET mappings
+	h.AutomaticHead = true
+
 	return h
 }
 
@@ -102,22 +168,104 @@ func (handler *HttpHandler) CodecService() codecsservices.CodecService {
 // ServeHTTP servers the actual HTTP request by buidling a context and running
 // it through all the handlers.
 func (handler *HttpHandler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
-	// override the method if needed
-	method := request.Header.Get("X-HTTP-Method-Override")
-	if method != "" {
-		request.Method = method
+	// should the client be sent to the canonical path instead?
+	var canonicalURL string
+	var redirect bool
+	if handler.CanonicalPathPolicy != nil {
+		canonicalURL, redirect = handler.CanonicalPathPolicy.redirectFor(request)
+	}
+
+	// override the method if allowed
+	var originalMethod string
+	var methodOverridden bool
+	if handler.MethodOverridePolicy != nil {
+		originalMethod, methodOverridden = handler.MethodOverridePolicy.Override(request)
+	}
+
+	// work out the version (which may change the path)
+	var version string
+	if handler.VersioningPolicy != nil {
+		version = handler.VersioningPolicy.Resolve(request)
 	}
 
 	// make the context
 	ctx := webcontext.NewWebContext(responseWriter, request, handler.codecService)
+	if handler.EncodedSlashes {
+		ctx.SetPath(paths.NewPathFromURL(request.URL))
+	}
 
 	// copy the data
 	for k, v := range handler.Data {
 		ctx.Data()[k] = v
 	}
 
-	// run it through the handlers
-	_, err := handler.Handlers.Handle(ctx)
+	if methodOverridden {
+		ctx.Data().Set(context.DataKeyOriginalMethod, originalMethod)
+	}
+
+	if len(version) > 0 {
+		ctx.Data().Set(context.DataKeyVersion, version)
+		handler.VersioningPolicy.WriteHeaders(version, responseWriter.Header())
+	}
+
+	// answer HEAD requests using the GET mappings?
+	var headWriter *headResponseWriter
+	if handler.AutomaticHead && ctx.MethodString() == gowebhttp.MethodHead {
+
+		explicit, explicitErr := handlesHeadExplicitly(handler.HandlersPipe(), ctx)
+		if explicitErr == nil && !explicit {
+			headWriter = newHeadResponseWriter(responseWriter)
+			ctx.SetHttpResponseWriter(headWriter)
+			ctx.Data().Set(dataKeyAutomaticHead, true)
+		}
+
+	}
+
+	// http.ServeFile adds (or removes) trailing slashes itself, so leave them
+	// alone for static files to avoid redirect loops
+	if redirect && handler.CanonicalPathPolicy.TrailingSlash != TrailingSlashIgnore {
+		if static, staticErr := servesFiles(handler.HandlersPipe(), ctx); staticErr == nil && static {
+			canonicalURL, redirect = handler.CanonicalPathPolicy.ignoringTrailingSlash().redirectFor(request)
+		}
+	}
+
+	// tell the observers we're starting
+	var handlerObservers []HandlerObserver
+	for _, observer := range handler.observers {
+		observer.RequestStarted(ctx)
+		if handlerObserver, ok := observer.(HandlerObserver); ok {
+			handlerObservers = append(handlerObservers, handlerObserver)
+		}
+	}
+
+	// let the pipes know who else is interested
+	if len(handlerObservers) > 0 {
+		ctx.Data().Set(dataKeyHandlerObservers, handlerObservers)
+	}
+
+	// tell the observers about requests whose handlers panic too, so they can
+	// clean up, before letting the panic carry on
+	finished := false
+	defer func() {
+		if finished {
+			return
+		}
+		if recovered := recover(); recovered != nil {
+			panicErr := fmt.Errorf("goweb: handler panicked: %v", recovered)
+			for i := len(handler.observers) - 1; i >= 0; i-- {
+				handler.observers[i].RequestFinished(ctx, panicErr)
+			}
+			panic(recovered)
+		}
+	}()
+
+	// run it through the handlers (unless it's being redirected)
+	var err error
+	if redirect {
+		handler.CanonicalPathPolicy.redirect(ctx, canonicalURL)
+	} else {
+		_, err = handler.Handlers.Handle(ctx)
+	}
 
 	// do we need to handle an error?
 	if err != nil {
@@ -130,6 +278,17 @@ func (handler *HttpHandler) ServeHTTP(responseWriter http.ResponseWriter, reques
 
 	}
 
+	// send the headers without the body
+	if headWriter != nil {
+		headWriter.finish()
+	}
+
+	// tell the observers we're finished (in reverse order)
+	finished = true
+	for i := len(handler.observers) - 1; i >= 0; i-- {
+		handler.observers[i].RequestFinished(ctx, err)
+	}
+
 }
 
 // ErrorHandler gets the Handler that will be used to handle errors.
@@ -164,6 +323,20 @@ func (h *HttpHandler) SetErrorHandler(errorHandler Handler) {
 	h.errorHandler = errorHandler
 }
 
+// AddObserver adds an Observer that will be notified when each request
+// starts and finishes.
+//
+// Observers are notified of the start of a request in the order in which they
+// were added, and of the end of a request in reverse order.
+func (h *HttpHandler) AddObserver(observer Observer) {
+	h.observers = append(h.observers, observer)
+}
+
+// Observers gets the Observers that have been added to this HttpHandler.
+func (h *HttpHandler) Observers() []Observer {
+	return h.observers
+}
+
 // HandlersPipe gets the pipe for handlers.
 func (h *HttpHandler) HandlersPipe() Pipe {
 	return h.Handlers[1].(Pipe)
@@ -184,6 +357,15 @@ func (h *HttpHandler) AppendHandler(handler Handler) {
 	h.Handlers[1] = h.HandlersPipe().AppendHandler(handler)
 }
 
+// insertHandler inserts a handler into the processing pipe at the specified index.
+func (h *HttpHandler) insertHandler(index int, handler Handler) {
+	pipe := h.HandlersPipe()
+	inserted := make(Pipe, 0, len(pipe)+1)
+	inserted = append(inserted, pipe[:index]...)
+	inserted = append(inserted, handler)
+	h.Handlers[1] = append(inserted, pipe[index:]...)
+}
+
 // AppendPreHandler appends a handler to be executed before processing begins.
 func (h *HttpHandler) AppendPreHandler(handler Handler) {
 	h.Handlers[0] = h.PreHandlersPipe().AppendHandler(handler)
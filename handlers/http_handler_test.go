@@ -199,9 +199,10 @@ func TestServeHTTPMethodOverride(t *testing.T) {
 
 	responseWriter := new(http_test.TestResponseWriter)
 	testRequest, _ := http.NewRequest("POST", "http://stretchr.org/goweb", nil)
-	testRequest.Header.Set("X-HTTP-Method-Override", "GET")
+	testRequest.Header.Set("X-HTTP-Method-Override", "PUT")
 	codecService := codecsservices.NewWebCodecService()
 	handler := NewHttpHandler(codecService)
+	handler.MethodOverridePolicy = NewMethodOverridePolicy(MethodOverrideHeader)
 
 	// setup some test handlers
 	handler1 := new(handlers_test.TestHandler)
@@ -237,6 +238,31 @@ func TestServeHTTPMethodOverride(t *testing.T) {
 	assert.Equal(t, ctx1, ctx2, "Contexts should be the same")
 	assert.Equal(t, ctx2, ctx3, "Contexts should be the same")
 
+	// the method should have been overridden
+	assert.Equal(t, "PUT", ctx.MethodString())
+	assert.Equal(t, "POST", OriginalMethod(ctx))
+	assert.Equal(t, "POST", ctx.Data().Get(context.DataKeyOriginalMethod).Str())
+
+}
+
+func TestServeHTTPMethodOverride_DisabledByDefault(t *testing.T) {
+
+	testRequest, _ := http.NewRequest("POST", "http://stretchr.org/goweb?method=DELETE", nil)
+	testRequest.Header.Set("X-HTTP-Method-Override", "PUT")
+	codecService := codecsservices.NewWebCodecService()
+	handler := NewHttpHandler(codecService)
+
+	var method string
+	handler.Map("goweb", func(c context.Context) error {
+		method = c.MethodString()
+		assert.Equal(t, "POST", OriginalMethod(c))
+		return nil
+	})
+
+	handler.ServeHTTP(new(http_test.TestResponseWriter), testRequest)
+
+	assert.Equal(t, "POST", method)
+
 }
 
 /*
@@ -296,3 +322,136 @@ func TestErrorHandlerGetsUsedOnError(t *testing.T) {
 	}
 
 }
+
+/*
+	Observers
+*/
+
+type testObserver struct {
+	name   string
+	events *[]string
+}
+
+func (o *testObserver) RequestStarted(ctx context.Context) {
+	*o.events = append(*o.events, o.name+" started")
+}
+
+func (o *testObserver) RequestFinished(ctx context.Context, err error) {
+	if err != nil {
+		*o.events = append(*o.events, o.name+" finished with error")
+	} else {
+		*o.events = append(*o.events, o.name+" finished")
+	}
+}
+
+func TestObserversAreNotified(t *testing.T) {
+
+	codecService := codecsservices.NewWebCodecService()
+	handler := NewHttpHandler(codecService)
+
+	var events []string
+	handler.AddObserver(&testObserver{"one", &events})
+	handler.AddObserver(&testObserver{"two", &events})
+	assert.Equal(t, 2, len(handler.Observers()))
+
+	handler.Map("people", func(c context.Context) error {
+		events = append(events, "handled")
+		return nil
+	})
+
+	testRequest, _ := http.NewRequest("GET", "http://stretchr.org/people", nil)
+	handler.ServeHTTP(new(http_test.TestResponseWriter), testRequest)
+
+	assert.Equal(t, []string{"one started", "two started", "handled", "two finished", "one finished"}, events)
+
+}
+
+func TestObserversAreNotifiedOfErrors(t *testing.T) {
+
+	codecService := codecsservices.NewWebCodecService()
+	handler := NewHttpHandler(codecService)
+
+	errorHandler := new(handlers_test.TestHandler)
+	errorHandler.On("Handle", mock.Anything).Return(false, nil)
+	handler.SetErrorHandler(errorHandler)
+
+	var events []string
+	handler.AddObserver(&testObserver{"one", &events})
+
+	handler.Map("people", func(c context.Context) error {
+		return errors.New("Test error")
+	})
+
+	testRequest, _ := http.NewRequest("GET", "http://stretchr.org/people", nil)
+	handler.ServeHTTP(new(http_test.TestResponseWriter), testRequest)
+
+	assert.Equal(t, []string{"one started", "one finished with error"}, events)
+
+	// the route should have been recorded
+	ctx := errorHandler.Calls[0].Arguments[0].(context.Context)
+	assert.Equal(t, "people", ctx.Data().Get(context.DataKeyRoute).Str())
+
+}
+
+func TestObserversAreNotified_Panic(t *testing.T) {
+
+	handler := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var events []string
+	handler.AddObserver(&testObserver{"one", &events})
+
+	handler.Map("people", func(c context.Context) error {
+		panic("oops")
+	})
+
+	testRequest, _ := http.NewRequest("GET", "http://stretchr.org/people", nil)
+	assert.Panics(t, func() {
+		handler.ServeHTTP(new(http_test.TestResponseWriter), testRequest)
+	})
+
+	assert.Equal(t, []string{"one started", "one finished with error"}, events)
+
+}
+
+type testHandlerObserver struct {
+	testObserver
+}
+
+func (o *testHandlerObserver) HandlerStarted(ctx context.Context, handler Handler) {
+	if _, ok := handler.(*PathMatchHandler); ok {
+		*o.events = append(*o.events, o.name+" handler started")
+	}
+}
+
+func (o *testHandlerObserver) HandlerFinished(ctx context.Context, handler Handler, err error) {
+	if _, ok := handler.(*PathMatchHandler); ok {
+		if err != nil {
+			*o.events = append(*o.events, o.name+" handler finished with error")
+		} else {
+			*o.events = append(*o.events, o.name+" handler finished")
+		}
+	}
+}
+
+func TestHandlerObserversAreNotified(t *testing.T) {
+
+	codecService := codecsservices.NewWebCodecService()
+	handler := NewHttpHandler(codecService)
+
+	var events []string
+	handler.AddObserver(&testHandlerObserver{testObserver{"one", &events}})
+
+	handler.MapBefore(func(c context.Context) error {
+		return nil
+	})
+	handler.Map("people", func(c context.Context) error {
+		events = append(events, "handled")
+		return errors.New("Test error")
+	})
+
+	testRequest, _ := http.NewRequest("GET", "http://stretchr.org/people", nil)
+	handler.ServeHTTP(new(http_test.TestResponseWriter), testRequest)
+
+	assert.Equal(t, []string{"one started", "one handler started", "one handler finished", "one handler started", "handled", "one handler finished with error", "one finished with error"}, events)
+
+}
@@ -3,11 +3,10 @@ package handlers
 import (
 	"fmt"
 	"github.com/stretchr/goweb/context"
-	"github.com/stretchr/goweb/controllers"
 	"github.com/stretchr/goweb/http"
 	"github.com/stretchr/goweb/paths"
-	stewstrings "github.com/stretchr/stew/strings"
 	nethttp "net/http"
+	"path"
 	"strings"
 )
 
@@ -42,8 +41,10 @@ func findMatcherFuncs(options ...interface{}) []MatcherFunc {
 		case []MatcherFunc:
 			matchers := options[i].([]MatcherFunc)
 			matcherFuncs = append(matcherFuncs, matchers...)
+		case HandlerOption, []HandlerOption:
+			// see findHandlerOptions
 		default:
-			panic(fmt.Sprintf("goweb: Argument %d (index %d) passed to Map must be of type MatcherFunc or []MatcherFunc, but was %s.", i+1, i, options[i]))
+			panic(fmt.Sprintf("goweb: Argument %d (index %d) passed to Map must be of type MatcherFunc, []MatcherFunc or HandlerOption, but was %s.", i+1, i, options[i]))
 		}
 	}
 	return matcherFuncs
@@ -60,7 +61,7 @@ func (h *HttpHandler) handlerForOptions(options ...interface{}) (Handler, error)
 
 	var matcherFuncStartPos int = -1
 	var methods []string
-	var path string
+	var path interface{} // string or Regexp
 	var executor HandlerExecutionFunc
 
 	switch options[0].(type) {
@@ -69,7 +70,7 @@ func (h *HttpHandler) handlerForOptions(options ...interface{}) (Handler, error)
 		switch options[1].(type) {
 		case nil:
 			panic("goweb: Cannot call Map with 2nd argument nil.")
-		case string: // (method|methods, path, executor, ...)
+		case string, Regexp: // (method|methods, path, executor, ...)
 
 			// get the methods from the arguments
 			switch options[0].(type) {
@@ -79,14 +80,18 @@ func (h *HttpHandler) handlerForOptions(options ...interface{}) (Handler, error)
 				methods = []string{options[0].(string)}
 			}
 
-			path = options[1].(string)
+			path = options[1]
 			executor = options[2].(func(context.Context) error)
 			matcherFuncStartPos = 3
 		default: // (path, executor, ...)
-			path = options[0].(string)
+			path = options[0]
 			executor = options[1].(func(context.Context) error)
 			matcherFuncStartPos = 2
 		}
+	case Regexp: // (regexp, executor, ...)
+		path = options[0]
+		executor = options[1].(func(context.Context) error)
+		matcherFuncStartPos = 2
 	case Handler: // actual handler object
 		return options[0].(Handler), nil
 	default: // (executor)
@@ -98,12 +103,14 @@ func (h *HttpHandler) handlerForOptions(options ...interface{}) (Handler, error)
 	// collect the matcher funcs
 	var matcherFuncs []MatcherFunc = findMatcherFuncs(options[matcherFuncStartPos:]...)
 
-	pathPattern, pathErr := paths.NewPathPattern(path)
+	pathPattern, pathErr := newPathPattern(path)
 
 	if pathErr != nil {
 		return nil, pathErr
 	}
 
+	pathPattern.CaseSensitive = h.CaseSensitive
+
 	handler := NewPathMatchHandler(pathPattern, executor)
 
 	// did they specify a method?
@@ -114,11 +121,27 @@ func (h *HttpHandler) handlerForOptions(options ...interface{}) (Handler, error)
 	// do we have any MatcherFuncs?
 	handler.MatcherFuncs = matcherFuncs
 
+	// apply the options
+	for _, option := range findHandlerOptions(options[matcherFuncStartPos:]...) {
+		if optionErr := option(handler); optionErr != nil {
+			return nil, optionErr
+		}
+	}
+
 	// return the handler
 	return handler, nil
 
 }
 
+// newPathPattern makes the PathPattern for the path argument of Map, which is
+// either a path pattern string or a Regexp.
+func newPathPattern(path interface{}) (*paths.PathPattern, error) {
+	if regex, ok := path.(Regexp); ok {
+		return paths.NewRegexpPathPattern(string(regex))
+	}
+	return paths.NewPathPattern(path.(string))
+}
+
 // Map maps a handler function to a specified path and optional HTTP method.
 //
 // For usage information, see goweb.Map.
@@ -136,13 +159,45 @@ func (h *HttpHandler) Map(options ...interface{}) (Handler, error) {
 		return nil, err
 	}
 
-	// append the handler
-	h.AppendHandler(handler)
+	// work out where it goes
+	index := h.indexForHandler(handler)
+
+	// make sure it can be reached
+	if conflictErr := h.checkConflicts(handler, index); conflictErr != nil {
+		return nil, conflictErr
+	}
+
+	// add the handler
+	h.insertHandler(index, handler)
 
 	return handler, nil
 
 }
 
+// indexForHandler gets the index in the processing pipe that Map should put the
+// handler at, which is the end unless OrderBySpecificity is set.
+func (h *HttpHandler) indexForHandler(handler Handler) int {
+
+	pipe := h.HandlersPipe()
+
+	pathMatchHandler, ok := handler.(*PathMatchHandler)
+	if !h.OrderBySpecificity || !ok {
+		return len(pipe)
+	}
+
+	// go before the first less specific handler
+	for index, existing := range pipe {
+		if existing, ok := existing.(*PathMatchHandler); ok {
+			if pathMatchHandler.PathPattern.MoreSpecificThan(existing.PathPattern) {
+				return index
+			}
+		}
+	}
+
+	return len(pipe)
+
+}
+
 // Map maps a handler function to a specified path and optional HTTP method
 // to be executed before any other handlers.
 //
@@ -185,148 +240,28 @@ func (h *HttpHandler) MapAfter(options ...interface{}) (Handler, error) {
 //
 // For more information, see goweb.MapController.
 func (h *HttpHandler) MapController(options ...interface{}) error {
+	_, mapErr := h.MapControllerMapping(options...)
+	return mapErr
+}
 
-	var matcherFuncStartPos int = -1
-	var path string
-	var controller interface{}
+// MapControllerMapping maps a controller like MapController, and returns the
+// ControllerMapping, which can be used to nest other controllers beneath it.
+//
+// For more information, see goweb.MapControllerMapping.
+func (h *HttpHandler) MapControllerMapping(options ...interface{}) (*ControllerMapping, error) {
 
 	if len(options) == 0 {
 		// no arguments is an error
 		panic("goweb: Cannot call MapController with no arguments")
 	}
 
-	switch options[0].(type) {
-	case string: // (path, controller)
-		if len(options) == 1 {
-			// we need more than just a string
-			panic("goweb: Cannot call MapController without a Controller")
-		}
-		path = options[0].(string)
-		controller = options[1]
-		matcherFuncStartPos = 2
-	default: // (controller)
-		if restfulController, ok := options[0].(controllers.RestfulController); ok {
-			controller = restfulController
-			path = restfulController.Path()
-		} else {
-			// use the default path
-			controller = options[0]
-			path = paths.PathPrefixForClass(options[0])
-		}
-		matcherFuncStartPos = 1
-	}
-
-	// store the matcher function slice
-	var matcherFuncs []MatcherFunc = findMatcherFuncs(options[matcherFuncStartPos:]...)
-
-	// get the specialised paths that we might need
-	pathWithID := stewstrings.MergeStrings(path, "/{", RestfulIDParameterName, "}")         // e.g.  people/123
-	pathWithOptionalID := stewstrings.MergeStrings(path, "/[", RestfulIDParameterName, "]") // e.g.  people/[123]
-
-	// get the HTTP methods that we will end up mapping
-	collectiveMethods := optionsListForResourceCollection(h, controller)
-	singularMethods := optionsListForSingleResource(h, controller)
-
-	// BeforeHandler
-	if beforeController, ok := controller.(controllers.BeforeHandler); ok {
-
-		// map the collective before handler
-		h.MapBefore(collectiveMethods, path, beforeController.Before, matcherFuncs)
-
-		// map the singular before handler
-		h.MapBefore(singularMethods, pathWithID, beforeController.Before, matcherFuncs)
-
-	}
-
-	// AfterHandler
-	if afterController, ok := controller.(controllers.AfterHandler); ok {
-
-		// map the collective after handler
-		h.MapAfter(collectiveMethods, path, afterController.After, matcherFuncs)
-
-		// map the singular after handler
-		h.MapAfter(singularMethods, pathWithID, afterController.After, matcherFuncs)
+	mapping := newControllerMapping(h, nil, options...)
 
+	if mapErr := mapping.mapActions(); mapErr != nil {
+		return nil, mapErr
 	}
 
-	// POST /resource  -  Create
-	if restfulController, ok := controller.(controllers.RestfulCreator); ok {
-		h.Map(h.HttpMethodForCreate, path, restfulController.Create, matcherFuncs)
-	}
-
-	// GET /resource/{id}  -  Read
-	if restfulController, ok := controller.(controllers.RestfulReader); ok {
-		h.Map(h.HttpMethodForReadOne, pathWithID, func(ctx context.Context) error {
-			return restfulController.Read(ctx.PathParams().Get(RestfulIDParameterName).Str(), ctx)
-		}, matcherFuncs)
-	}
-
-	// GET /resource  -  ReadMany
-	if restfulController, ok := controller.(controllers.RestfulManyReader); ok {
-		h.Map(h.HttpMethodForReadMany, path, restfulController.ReadMany, matcherFuncs)
-	}
-
-	// DELETE /resource/{id}  -  Delete
-	if restfulController, ok := controller.(controllers.RestfulDeletor); ok {
-		h.Map(h.HttpMethodForDeleteOne, pathWithID, func(ctx context.Context) error {
-			return restfulController.Delete(ctx.PathParams().Get(RestfulIDParameterName).Str(), ctx)
-		}, matcherFuncs)
-	}
-
-	// DELETE /resource  -  DeleteMany
-	if restfulController, ok := controller.(controllers.RestfulManyDeleter); ok {
-		h.Map(h.HttpMethodForDeleteMany, path, restfulController.DeleteMany, matcherFuncs)
-	}
-
-	// PATCH /resource/{id}  -  Update
-	if restfulController, ok := controller.(controllers.RestfulUpdater); ok {
-		h.Map(h.HttpMethodForUpdateOne, pathWithID, func(ctx context.Context) error {
-			return restfulController.Update(ctx.PathParams().Get(RestfulIDParameterName).Str(), ctx)
-		}, matcherFuncs)
-	}
-
-	// PATCH /resource  -  UpdateMany
-	if restfulController, ok := controller.(controllers.RestfulManyUpdater); ok {
-		h.Map(h.HttpMethodForUpdateMany, path, restfulController.UpdateMany, matcherFuncs)
-	}
-
-	// PUT /resource/{id}  -  Replace
-	if restfulController, ok := controller.(controllers.RestfulReplacer); ok {
-		h.Map(h.HttpMethodForReplace, pathWithID, func(ctx context.Context) error {
-			return restfulController.Replace(ctx.PathParams().Get(RestfulIDParameterName).Str(), ctx)
-		}, matcherFuncs)
-	}
-
-	// HEAD /resource/[id]  -  Head
-	if restfulController, ok := controller.(controllers.RestfulHead); ok {
-		h.Map(h.HttpMethodForHead, pathWithOptionalID, restfulController.Head, matcherFuncs)
-	}
-
-	// OPTIONS /resource/[id]  -  Options
-	if restfulController, ok := controller.(controllers.RestfulOptions); ok {
-
-		h.Map(h.HttpMethodForOptions, pathWithOptionalID, restfulController.Options, matcherFuncs)
-
-	} else {
-
-		// use the default options implementation
-
-		h.Map(http.MethodOptions, path, func(ctx context.Context) error {
-			ctx.HttpResponseWriter().Header().Set("Allow", strings.Join(collectiveMethods, ","))
-			ctx.HttpResponseWriter().WriteHeader(200)
-			return nil
-		}, matcherFuncs)
-
-		h.Map(http.MethodOptions, pathWithID, func(ctx context.Context) error {
-			ctx.HttpResponseWriter().Header().Set("Allow", strings.Join(singularMethods, ","))
-			ctx.HttpResponseWriter().WriteHeader(200)
-			return nil
-		}, matcherFuncs)
-
-	}
-
-	// everything ok
-	return nil
+	return mapping, nil
 
 }
 
@@ -350,30 +285,53 @@ func (h *HttpHandler) MapStaticFile(publicPath, staticFilePath string, matcherFu
 
 	// set the handler description
 	handler.(*PathMatchHandler).Description = fmt.Sprintf("Static file from: %s", staticFilePath)
+	handler.(*PathMatchHandler).servesFiles = true
 
 	return nil, nil
 
 }
 
+// staticPathParameterName is the name of the path parameter that MapStatic
+// captures the path of the file in, unless the public path names its own.
+const staticPathParameterName string = "filepath"
+
 // MapStatic maps static files from the specified systemPath to the
 // specified publicPath.
 //
 //     goweb.MapStatic("/static", "/location/on/system/to/files")
+//
+// The path of the file within the systemPath is available as the "filepath" path
+// parameter, or under the name of the catch-all the publicPath ends with (i.e.
+// "/static/{file...}").
 func (h *HttpHandler) MapStatic(publicPath, systemPath string, matcherFuncs ...MatcherFunc) (Handler, error) {
 
-	path := paths.NewPath(publicPath)
-	var dynamicPath string = path.RawPath
-
-	// ensure the path ends in "***"
-	segments := path.Segments()
-	if segments[len(segments)-1] != "***" {
-		dynamicPath = fmt.Sprintf("%s/***", path.RawPath)
+	segments := strings.Split(paths.NewPath(publicPath).RawPath, paths.PathSeperator)
+	lastSegment := segments[len(segments)-1]
+
+	// ensure the path ends in a named catch-all
+	var parameterName string
+	switch {
+	case lastSegment == paths.MatchAllPaths:
+		parameterName = staticPathParameterName
+		segments[len(segments)-1] = paths.MatchAllPaths + parameterName
+	case strings.HasPrefix(lastSegment, paths.MatchAllPaths):
+		parameterName = strings.TrimPrefix(lastSegment, paths.MatchAllPaths)
+	case strings.HasPrefix(lastSegment, "{") && strings.HasSuffix(lastSegment, "...}"):
+		parameterName = strings.TrimSuffix(strings.TrimPrefix(lastSegment, "{"), "...}")
+	default:
+		parameterName = staticPathParameterName
+		segments = append(segments, paths.MatchAllPaths+parameterName)
 	}
+	dynamicPath := strings.Join(segments, paths.PathSeperator)
 
 	handler, mapErr := h.Map(http.MethodGet, dynamicPath, func(ctx context.Context) error {
 
-		// get the non-system part of the path
-		thePath := path.RealFilePath(systemPath, ctx.Path().RawPath)
+		// get the non-system part of the path (segments can contain encoded
+		// slashes, so make sure it can't go above the systemPath)
+		thePath := systemPath
+		if filePath := ctx.PathValue(parameterName); len(filePath) > 0 {
+			thePath = fmt.Sprintf("%s%s", systemPath, path.Clean(paths.PathSeperator+filePath))
+		}
 
 		nethttp.ServeFile(ctx.HttpResponseWriter(), ctx.HttpRequest(), thePath)
 
@@ -387,6 +345,7 @@ func (h *HttpHandler) MapStatic(publicPath, systemPath string, matcherFuncs ...M
 
 	// set the handler description
 	handler.(*PathMatchHandler).Description = fmt.Sprintf("Static files from: %s", systemPath)
+	handler.(*PathMatchHandler).servesFiles = true
 
 	return handler, nil
 
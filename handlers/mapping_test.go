@@ -11,8 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	http_test "github.com/stretchr/testify/http"
 	"github.com/stretchr/testify/mock"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -605,6 +608,40 @@ func TestMapStatic(t *testing.T) {
 
 }
 
+func TestMapStatic_FilePath(t *testing.T) {
+
+	systemPath, _ := ioutil.TempDir("", "goweb-static")
+	defer os.RemoveAll(systemPath)
+	os.MkdirAll(filepath.Join(systemPath, "css", "themes"), 0755)
+	ioutil.WriteFile(filepath.Join(systemPath, "css", "themes", "Dark.min.css"), []byte("body{}"), 0644)
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapStatic("/static", systemPath)
+	h.MapStatic("/assets/{file...}", systemPath)
+
+	assert.Equal(t, "static/***filepath", h.HandlersPipe()[0].(*PathMatchHandler).PathPattern.RawPath)
+	assert.Equal(t, "assets/{file...}", h.HandlersPipe()[1].(*PathMatchHandler).PathPattern.RawPath)
+
+	response := serveTestRequest(h, "GET", "http://goweb.org/static/css/themes/Dark.min.css")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "body{}", response.Output)
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/assets/css/themes/Dark.min.css")
+	assert.Equal(t, "body{}", response.Output)
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/static/css/missing.css")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	// encoded slashes can't be used to escape the system path
+	response = serveTestRequest(h, "GET", "http://goweb.org/static/css%2F..%2F..%2F..%2Fetc%2Fpasswd")
+	assert.NotEqual(t, http.StatusOK, response.StatusCode)
+
+	h.EncodedSlashes = true
+	response = serveTestRequest(h, "GET", "http://goweb.org/static/css%2F..%2F..%2F..%2Fetc%2Fpasswd")
+	assert.NotEqual(t, http.StatusOK, response.StatusCode)
+
+}
+
 func TestMapStatic_WithMatcherFuncs(t *testing.T) {
 
 	codecService := codecsservices.NewWebCodecService()
@@ -674,3 +711,45 @@ func TestMapStaticFile_WithMatcherFuncs(t *testing.T) {
 	assert.Equal(t, 1, len(staticHandler.MatcherFuncs))
 	assert.Equal(t, matcherFunc, staticHandler.MatcherFuncs[0], "Matcher func (first)")
 }
+
+func TestMap_OrderBySpecificity(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.OrderBySpecificity = true
+
+	var called string
+	mapCalled := func(name, path string) {
+		h.Map("GET", path, func(c context.Context) error {
+			called = name
+			return nil
+		})
+	}
+
+	mapCalled("catch-all", "***")
+	mapCalled("people", "people/***")
+	mapCalled("person", "people/{id}")
+	mapCalled("me", "people/me")
+	mapCalled("optional", "people/[id]")
+	mapCalled("person again", "people/{name}")
+
+	var order []string
+	for _, handler := range h.HandlersPipe() {
+		order = append(order, handler.(*PathMatchHandler).PathPattern.RawPath)
+	}
+	assert.Equal(t, []string{"people/me", "people/{id}", "people/{name}", "people/[id]", "people/***", "***"}, order)
+
+	serveTestRequest(h, "GET", "http://goweb.org/people/me")
+	assert.Equal(t, "me", called)
+	serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.Equal(t, "person", called)
+	serveTestRequest(h, "GET", "http://goweb.org/people")
+	assert.Equal(t, "optional", called)
+	serveTestRequest(h, "GET", "http://goweb.org/books")
+	assert.Equal(t, "catch-all", called)
+
+	// only the equally specific patterns conflict
+	if assert.Equal(t, 1, len(h.Conflicts())) {
+		assert.Equal(t, "people/{name}", h.Conflicts()[0].Handler.PathPattern.RawPath)
+	}
+
+}
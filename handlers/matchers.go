@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"mime"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+	The MatcherFuncs in this file make no decision of their own when their check
+	passes (they return DontCare), so the path pattern is still checked, and they
+	can be combined with other MatcherFuncs.  When the check fails, they return
+	NoMatch.
+*/
+
+// decide gets the decision for a check that passed or failed.
+func decide(passed bool) MatcherFuncDecision {
+	if passed {
+		return DontCare
+	}
+	return NoMatch
+}
+
+// When gets a MatcherFunc that only lets the handler handle requests for which
+// the condition is true.
+func When(condition func(ctx context.Context) bool) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		return decide(condition(ctx)), nil
+	}
+}
+
+// HasHeader gets a MatcherFunc that only lets the handler handle requests that
+// have the specified header.
+func HasHeader(name string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		_, exists := ctx.HttpRequest().Header[http.CanonicalHeaderKey(name)]
+		return decide(exists), nil
+	}
+}
+
+// HeaderEquals gets a MatcherFunc that only lets the handler handle requests
+// with a header that has the specified value.
+func HeaderEquals(name, value string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		for _, headerValue := range ctx.HttpRequest().Header[http.CanonicalHeaderKey(name)] {
+			if headerValue == value {
+				return DontCare, nil
+			}
+		}
+		return NoMatch, nil
+	}
+}
+
+// HeaderMatches gets a MatcherFunc that only lets the handler handle requests
+// with a header whose value matches the specified regular expression.
+//
+// If the pattern isn't valid, HeaderMatches panics, so the mistake is found
+// when the handler is mapped rather than when requests are made.
+func HeaderMatches(name, pattern string) MatcherFunc {
+
+	regex, regexErr := regexp.Compile(pattern)
+	if regexErr != nil {
+		panic(fmt.Sprintf("goweb: Cannot call HeaderMatches with an invalid pattern: %s", regexErr))
+	}
+
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		for _, headerValue := range ctx.HttpRequest().Header[http.CanonicalHeaderKey(name)] {
+			if regex.MatchString(headerValue) {
+				return DontCare, nil
+			}
+		}
+
+		return NoMatch, nil
+
+	}
+
+}
+
+// HasQuery gets a MatcherFunc that only lets the handler handle requests that
+// have the specified query parameter.
+func HasQuery(name string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		_, exists := ctx.HttpRequest().URL.Query()[name]
+		return decide(exists), nil
+	}
+}
+
+// QueryEquals gets a MatcherFunc that only lets the handler handle requests
+// with a query parameter that has the specified value.
+func QueryEquals(name, value string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		for _, queryValue := range ctx.HttpRequest().URL.Query()[name] {
+			if queryValue == value {
+				return DontCare, nil
+			}
+		}
+		return NoMatch, nil
+	}
+}
+
+// ContentType gets a MatcherFunc that only lets the handler handle requests
+// whose body is one of the specified media types.  Media types can have
+// wildcard subtypes, i.e. "image/*".
+func ContentType(mediaTypes ...string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		contentType := ctx.HttpRequest().Header.Get("Content-Type")
+		if len(contentType) == 0 {
+			return NoMatch, nil
+		}
+
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return NoMatch, nil
+		}
+
+		for _, allowed := range mediaTypes {
+			if mediaTypeMatches(strings.ToLower(allowed), mediaType) {
+				return DontCare, nil
+			}
+		}
+
+		return NoMatch, nil
+
+	}
+}
+
+// Accepts gets a MatcherFunc that only lets the handler handle requests from
+// clients that accept at least one of the specified media types, according to
+// their Accept header.  Requests without an Accept header accept anything.
+//
+// Wildcards work both ways: Accepts("application/json") matches clients that
+// accept "application/*", and Accepts("application/*") matches clients that
+// accept "application/json".
+func Accepts(mediaTypes ...string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		accept := ctx.HttpRequest().Header.Get("Accept")
+		if len(strings.TrimSpace(accept)) == 0 {
+			return DontCare, nil
+		}
+
+		for _, accepted := range strings.Split(accept, ",") {
+
+			acceptedType, params, err := mime.ParseMediaType(accepted)
+			if err != nil {
+				continue
+			}
+
+			// q=0 means "not acceptable"
+			if q, exists := params["q"]; exists {
+				if quality, err := strconv.ParseFloat(q, 64); err == nil && quality <= 0 {
+					continue
+				}
+			}
+
+			for _, mediaType := range mediaTypes {
+				mediaType = strings.ToLower(mediaType)
+				if mediaTypeMatches(acceptedType, mediaType) || mediaTypeMatches(mediaType, acceptedType) {
+					return DontCare, nil
+				}
+			}
+
+		}
+
+		return NoMatch, nil
+
+	}
+}
+
+// mediaTypeMatches gets whether the media type matches the pattern, which may be
+// */* or have a wildcard subtype.
+func mediaTypeMatches(pattern, mediaType string) bool {
+
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*"))
+	}
+
+	return false
+
+}
+
+// Scheme gets a MatcherFunc that only lets the handler handle requests made
+// with one of the specified URL schemes ("http" or "https").
+//
+// Requests are "https" if they were made over TLS.  Headers set by proxies, like
+// X-Forwarded-Proto, are not trusted; combine HeaderEquals with Or to accept them.
+func Scheme(schemes ...string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		request := ctx.HttpRequest()
+		scheme := "http"
+		if request.TLS != nil {
+			scheme = "https"
+		} else if len(request.URL.Scheme) > 0 {
+			scheme = strings.ToLower(request.URL.Scheme)
+		}
+
+		for _, allowed := range schemes {
+			if strings.ToLower(allowed) == scheme {
+				return DontCare, nil
+			}
+		}
+
+		return NoMatch, nil
+
+	}
+}
+
+// HTTPS gets a MatcherFunc that only lets the handler handle requests made over
+// HTTPS.  See Scheme.
+func HTTPS() MatcherFunc {
+	return Scheme("https")
+}
+
+// RemoteAddrIn gets a MatcherFunc that only lets the handler handle requests
+// from clients whose IP address is in one of the specified CIDR ranges, i.e.
+// "10.0.0.0/8".
+//
+// The address the request came from is used; headers set by proxies, like
+// X-Forwarded-For, are not trusted.  If one of the ranges isn't valid,
+// RemoteAddrIn panics, so the mistake is found when the handler is mapped.
+func RemoteAddrIn(cidrs ...string) MatcherFunc {
+
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("goweb: Cannot call RemoteAddrIn with an invalid CIDR range: %s", err))
+		}
+		networks = append(networks, network)
+	}
+
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		host := ctx.HttpRequest().RemoteAddr
+		if withoutPort, _, err := net.SplitHostPort(host); err == nil {
+			host = withoutPort
+		}
+
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return NoMatch, nil
+		}
+
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return DontCare, nil
+			}
+		}
+
+		return NoMatch, nil
+
+	}
+
+}
+
+// FeatureFlags tells whether features are enabled, for the FeatureEnabled
+// MatcherFunc.
+type FeatureFlags interface {
+
+	// Enabled gets whether the named feature is enabled for the request in the
+	// specified context.
+	Enabled(feature string, ctx context.Context) bool
+}
+
+// FeatureEnabled gets a MatcherFunc that only lets the handler handle requests
+// for which the named feature is enabled.
+func FeatureEnabled(flags FeatureFlags, feature string) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		return decide(flags.Enabled(feature, ctx)), nil
+	}
+}
+
+// And gets a MatcherFunc that combines the specified MatcherFuncs: it returns
+// NoMatch if any of them does, otherwise Match if any of them does, otherwise
+// DontCare.
+func And(matcherFuncs ...MatcherFunc) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		decision := DontCare
+
+		for _, matcherFunc := range matcherFuncs {
+			matcherDecision, err := matcherFunc(ctx)
+			if err != nil {
+				return DontCare, err
+			}
+			switch matcherDecision {
+			case NoMatch:
+				return NoMatch, nil
+			case Match:
+				decision = Match
+			}
+		}
+
+		return decision, nil
+
+	}
+}
+
+// Or gets a MatcherFunc that combines the specified MatcherFuncs: it returns
+// Match if any of them does, otherwise DontCare if any of them does, otherwise
+// NoMatch.
+func Or(matcherFuncs ...MatcherFunc) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		decision := NoMatch
+
+		for _, matcherFunc := range matcherFuncs {
+			matcherDecision, err := matcherFunc(ctx)
+			if err != nil {
+				return DontCare, err
+			}
+			switch matcherDecision {
+			case Match:
+				return Match, nil
+			case DontCare:
+				decision = DontCare
+			}
+		}
+
+		return decision, nil
+
+	}
+}
+
+// Not gets a MatcherFunc that inverts the decision of the specified MatcherFunc:
+// NoMatch becomes DontCare, and Match and DontCare (which both let the handler
+// handle the request) become NoMatch.
+func Not(matcherFunc MatcherFunc) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+
+		decision, err := matcherFunc(ctx)
+		if err != nil {
+			return DontCare, err
+		}
+
+		return decide(decision == NoMatch), nil
+
+	}
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+)
+
+func fixedMatcherFunc(decision MatcherFuncDecision) MatcherFunc {
+	return func(ctx context.Context) (MatcherFuncDecision, error) {
+		return decision, nil
+	}
+}
+
+func errorMatcherFunc(ctx context.Context) (MatcherFuncDecision, error) {
+	return DontCare, errors.New("matcher failed")
+}
+
+func TestHeaderMatchers(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+	ctx.HttpRequest().Header.Set("X-Api-Client", "mobile-ios")
+
+	decision, _ := HasHeader("x-api-client")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = HasHeader("X-Other")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	decision, _ = HeaderEquals("X-Api-Client", "mobile-ios")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = HeaderEquals("X-Api-Client", "web")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	decision, _ = HeaderMatches("X-Api-Client", `^mobile-`)(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = HeaderMatches("X-Api-Client", `^web`)(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	// invalid patterns are found when mapping, not when handling requests
+	assert.Panics(t, func() {
+		HeaderMatches("X-Api-Client", `[0-9]++`)
+	})
+
+}
+
+func TestQueryMatchers(t *testing.T) {
+
+	ctx := context_test.MakeTestContextWithPath("people?debug&format=csv")
+
+	decision, _ := HasQuery("debug")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = HasQuery("verbose")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	decision, _ = QueryEquals("format", "csv")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = QueryEquals("format", "xml")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+}
+
+func TestContentType(t *testing.T) {
+
+	ctx := context_test.MakeTestContextWithDetails("people", "POST")
+
+	decision, _ := ContentType("application/json")(ctx)
+	assert.Equal(t, NoMatch, decision, "requests without a body type never match")
+
+	ctx.HttpRequest().Header.Set("Content-Type", "application/JSON; charset=utf-8")
+	decision, _ = ContentType("text/xml", "application/json")(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	ctx.HttpRequest().Header.Set("Content-Type", "image/png")
+	decision, _ = ContentType("application/json")(ctx)
+	assert.Equal(t, NoMatch, decision)
+	decision, _ = ContentType("image/*")(ctx)
+	assert.Equal(t, DontCare, decision)
+
+}
+
+func TestAccepts(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+
+	decision, _ := Accepts("application/json")(ctx)
+	assert.Equal(t, DontCare, decision, "no Accept header accepts anything")
+
+	ctx.HttpRequest().Header.Set("Accept", "text/html, application/json;q=0.9")
+	decision, _ = Accepts("application/json")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = Accepts("text/csv")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	ctx.HttpRequest().Header.Set("Accept", "text/*")
+	decision, _ = Accepts("text/csv")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = Accepts("application/json")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	// wildcards in the arguments match concrete Accept values too
+	ctx.HttpRequest().Header.Set("Accept", "application/json")
+	decision, _ = Accepts("application/*")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = Accepts("*/*")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = Accepts("text/*")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	ctx.HttpRequest().Header.Set("Accept", "application/json;q=0, */*")
+	decision, _ = Accepts("text/csv")(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	ctx.HttpRequest().Header.Set("Accept", "application/json;q=0")
+	decision, _ = Accepts("application/json")(ctx)
+	assert.Equal(t, NoMatch, decision, "q=0 means not acceptable")
+
+}
+
+func TestScheme(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+
+	decision, _ := HTTPS()(ctx)
+	assert.Equal(t, NoMatch, decision)
+	decision, _ = Scheme("http")(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	ctx.HttpRequest().TLS = new(tls.ConnectionState)
+	decision, _ = HTTPS()(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	// forwarded headers are only trusted when asked
+	ctx = context_test.MakeTestContext()
+	ctx.HttpRequest().Header.Set("X-Forwarded-Proto", "https")
+	decision, _ = HTTPS()(ctx)
+	assert.Equal(t, NoMatch, decision)
+	decision, _ = Or(HTTPS(), HeaderEquals("X-Forwarded-Proto", "https"))(ctx)
+	assert.Equal(t, DontCare, decision)
+
+}
+
+func TestRemoteAddrIn(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+	matcherFunc := RemoteAddrIn("10.0.0.0/8", "2001:db8::/32")
+
+	ctx.HttpRequest().RemoteAddr = "10.1.2.3:5123"
+	decision, _ := matcherFunc(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	ctx.HttpRequest().RemoteAddr = "[2001:db8::1]:443"
+	decision, _ = matcherFunc(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	ctx.HttpRequest().RemoteAddr = "192.168.0.1:5123"
+	decision, _ = matcherFunc(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	ctx.HttpRequest().RemoteAddr = "not an address"
+	decision, _ = matcherFunc(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	assert.Panics(t, func() {
+		RemoteAddrIn("10.0.0.0/8", "10.0.0.0/99")
+	})
+
+}
+
+type testFeatureFlags map[string]bool
+
+func (f testFeatureFlags) Enabled(feature string, ctx context.Context) bool {
+	return f[feature]
+}
+
+func TestFeatureEnabledAndWhen(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+	flags := testFeatureFlags{"new-search": true}
+
+	decision, _ := FeatureEnabled(flags, "new-search")(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = FeatureEnabled(flags, "new-checkout")(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	decision, _ = When(func(ctx context.Context) bool { return ctx.MethodString() == "GET" })(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = When(func(ctx context.Context) bool { return false })(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+}
+
+func TestAnd(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+
+	decision, _ := And(fixedMatcherFunc(DontCare), fixedMatcherFunc(DontCare))(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = And(fixedMatcherFunc(DontCare), fixedMatcherFunc(Match))(ctx)
+	assert.Equal(t, Match, decision)
+	decision, _ = And(fixedMatcherFunc(Match), fixedMatcherFunc(NoMatch))(ctx)
+	assert.Equal(t, NoMatch, decision)
+	decision, _ = And()(ctx)
+	assert.Equal(t, DontCare, decision)
+
+	_, err := And(fixedMatcherFunc(DontCare), errorMatcherFunc)(ctx)
+	assert.Error(t, err)
+
+}
+
+func TestOr(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+
+	decision, _ := Or(fixedMatcherFunc(NoMatch), fixedMatcherFunc(NoMatch))(ctx)
+	assert.Equal(t, NoMatch, decision)
+	decision, _ = Or(fixedMatcherFunc(NoMatch), fixedMatcherFunc(DontCare))(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = Or(fixedMatcherFunc(DontCare), fixedMatcherFunc(Match))(ctx)
+	assert.Equal(t, Match, decision)
+
+	_, err := Or(fixedMatcherFunc(NoMatch), errorMatcherFunc)(ctx)
+	assert.Error(t, err)
+
+}
+
+func TestNot(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+
+	decision, _ := Not(fixedMatcherFunc(NoMatch))(ctx)
+	assert.Equal(t, DontCare, decision)
+	decision, _ = Not(fixedMatcherFunc(DontCare))(ctx)
+	assert.Equal(t, NoMatch, decision)
+	decision, _ = Not(fixedMatcherFunc(Match))(ctx)
+	assert.Equal(t, NoMatch, decision)
+
+	_, err := Not(errorMatcherFunc)(ctx)
+	assert.Error(t, err)
+
+}
+
+func TestMatchers_WithMap(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var called string
+	h.Map("GET", "people", func(c context.Context) error {
+		called = "beta"
+		return nil
+	}, And(HasHeader("X-Beta"), Not(QueryEquals("legacy", "true"))))
+	h.Map("GET", "people", func(c context.Context) error {
+		called = "people"
+		return nil
+	})
+
+	serve := func(url string) {
+		called = ""
+		request, _ := http.NewRequest("GET", url, nil)
+		request.Header.Set("X-Beta", "1")
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+	}
+
+	serveTestRequest(h, "GET", "http://goweb.org/people")
+	assert.Equal(t, "people", called)
+
+	serve("http://goweb.org/people")
+	assert.Equal(t, "beta", called)
+
+	serve("http://goweb.org/people?legacy=true")
+	assert.Equal(t, "people", called)
+
+	// the path is still checked
+	serve("http://goweb.org/people/123")
+	assert.Equal(t, "", called)
+
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MethodOverrideSource represents a place in the request that a
+// MethodOverridePolicy may look for the method to use instead of the real one.
+//
+// Sources can be combined with the | operator.
+type MethodOverrideSource int
+
+const (
+	// MethodOverrideHeader allows the method to be overridden with the
+	// X-HTTP-Method-Override header.
+	MethodOverrideHeader MethodOverrideSource = 1 << iota
+
+	// MethodOverrideFormField allows the method to be overridden with the
+	// _method field of URL encoded or multipart form bodies.
+	MethodOverrideFormField
+
+	// MethodOverrideQuery allows the method to be overridden with the method
+	// URL parameter.
+	//
+	// Be careful; combined with allowing GET to be overridden, a simple link
+	// can trigger unsafe actions.
+	MethodOverrideQuery
+)
+
+const (
+	// DefaultMethodOverrideHeader is the default header that MethodOverrideHeader
+	// reads.
+	DefaultMethodOverrideHeader string = "X-HTTP-Method-Override"
+
+	// DefaultMethodOverrideFormField is the default form field that
+	// MethodOverrideFormField reads.
+	DefaultMethodOverrideFormField string = "_method"
+
+	// DefaultMethodOverrideQueryParameter is the default URL parameter that
+	// MethodOverrideQuery reads.
+	DefaultMethodOverrideQueryParameter string = "method"
+)
+
+// MethodOverridePolicy describes when the HTTP method of a request may be
+// overridden, for clients (such as HTML forms) that are unable to send the
+// method they really mean.
+//
+// Method overriding is disabled unless a policy is set on the HttpHandler:
+//
+//     handler.MethodOverridePolicy = handlers.NewMethodOverridePolicy(handlers.MethodOverrideHeader | handlers.MethodOverrideFormField)
+//
+// When the method is overridden, the method the request was sent with is kept
+// in the context.Data with the context.DataKeyOriginalMethod key.  Use the
+// OriginalMethod func to get it.
+type MethodOverridePolicy struct {
+
+	// Sources are the places in the request to look for the new method.  If more
+	// than one source is allowed, the header is checked first, then the form field,
+	// then the URL parameter.
+	Sources MethodOverrideSource
+
+	// HeaderName is the name of the header read by MethodOverrideHeader.
+	HeaderName string
+
+	// FormField is the name of the form field read by MethodOverrideFormField.
+	FormField string
+
+	// QueryParameter is the name of the URL parameter read by MethodOverrideQuery.
+	QueryParameter string
+
+	// OverridableMethods are the methods that may be overridden.  By default,
+	// only POST requests may be overridden.
+	OverridableMethods []string
+
+	// AllowedMethods are the methods that requests may be overridden to.
+	AllowedMethods []string
+}
+
+// NewMethodOverridePolicy makes a new MethodOverridePolicy that allows POST
+// requests to be turned into PUT, PATCH or DELETE requests using the specified
+// sources.
+func NewMethodOverridePolicy(sources MethodOverrideSource) *MethodOverridePolicy {
+	return &MethodOverridePolicy{
+		Sources:            sources,
+		HeaderName:         DefaultMethodOverrideHeader,
+		FormField:          DefaultMethodOverrideFormField,
+		QueryParameter:     DefaultMethodOverrideQueryParameter,
+		OverridableMethods: []string{"POST"},
+		AllowedMethods:     []string{"PUT", "PATCH", "DELETE"}}
+}
+
+// Override changes the method of the request if the policy allows it.
+//
+// Returns the original method, and whether the method was changed or not.
+func (p *MethodOverridePolicy) Override(request *http.Request) (string, bool) {
+
+	original := strings.ToUpper(request.Method)
+
+	if !containsMethod(p.OverridableMethods, original) {
+		return original, false
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(p.requestedMethod(request)))
+
+	if method == "" || method == original || !containsMethod(p.AllowedMethods, method) {
+		return original, false
+	}
+
+	request.Method = method
+	return original, true
+}
+
+// requestedMethod gets the method the client has asked for from the first
+// allowed source that has one.
+func (p *MethodOverridePolicy) requestedMethod(request *http.Request) string {
+
+	if p.Sources&MethodOverrideHeader != 0 {
+		if method := request.Header.Get(p.HeaderName); method != "" {
+			return method
+		}
+	}
+
+	if p.Sources&MethodOverrideFormField != 0 && isFormRequest(request) {
+		if method := request.PostFormValue(p.FormField); method != "" {
+			return method
+		}
+	}
+
+	if p.Sources&MethodOverrideQuery != 0 {
+		if method := request.URL.Query().Get(p.QueryParameter); method != "" {
+			return method
+		}
+	}
+
+	return ""
+}
+
+// OriginalMethod gets the HTTP method that the request in the specified context
+// was actually sent with, which will be different from ctx.MethodString() if
+// it has been overridden.
+func OriginalMethod(ctx context.Context) string {
+	if method := ctx.Data().Get(context.DataKeyOriginalMethod).Str(); method != "" {
+		return method
+	}
+	return ctx.MethodString()
+}
+
+// containsMethod gets whether the list of methods contains the method or not.
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.ToUpper(m) == method {
+			return true
+		}
+	}
+	return false
+}
+
+// isFormRequest gets whether the request has a URL encoded or multipart form
+// body or not.
+func isFormRequest(request *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data"
+}
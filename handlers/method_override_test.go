@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewMethodOverridePolicy(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideHeader | MethodOverrideQuery)
+
+	assert.Equal(t, MethodOverrideHeader|MethodOverrideQuery, p.Sources)
+	assert.Equal(t, "X-HTTP-Method-Override", p.HeaderName)
+	assert.Equal(t, "_method", p.FormField)
+	assert.Equal(t, "method", p.QueryParameter)
+	assert.Equal(t, []string{"POST"}, p.OverridableMethods)
+	assert.Equal(t, []string{"PUT", "PATCH", "DELETE"}, p.AllowedMethods)
+
+}
+
+func TestMethodOverridePolicy_Header(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideHeader)
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people/123", nil)
+	request.Header.Set("X-HTTP-Method-Override", "delete")
+
+	original, overridden := p.Override(request)
+
+	assert.True(t, overridden)
+	assert.Equal(t, "POST", original)
+	assert.Equal(t, "DELETE", request.Method)
+
+}
+
+func TestMethodOverridePolicy_FormField(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideFormField)
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people/123", strings.NewReader("_method=PATCH&name=Mat"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, overridden := p.Override(request)
+
+	assert.True(t, overridden)
+	assert.Equal(t, "PATCH", request.Method)
+	assert.Equal(t, "Mat", request.PostFormValue("name"))
+
+	// only form bodies are read
+	request, _ = http.NewRequest("POST", "http://goweb.org/people/123", strings.NewReader(`{"_method":"PATCH"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	_, overridden = p.Override(request)
+
+	assert.False(t, overridden)
+	assert.Equal(t, "POST", request.Method)
+
+}
+
+func TestMethodOverridePolicy_Query(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideQuery)
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people/123?method=PUT", nil)
+
+	_, overridden := p.Override(request)
+
+	assert.True(t, overridden)
+	assert.Equal(t, "PUT", request.Method)
+
+}
+
+func TestMethodOverridePolicy_DisallowedSource(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideHeader)
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people/123?method=PUT", nil)
+
+	_, overridden := p.Override(request)
+
+	assert.False(t, overridden)
+	assert.Equal(t, "POST", request.Method)
+
+}
+
+func TestMethodOverridePolicy_OnlyPOSTByDefault(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideHeader | MethodOverrideQuery)
+
+	request, _ := http.NewRequest("GET", "http://goweb.org/people/123?method=DELETE", nil)
+
+	_, overridden := p.Override(request)
+
+	assert.False(t, overridden)
+	assert.Equal(t, "GET", request.Method)
+
+	// unless we say so
+	p.OverridableMethods = []string{"GET", "POST"}
+
+	_, overridden = p.Override(request)
+
+	assert.True(t, overridden)
+	assert.Equal(t, "DELETE", request.Method)
+
+}
+
+func TestMethodOverridePolicy_DisallowedTarget(t *testing.T) {
+
+	p := NewMethodOverridePolicy(MethodOverrideHeader)
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people/123", nil)
+	request.Header.Set("X-HTTP-Method-Override", "CONNECT")
+
+	_, overridden := p.Override(request)
+
+	assert.False(t, overridden)
+	assert.Equal(t, "POST", request.Method)
+
+}
+
+func TestOriginalMethod(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+	assert.Equal(t, ctx.MethodString(), OriginalMethod(ctx))
+
+	ctx.Data().Set(context.DataKeyOriginalMethod, "POST")
+	assert.Equal(t, "POST", OriginalMethod(ctx))
+
+}
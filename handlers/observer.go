@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+)
+
+// Observer represents an object that is notified when an HttpHandler starts
+// and finishes serving each request.
+//
+// Unlike before and after handlers, observers are always notified, even if
+// no handlers match the request or one of them returns an error.  This makes
+// them well suited to instrumentation, such as collecting metrics.
+//
+// To add an observer, use the HttpHandler.AddObserver method.
+type Observer interface {
+
+	// RequestStarted is called once the context has been created, before
+	// any handlers are run.
+	RequestStarted(ctx context.Context)
+
+	// RequestFinished is called after all handlers (and the ErrorHandler if
+	// there was an error) have finished.  err is the error returned by the
+	// handlers, or nil.
+	//
+	// If a handler panics, RequestFinished is called with an error describing
+	// the panic before the panic carries on.
+	RequestFinished(ctx context.Context, err error)
+}
+
+// HandlerObserver represents an Observer that also wants to be notified
+// before and after each Handler handles a request.
+//
+// Handlers are nested, so the HttpHandler's pre, process and post Pipes will
+// each be observed as a Handler, followed by the Handlers inside them.
+type HandlerObserver interface {
+	Observer
+
+	// HandlerStarted is called before the handler's Handle method is called.
+	HandlerStarted(ctx context.Context, handler Handler)
+
+	// HandlerFinished is called after the handler's Handle method has returned.
+	// err is the error returned by the handler, or nil.
+	HandlerFinished(ctx context.Context, handler Handler, err error)
+}
+
+// handlerObserversFor gets the HandlerObservers that should be notified
+// about handlers handling the specified context.
+func handlerObserversFor(ctx context.Context) []HandlerObserver {
+	if ctx == nil {
+		return nil
+	}
+	observers, _ := ctx.Data().Get(dataKeyHandlerObservers).Data().([]HandlerObserver)
+	return observers
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"fmt"
 	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/http"
 	"github.com/stretchr/goweb/paths"
+	"github.com/stretchr/objx"
 	"strings"
 )
 
@@ -30,6 +32,36 @@ type PathMatchHandler struct {
 	// be returned instead of the default when String() is called.
 	Description string
 
+	// Name is an optional name for the mapping, i.e. "people.read", which identifies
+	// it in the route table (see HttpHandler.Routes).
+	Name string
+
+	// Controller is the controller this handler was mapped for by MapController, or nil.
+	Controller interface{}
+
+	// ActionName is the name of the controller action (i.e. "Create", "ReadMany" or the
+	// name of a custom action) this handler was mapped for by MapController.  It is empty
+	// for other handlers, including controller Before and After hooks.
+	ActionName string
+
+	// Metadata holds extra information about the mapping for other tools, such as
+	// documentation generators, keyed by the name of the tool.  It is nil until a
+	// HandlerOption adds something to it.
+	Metadata map[string]interface{}
+
+	// Version is the version of the API this handler is for, or an empty string
+	// if it handles requests for every version.  See VersioningPolicy.
+	Version string
+
+	// HostPattern is the pattern the host of requests must match for this handler
+	// to handle them, or nil if it handles requests for any host.  See Host.
+	HostPattern *paths.HostPattern
+
+	// servesFiles indicates whether the handler was mapped by MapStatic or
+	// MapStaticFile, so http.ServeFile may redirect its requests to add or
+	// remove a trailing slash.
+	servesFiles bool
+
 	// BreakCurrentPipeline indicates whether the rest of the handlers in the Pipe
 	// should be skipped once this handler has done its work.
 	//
@@ -56,15 +88,26 @@ func NewPathMatchHandler(pathPattern *paths.PathPattern, executionFunc HandlerEx
 // WillHandle checks whether this handler will be used to handle the specified
 // request or not.
 func (p *PathMatchHandler) WillHandle(c context.Context) (bool, error) {
+	willHandle, _, err := p.match(c)
+	return willHandle, err
+}
+
+// match checks whether this handler will be used to handle the specified request
+// or not, and gives the reason why.
+func (p *PathMatchHandler) match(c context.Context) (bool, string, error) {
+
+	// forget parameters found by the matcher funcs of other handlers
+	delete(c.Data(), dataKeyMatcherParameters)
 
 	// check each matcher func
 	matcherFuncMatches := true
 	matcherFuncDecisionMade := false
-	for _, matcherFunc := range p.MatcherFuncs {
+	var matcherFuncIndex int
+	for index, matcherFunc := range p.MatcherFuncs {
 		decision, matcherFuncErr := matcherFunc(c)
 
 		if matcherFuncErr != nil {
-			return false, matcherFuncErr
+			return false, "", matcherFuncErr
 		}
 
 		switch decision {
@@ -79,6 +122,7 @@ func (p *PathMatchHandler) WillHandle(c context.Context) (bool, error) {
 		}
 
 		if matcherFuncDecisionMade {
+			matcherFuncIndex = index
 			break
 		}
 
@@ -94,8 +138,11 @@ func (p *PathMatchHandler) WillHandle(c context.Context) (bool, error) {
 
 	} else {
 
+		// HEAD requests may be answered by GET handlers
+		automaticHead := c.MethodString() == http.MethodHead && c.Data().Get(dataKeyAutomaticHead).Bool()
+
 		for _, httpMethod := range p.HttpMethods {
-			if httpMethod == c.MethodString() {
+			if httpMethod == c.MethodString() || (automaticHead && httpMethod == http.MethodGet) {
 				httpMethodMatch = true
 				break
 			}
@@ -105,7 +152,21 @@ func (p *PathMatchHandler) WillHandle(c context.Context) (bool, error) {
 
 	// cancel early if we didn't get an HTTP Method match
 	if !httpMethodMatch {
-		return false, nil
+		return false, fmt.Sprintf("method %s is not %s", c.MethodString(), strings.Join(p.HttpMethods, " or ")), nil
+	}
+
+	// check the version
+	if len(p.Version) > 0 && p.Version != c.Data().Get(context.DataKeyVersion).Str() {
+		return false, fmt.Sprintf("mapped for version %s", p.Version), nil
+	}
+
+	// check the host
+	var hostParameters objx.Map
+	if p.HostPattern != nil {
+		var hostMatch bool
+		if hostParameters, hostMatch = p.HostPattern.Match(c.HttpRequest().Host); !hostMatch {
+			return false, fmt.Sprintf("host %s does not match %s", c.HttpRequest().Host, p.HostPattern.RawHost), nil
+		}
 	}
 
 	// check path match
@@ -113,27 +174,51 @@ func (p *PathMatchHandler) WillHandle(c context.Context) (bool, error) {
 	pathMatch := p.PathPattern.GetPathMatch(c.Path())
 
 	var allMatch bool
+	var reason string
 
 	if matcherFuncDecisionMade {
 		allMatch = matcherFuncMatches
+		if allMatch {
+			reason = fmt.Sprintf("matcher func %d matched", matcherFuncIndex+1)
+		} else {
+			reason = fmt.Sprintf("matcher func %d did not match", matcherFuncIndex+1)
+		}
 	} else {
 		allMatch = pathMatch.Matches
+		if allMatch {
+			reason = fmt.Sprintf("path matches %s", p.PathPattern.RawPath)
+		} else {
+			reason = fmt.Sprintf("path does not match %s", p.PathPattern.RawPath)
+		}
 	}
 
 	if allMatch {
 
-		// save the match parameters for later
-		c.Data().Set(context.DataKeyPathParameters, pathMatch.Parameters)
+		// save the match parameters (and any from the host or matcher funcs)
+		// for later
+		parameters := pathMatch.Parameters
+		matcherParameters, _ := c.Data().Get(dataKeyMatcherParameters).Data().(objx.Map)
+		if len(hostParameters) > 0 || len(matcherParameters) > 0 {
+			parameters = hostParameters.Merge(matcherParameters).Merge(pathMatch.Parameters)
+		}
+		c.Data().Set(context.DataKeyPathParameters, parameters)
 
 	}
 
-	return allMatch, nil
+	return allMatch, reason, nil
 }
 
 /*
   Handle gives each sub handle the opportinuty to handle the context.
 */
 func (p *PathMatchHandler) Handle(c context.Context) (bool, error) {
+
+	// handlers that break the pipeline are responsible for the response,
+	// so remember which route was used
+	if p.BreakCurrentPipeline {
+		c.Data().Set(context.DataKeyRoute, p.PathPattern.RawPath)
+	}
+
 	err := p.ExecutionFunc(c)
 	return p.BreakCurrentPipeline, err
 }
@@ -160,5 +245,15 @@ func (p *PathMatchHandler) String() string {
 		matcherFuncsDesc = fmt.Sprintf(" - %d matcher func(s)", len(p.MatcherFuncs))
 	}
 
-	return fmt.Sprintf("%s%v - %v %s\n", methods, p.PathPattern.RawPath, desc, matcherFuncsDesc)
+	var version string
+	if len(p.Version) > 0 {
+		version = fmt.Sprintf(" (version %s)", p.Version)
+	}
+
+	var host string
+	if p.HostPattern != nil {
+		host = fmt.Sprintf(" (host %s)", p.HostPattern.RawHost)
+	}
+
+	return fmt.Sprintf("%s%v%s%s - %v %s\n", methods, p.PathPattern.RawPath, version, host, desc, matcherFuncsDesc)
 }
@@ -47,8 +47,15 @@ func (p Pipe) Handle(c context.Context) (bool, error) {
 	var handleErr error
 	var stop bool
 
+	observers := handlerObserversFor(c)
+
 	for _, handler := range p {
 
+		// has a handler already dealt with the request?
+		if IsAborted(c) {
+			break
+		}
+
 		willHandle, willHandleErr = handler.WillHandle(c)
 
 		if willHandleErr != nil {
@@ -57,9 +64,17 @@ func (p Pipe) Handle(c context.Context) (bool, error) {
 
 		if willHandle {
 
+			for _, observer := range observers {
+				observer.HandlerStarted(c, handler)
+			}
+
 			// call the handler
 			stop, handleErr = handler.Handle(c)
 
+			for i := len(observers) - 1; i >= 0; i-- {
+				observers[i].HandlerFinished(c, handler, handleErr)
+			}
+
 			if handleErr != nil {
 
 				// already a HandlerError?
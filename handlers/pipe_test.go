@@ -105,3 +105,23 @@ func TestPipe_Handle_Stopping(t *testing.T) {
 	mock.AssertExpectationsForObjects(t, handler1.Mock, handler2.Mock, handler3.Mock)
 
 }
+
+func TestPipe_Handle_Aborted(t *testing.T) {
+
+	ctx := context_test.MakeTestContext()
+
+	handler1 := new(handlers_test.TestHandler)
+	handler2 := new(handlers_test.TestHandler)
+
+	handler1.On("WillHandle", ctx).Return(true, nil)
+	handler1.On("Handle", ctx).Return(false, nil).Run(func(args mock.Arguments) {
+		Abort(ctx)
+	})
+
+	p := Pipe{Pipe{handler1}, Pipe{handler2}}
+	p.Handle(ctx)
+
+	assert.True(t, IsAborted(ctx))
+	mock.AssertExpectationsForObjects(t, handler1.Mock, handler2.Mock)
+
+}
@@ -2,17 +2,38 @@ package handlers
 
 import (
 	"github.com/stretchr/goweb/context"
-	"regexp"
+	"github.com/stretchr/goweb/paths"
 )
 
+// dataKeyMatcherParameters is the data key for the path parameters found by
+// MatcherFuncs, such as the named groups of RegexPath.
+const dataKeyMatcherParameters string = "matcherparameters"
+
+// Regexp is a regular expression that can be used instead of a path pattern
+// when mapping handlers.  The values of named capture groups become path
+// parameters:
+//
+//     goweb.Map("GET", handlers.Regexp(`^people/(?P<id>[0-9]+)$`), func(c context.Context) error {
+//       id := c.PathValue("id")
+//       ...
+//     })
+//
+// The expression is compiled when the mapping is made, so Map returns an error
+// if it isn't valid.  For details of how paths are matched, see
+// paths.NewRegexpPathPattern.
+type Regexp string
+
 // RegexPath returns a MatcherFunc that mathces the path based on the specified
-// Regex pattern.
+// Regex pattern.  The values of named capture groups become path parameters.
+//
+// Unlike Regexp, an invalid pattern is only reported when the MatcherFunc is
+// called.
 //
 // For more information, see the goweb.RegexPath shortcut function.
 func RegexPath(regexpPattern string) MatcherFunc {
 
 	// compile the regex
-	regex, regexErr := regexp.Compile(regexpPattern)
+	pathPattern, regexErr := paths.NewRegexpPathPattern(regexpPattern)
 
 	// return a MatcherFunc that will check the regex against the path
 	// and return the decisive MatcherFuncDecision.
@@ -25,8 +46,9 @@ func RegexPath(regexpPattern string) MatcherFunc {
 
 		var decision MatcherFuncDecision
 
-		if regex.MatchString(ctx.Path().RawPath) {
+		if pathMatch := pathPattern.GetPathMatch(ctx.Path()); pathMatch.Matches {
 			decision = Match
+			ctx.Data().Set(dataKeyMatcherParameters, pathMatch.Parameters)
 		} else {
 			decision = NoMatch
 		}
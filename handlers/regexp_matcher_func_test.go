@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	codecsservices "github.com/stretchr/codecs/services"
 	"github.com/stretchr/goweb/context"
 	context_test "github.com/stretchr/goweb/webcontext/test"
 	"github.com/stretchr/testify/assert"
@@ -45,3 +46,68 @@ func TestRegexPath_Error(t *testing.T) {
 	assert.Error(t, err)
 
 }
+
+func TestRegexPath_Parameters(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var name, index string
+	h.Map(func(c context.Context) error {
+		name, index = c.PathValue("name"), c.PathValue("index")
+		return nil
+	}, RegexPath(`^(?P<name>[a-z]+)\[(?P<index>[0-9]+)\]$`))
+
+	serveTestRequest(h, "GET", "http://goweb.org/adam[23]")
+	assert.Equal(t, "adam", name)
+	assert.Equal(t, "23", index)
+
+}
+
+func TestMap_Regexp(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	var called, id, format string
+	h.Map("GET", Regexp(`^people/(?P<id>[0-9]+)(\.(?P<format>json|xml))?$`), func(c context.Context) error {
+		called, id, format = "numeric", c.PathValue("id"), c.PathValue("format")
+		return nil
+	})
+	h.Map(Regexp(`^people/(?P<name>[a-z]+)$`), func(c context.Context) error {
+		called, id, format = "named", c.PathValue("name"), ""
+		return nil
+	})
+	h.Map("GET", "people/{id}", func(c context.Context) error {
+		called, id, format = "pattern", c.PathValue("id"), ""
+		return nil
+	})
+
+	serveTestRequest(h, "GET", "http://goweb.org/people/123.xml")
+	assert.Equal(t, "numeric", called)
+	assert.Equal(t, "123", id)
+	assert.Equal(t, "xml", format)
+
+	serveTestRequest(h, "DELETE", "http://goweb.org/people/mat")
+	assert.Equal(t, "named", called)
+	assert.Equal(t, "mat", id)
+
+	serveTestRequest(h, "GET", "http://goweb.org/people/MAT")
+	assert.Equal(t, "pattern", called, "Regular expressions are case sensitive")
+	assert.Equal(t, "MAT", id)
+
+	assert.Empty(t, h.Conflicts(), "Regular expressions aren't checked for conflicts")
+	assert.Contains(t, h.String(), `GET ^people/(?P<id>[0-9]+)`)
+
+}
+
+func TestMap_Regexp_Invalid(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	_, err := h.Map("GET", Regexp(`[0-9]++`), func(c context.Context) error {
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(h.HandlersPipe()))
+
+}
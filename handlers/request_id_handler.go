@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/stretchr/goweb/context"
+)
+
+const (
+	// RequestIDHeader is the default HTTP header used to send and receive
+	// request IDs.
+	RequestIDHeader string = "X-Request-ID"
+
+	// MaxRequestIDLength is the longest incoming request ID that the
+	// DefaultRequestIDValidator will accept.
+	MaxRequestIDLength int = 200
+)
+
+// RequestIDHandler is a Handler that makes sure every request has an ID that
+// can be used to correlate the client's view of a request with error pages and
+// logs on the server.
+//
+// If the request has a valid X-Request-ID header (for example, set by a load
+// balancer or the client) it is used, otherwise a new ID is generated.  The ID
+// is stored in the context.Data with the context.DataKeyRequestID key, and
+// echoed back to the client in the X-Request-ID response header.
+//
+// RequestIDHandlers should be added to the start of the pre handlers pipe, so
+// that the ID is available to everything that follows:
+//
+//     handler.PrependPreHandler(handlers.NewRequestIDHandler())
+type RequestIDHandler struct {
+
+	// HeaderName is the name of the request and response header that carries
+	// the ID.  If empty, RequestIDHeader is used.
+	HeaderName string
+
+	// Generator is the func used to make new IDs.  If nil,
+	// DefaultRequestIDGenerator is used.
+	Generator func() string
+
+	// Validator is the func used to decide whether an incoming ID is acceptable
+	// or not.  Unacceptable IDs are replaced with new ones.  If nil,
+	// DefaultRequestIDValidator is used.
+	Validator func(id string) bool
+}
+
+// NewRequestIDHandler makes a new RequestIDHandler that uses the X-Request-ID
+// header, and the default generator and validator.
+func NewRequestIDHandler() *RequestIDHandler {
+	return &RequestIDHandler{
+		HeaderName: RequestIDHeader,
+		Generator:  DefaultRequestIDGenerator,
+		Validator:  DefaultRequestIDValidator}
+}
+
+// WillHandle always returns true, as every request needs an ID.
+func (h *RequestIDHandler) WillHandle(context.Context) (bool, error) {
+	return true, nil
+}
+
+// Handle sets the request ID on the context, and on the response.
+func (h *RequestIDHandler) Handle(ctx context.Context) (bool, error) {
+
+	headerName := h.HeaderName
+	if len(headerName) == 0 {
+		headerName = RequestIDHeader
+	}
+
+	validator := h.Validator
+	if validator == nil {
+		validator = DefaultRequestIDValidator
+	}
+
+	generator := h.Generator
+	if generator == nil {
+		generator = DefaultRequestIDGenerator
+	}
+
+	id := ctx.HttpRequest().Header.Get(headerName)
+
+	if len(id) == 0 || !validator(id) {
+		id = generator()
+	}
+
+	ctx.Data().Set(context.DataKeyRequestID, id)
+	ctx.HttpResponseWriter().Header().Set(headerName, id)
+
+	return false, nil
+}
+
+// RequestID gets the ID of the request in the specified context, or an
+// empty string if the request has no ID.
+//
+// Use RequestID when writing log entries, so they can be matched up with
+// what the client saw.
+func RequestID(ctx context.Context) string {
+	return ctx.Data().Get(context.DataKeyRequestID).Str()
+}
+
+// DefaultRequestIDGenerator generates a random 128 bit ID, encoded as
+// 32 hex characters.
+func DefaultRequestIDGenerator() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// DefaultRequestIDValidator accepts IDs that are no longer than
+// MaxRequestIDLength, and are made up of letters, digits and the
+// characters - _ . : + / =
+//
+// This keeps IDs safe to echo in headers, write to logs and include in
+// HTML pages.
+func DefaultRequestIDValidator(id string) bool {
+
+	if len(id) == 0 || len(id) > MaxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '+', c == '/', c == '=':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
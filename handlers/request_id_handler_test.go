@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"github.com/stretchr/goweb/context"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDHandler_Interface(t *testing.T) {
+
+	assert.Implements(t, (*Handler)(nil), new(RequestIDHandler))
+
+}
+
+func TestNewRequestIDHandler(t *testing.T) {
+
+	h := NewRequestIDHandler()
+
+	assert.Equal(t, RequestIDHeader, h.HeaderName)
+	assert.NotNil(t, h.Generator)
+	assert.NotNil(t, h.Validator)
+
+	willHandle, _ := h.WillHandle(context_test.MakeTestContext())
+	assert.True(t, willHandle)
+
+}
+
+func TestRequestIDHandler_GeneratesID(t *testing.T) {
+
+	h := NewRequestIDHandler()
+	h.Generator = func() string {
+		return "generated"
+	}
+	ctx := context_test.MakeTestContext()
+
+	stop, err := h.Handle(ctx)
+
+	assert.False(t, stop)
+	assert.NoError(t, err)
+	assert.Equal(t, "generated", ctx.Data().Get(context.DataKeyRequestID).Str())
+	assert.Equal(t, "generated", RequestID(ctx))
+	assert.Equal(t, "generated", ctx.HttpResponseWriter().Header().Get("X-Request-ID"))
+
+}
+
+func TestRequestIDHandler_UsesIncomingID(t *testing.T) {
+
+	h := NewRequestIDHandler()
+	ctx := context_test.MakeTestContext()
+	ctx.HttpRequest().Header.Set("X-Request-ID", "client-id:123")
+
+	h.Handle(ctx)
+
+	assert.Equal(t, "client-id:123", RequestID(ctx))
+	assert.Equal(t, "client-id:123", ctx.HttpResponseWriter().Header().Get("X-Request-ID"))
+
+}
+
+func TestRequestIDHandler_ReplacesInvalidID(t *testing.T) {
+
+	h := NewRequestIDHandler()
+	ctx := context_test.MakeTestContext()
+	ctx.HttpRequest().Header.Set("X-Request-ID", "<script>")
+
+	h.Handle(ctx)
+
+	assert.NotEqual(t, "<script>", RequestID(ctx))
+	assert.Equal(t, 32, len(RequestID(ctx)))
+
+}
+
+func TestRequestIDHandler_ZeroValue(t *testing.T) {
+
+	h := new(RequestIDHandler)
+	ctx := context_test.MakeTestContext()
+	ctx.HttpRequest().Header.Set("X-Request-ID", "<script>")
+
+	h.Handle(ctx)
+
+	assert.Equal(t, 32, len(RequestID(ctx)), "The default generator and validator should be used")
+	assert.Equal(t, RequestID(ctx), ctx.HttpResponseWriter().Header().Get("X-Request-ID"))
+
+}
+
+func TestDefaultRequestIDGenerator(t *testing.T) {
+
+	id := DefaultRequestIDGenerator()
+
+	assert.Equal(t, 32, len(id))
+	assert.True(t, DefaultRequestIDValidator(id))
+	assert.NotEqual(t, id, DefaultRequestIDGenerator())
+
+}
+
+func TestDefaultRequestIDValidator(t *testing.T) {
+
+	assert.True(t, DefaultRequestIDValidator("abc-123_DEF.456"))
+	assert.True(t, DefaultRequestIDValidator("Root=1-5759e988-bd862e3fe1be46a994272793"))
+	assert.False(t, DefaultRequestIDValidator(""))
+	assert.False(t, DefaultRequestIDValidator("has space"))
+	assert.False(t, DefaultRequestIDValidator("new\nline"))
+	assert.False(t, DefaultRequestIDValidator(strings.Repeat("a", MaxRequestIDLength+1)))
+
+}
+
+func TestRequestID_WithoutHandler(t *testing.T) {
+
+	assert.Equal(t, "", RequestID(context_test.MakeTestContext()))
+
+}
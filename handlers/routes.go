@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/http"
+	"github.com/stretchr/goweb/paths"
+	"github.com/stretchr/goweb/webcontext"
+	"html/template"
+	nethttp "net/http"
+	"strings"
+)
+
+// Phases of the HttpHandler that handlers are mapped in.
+const (
+	PhasePre     string = "pre"
+	PhaseProcess string = "process"
+	PhasePost    string = "post"
+)
+
+// phases are the phases of the pipes in HttpHandler.Handlers, in order.
+var phases []string = []string{PhasePre, PhaseProcess, PhasePost}
+
+// Route describes a single handler in an HttpHandler.
+type Route struct {
+
+	// Phase is the phase the handler runs in, i.e. PhaseProcess for handlers
+	// mapped with Map.
+	Phase string `json:"phase"`
+
+	// Methods are the HTTP methods the handler is mapped for, or empty if it
+	// handles any method.
+	Methods []string `json:"methods,omitempty"`
+
+	// Pattern is the raw path pattern of the mapping.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Name is the name given to the mapping (see the Name HandlerOption).
+	Name string `json:"name,omitempty"`
+
+	// Description is the description of the mapping.
+	Description string `json:"description,omitempty"`
+
+	// MatcherFuncs is the number of MatcherFuncs the mapping has.
+	MatcherFuncs int `json:"matcherFuncs,omitempty"`
+
+	// Controller is the type of the controller the mapping was made for.
+	Controller string `json:"controller,omitempty"`
+
+	// Action is the controller action the mapping was made for.
+	Action string `json:"action,omitempty"`
+
+	// Version is the version of the API the mapping is for.
+	Version string `json:"version,omitempty"`
+
+	// Host is the host pattern of the mapping.
+	Host string `json:"host,omitempty"`
+
+	// Type is the type of the handler.
+	Type string `json:"type"`
+
+	// Handler is the handler itself.
+	Handler Handler `json:"-"`
+}
+
+// Routes gets every handler in the HttpHandler, in the order they are consulted.
+func (h *HttpHandler) Routes() []Route {
+
+	var routes []Route
+
+	h.walkPhases(func(phase string, handler Handler) {
+		routes = append(routes, routeFor(phase, handler))
+	})
+
+	return routes
+
+}
+
+// walkPhases calls the func with every handler in the HttpHandler, in order,
+// flattening nested Pipes.
+func (h *HttpHandler) walkPhases(fn func(phase string, handler Handler)) {
+
+	var walk func(phase string, pipe Pipe)
+	walk = func(phase string, pipe Pipe) {
+		for _, handler := range pipe {
+			if nested, ok := handler.(Pipe); ok {
+				walk(phase, nested)
+			} else {
+				fn(phase, handler)
+			}
+		}
+	}
+
+	for index, handler := range h.Handlers {
+		var phase string
+		if index < len(phases) {
+			phase = phases[index]
+		}
+		if pipe, ok := handler.(Pipe); ok {
+			walk(phase, pipe)
+		} else {
+			fn(phase, handler)
+		}
+	}
+
+}
+
+// routeFor makes the Route describing the handler.
+func routeFor(phase string, handler Handler) Route {
+
+	route := Route{Phase: phase, Type: fmt.Sprintf("%T", handler), Handler: handler}
+
+	if pathMatchHandler, ok := handler.(*PathMatchHandler); ok {
+		route.Methods = pathMatchHandler.HttpMethods
+		if pathMatchHandler.PathPattern != nil {
+			route.Pattern = pathMatchHandler.PathPattern.RawPath
+		}
+		route.Name = pathMatchHandler.Name
+		route.Description = pathMatchHandler.Description
+		route.MatcherFuncs = len(pathMatchHandler.MatcherFuncs)
+		if pathMatchHandler.Controller != nil {
+			route.Controller = fmt.Sprintf("%T", pathMatchHandler.Controller)
+		}
+		route.Action = pathMatchHandler.ActionName
+		route.Version = pathMatchHandler.Version
+		if pathMatchHandler.HostPattern != nil {
+			route.Host = pathMatchHandler.HostPattern.RawHost
+		}
+	}
+
+	return route
+
+}
+
+// Explanation describes whether a handler would run for a request, and why.
+type Explanation struct {
+	Route
+
+	// Runs is whether the handler would run.
+	Runs bool `json:"runs"`
+
+	// Reason explains why the handler would or wouldn't run.
+	Reason string `json:"reason"`
+}
+
+// Explain works out which handlers would run for a request with the specified
+// method and path, and why the others wouldn't.
+//
+// The handlers aren't actually run, so handlers that decide what happens next
+// as they run (i.e. by calling Abort) aren't taken into account.
+func (h *HttpHandler) Explain(method, path string) ([]Explanation, error) {
+
+	request, requestErr := nethttp.NewRequest(method, path, nil)
+	if requestErr != nil {
+		return nil, requestErr
+	}
+
+	// build the context like ServeHTTP would
+	var version string
+	if h.VersioningPolicy != nil {
+		version = h.VersioningPolicy.Resolve(request)
+	}
+
+	ctx := webcontext.NewWebContext(new(explainResponseWriter), request, h.codecService)
+	if h.EncodedSlashes {
+		ctx.SetPath(paths.NewPathFromURL(request.URL))
+	}
+	for k, v := range h.Data {
+		ctx.Data()[k] = v
+	}
+	if len(version) > 0 {
+		ctx.Data().Set(context.DataKeyVersion, version)
+	}
+	if h.AutomaticHead && method == http.MethodHead {
+		if explicit, err := handlesHeadExplicitly(h.HandlersPipe(), ctx); err == nil && !explicit {
+			ctx.Data().Set(dataKeyAutomaticHead, true)
+		}
+	}
+
+	var explanations []Explanation
+	var explainErr error
+	stoppedPhases := make(map[string]bool)
+
+	h.walkPhases(func(phase string, handler Handler) {
+
+		if explainErr != nil {
+			return
+		}
+
+		explanation := Explanation{Route: routeFor(phase, handler)}
+
+		if stoppedPhases[phase] {
+			explanation.Reason = "an earlier handler finished the pipe"
+			explanations = append(explanations, explanation)
+			return
+		}
+
+		var willHandle bool
+		if pathMatchHandler, ok := handler.(*PathMatchHandler); ok {
+			willHandle, explanation.Reason, explainErr = pathMatchHandler.match(ctx)
+			stoppedPhases[phase] = willHandle && pathMatchHandler.BreakCurrentPipeline
+		} else {
+			willHandle, explainErr = handler.WillHandle(ctx)
+			explanation.Reason = fmt.Sprintf("WillHandle returned %t", willHandle)
+		}
+
+		explanation.Runs = willHandle
+		explanations = append(explanations, explanation)
+
+	})
+
+	if explainErr != nil {
+		return nil, explainErr
+	}
+
+	return explanations, nil
+
+}
+
+// explainResponseWriter is an http.ResponseWriter that discards everything, for
+// the contexts made by Explain.
+type explainResponseWriter struct {
+	header nethttp.Header
+}
+
+func (w *explainResponseWriter) Header() nethttp.Header {
+	if w.header == nil {
+		w.header = make(nethttp.Header)
+	}
+	return w.header
+}
+
+func (w *explainResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (w *explainResponseWriter) WriteHeader(int) {}
+
+// routesTemplate renders the route table as HTML.
+var routesTemplate *template.Template = template.Must(template.New("routes").Parse(`<!DOCTYPE html>
+<html><head><title>Routes</title>
+<style>
+table { border-collapse: collapse; font-family: sans-serif; font-size: 14px }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left }
+th { background-color: #eee }
+</style>
+</head><body>
+<table>
+<tr><th>Phase</th><th>Methods</th><th>Pattern</th><th>Name</th><th>Description</th><th>Matcher funcs</th><th>Controller</th><th>Action</th><th>Version</th><th>Host</th></tr>
+{{range .}}<tr><td>{{.Phase}}</td><td>{{range $i, $m := .Methods}}{{if $i}}, {{end}}{{$m}}{{end}}</td><td><code>{{.Pattern}}</code></td><td>{{.Name}}</td><td>{{.Description}}</td><td>{{.MatcherFuncs}}</td><td>{{.Controller}}</td><td>{{.Action}}</td><td>{{.Version}}</td><td>{{.Host}}</td></tr>
+{{end}}</table>
+</body></html>
+`))
+
+// RoutesEndpoint gets a func that writes the route table of the
+// specified HttpHandler, for debugging:
+//
+//     goweb.Map("GET", "debug/routes", handlers.RoutesEndpoint(goweb.DefaultHttpHandler()))
+//
+// The table is written as an HTML page if the path ends with .html or the client
+// accepts text/html, and as JSON otherwise.
+func RoutesEndpoint(httpHandler *HttpHandler) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+
+		routes := httpHandler.Routes()
+		w := ctx.HttpResponseWriter()
+
+		if ctx.FileExtension() == ".html" || strings.Contains(ctx.HttpRequest().Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			return routesTemplate.Execute(w, routes)
+		}
+
+		body, err := json.Marshal(routes)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(body)
+		return err
+
+	}
+}
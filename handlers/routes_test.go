@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func makeRoutesTestHandler() *HttpHandler {
+
+	noop := func(c context.Context) error {
+		return nil
+	}
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapBefore("people/***", noop, Name("people.before"))
+	h.Map("GET", "people/me", noop, Name("people.me"), Description("The current person"))
+	h.Map("GET", "people/{id}", noop, Version("2"))
+	h.Map("POST", "people", noop, func(c context.Context) (MatcherFuncDecision, error) {
+		return NoMatch, nil
+	})
+	h.MapController("books", new(testBooksController))
+	h.MapAfter("***", noop)
+
+	return h
+
+}
+
+func TestRoutes(t *testing.T) {
+
+	routes := makeRoutesTestHandler().Routes()
+
+	if assert.True(t, len(routes) > 5) {
+
+		assert.Equal(t, PhasePre, routes[0].Phase)
+		assert.Equal(t, "people/***", routes[0].Pattern)
+		assert.Equal(t, "people.before", routes[0].Name)
+
+		assert.Equal(t, PhaseProcess, routes[1].Phase)
+		assert.Equal(t, []string{"GET"}, routes[1].Methods)
+		assert.Equal(t, "people/me", routes[1].Pattern)
+		assert.Equal(t, "The current person", routes[1].Description)
+		assert.Equal(t, "*handlers.PathMatchHandler", routes[1].Type)
+
+		assert.Equal(t, "2", routes[2].Version)
+		assert.Equal(t, 1, routes[3].MatcherFuncs)
+
+		last := routes[len(routes)-1]
+		assert.Equal(t, PhasePost, last.Phase)
+		assert.Equal(t, "***", last.Pattern)
+
+		var controllerRoutes int
+		for _, route := range routes {
+			if len(route.Controller) > 0 {
+				controllerRoutes++
+				assert.Equal(t, "*handlers.testBooksController", route.Controller)
+			}
+		}
+		assert.True(t, controllerRoutes > 0)
+
+	}
+
+}
+
+func TestExplain(t *testing.T) {
+
+	explanations, err := makeRoutesTestHandler().Explain("GET", "http://goweb.org/people/me")
+
+	if assert.NoError(t, err) && assert.True(t, len(explanations) > 4) {
+
+		assert.True(t, explanations[0].Runs, "Before handler should run")
+		assert.Equal(t, "path matches people/***", explanations[0].Reason)
+
+		assert.True(t, explanations[1].Runs)
+		assert.Equal(t, "path matches people/me", explanations[1].Reason)
+
+		assert.False(t, explanations[2].Runs)
+		assert.Equal(t, "an earlier handler finished the pipe", explanations[2].Reason)
+
+		last := explanations[len(explanations)-1]
+		assert.True(t, last.Runs, "After handler should run")
+
+	}
+
+	explanations, _ = makeRoutesTestHandler().Explain("POST", "http://goweb.org/people")
+	assert.Equal(t, "method POST is not GET", explanations[1].Reason)
+	assert.Equal(t, "matcher func 1 did not match", explanations[3].Reason)
+
+	explanations, _ = makeRoutesTestHandler().Explain("GET", "http://goweb.org/people/123")
+	assert.Equal(t, "mapped for version 2", explanations[2].Reason)
+
+}
+
+func TestRoutesEndpoint(t *testing.T) {
+
+	h := makeRoutesTestHandler()
+	h.Map("GET", "debug/routes", RoutesEndpoint(h))
+
+	response := serveTestRequest(h, "GET", "http://goweb.org/debug/routes")
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+
+	var routes []Route
+	if assert.NoError(t, json.Unmarshal([]byte(response.Output), &routes)) {
+		assert.Equal(t, "people.me", routes[1].Name)
+	}
+
+	response = serveTestRequest(h, "GET", "http://goweb.org/debug/routes.html")
+	assert.Equal(t, "text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.True(t, strings.Contains(response.Output, "<td><code>people/me</code></td>"))
+
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/stretchr/goweb/context"
+	"strings"
+)
+
+const (
+	// CSPNoncePlaceholder is replaced with the request's nonce wherever it
+	// appears in the ContentSecurityPolicy.
+	CSPNoncePlaceholder string = "{nonce}"
+
+	// cspNonceLength is the number of random bytes in a CSP nonce.
+	cspNonceLength int = 16
+)
+
+// SecurityHeaders holds the values of the security related headers that a
+// SecurityHeadersHandler will set on responses.
+//
+// Empty values are not written.
+type SecurityHeaders struct {
+
+	// StrictTransportSecurity is the value of the Strict-Transport-Security header.
+	StrictTransportSecurity string
+
+	// ContentTypeOptions is the value of the X-Content-Type-Options header.
+	ContentTypeOptions string
+
+	// FrameOptions is the value of the X-Frame-Options header.
+	FrameOptions string
+
+	// ReferrerPolicy is the value of the Referrer-Policy header.
+	ReferrerPolicy string
+
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header.
+	//
+	// Any CSPNoncePlaceholder in the policy will be replaced with a new nonce
+	// for each request.
+	ContentSecurityPolicy string
+}
+
+// DefaultSecurityHeaders are the SecurityHeaders used by new
+// SecurityHeadersHandlers.
+var DefaultSecurityHeaders = SecurityHeaders{
+	StrictTransportSecurity: "max-age=31536000; includeSubDomains",
+	ContentTypeOptions:      "nosniff",
+	FrameOptions:            "SAMEORIGIN",
+	ReferrerPolicy:          "strict-origin-when-cross-origin",
+	ContentSecurityPolicy:   "default-src 'self'; script-src 'self' 'nonce-{nonce}'; object-src 'none'; base-uri 'self'"}
+
+// securityHeadersOverride is a set of SecurityHeaders to use instead of the
+// defaults when the MatcherFuncs match.
+type securityHeadersOverride struct {
+	headers      SecurityHeaders
+	matcherFuncs []MatcherFunc
+}
+
+// SecurityHeadersHandler is a Handler that sets security related headers, such
+// as Strict-Transport-Security and Content-Security-Policy, on every response.
+//
+// It should be added to the pre handlers pipe:
+//
+//     securityHeaders := handlers.NewSecurityHeadersHandler()
+//     handler.AppendPreHandler(securityHeaders)
+//
+// Different headers can be used for some requests with the Override method:
+//
+//     embeddable := securityHeaders.Defaults
+//     embeddable.FrameOptions = ""
+//     securityHeaders.Override(embeddable, goweb.RegexPath(`^widgets/.*`))
+//
+// CSP nonces
+//
+// If the ContentSecurityPolicy contains the {nonce} placeholder, a new nonce
+// is generated for each request and stored in the context.Data with the
+// context.DataKeyCSPNonce key.  Templates should put the nonce in the nonce
+// attribute of inline scripts:
+//
+//     <script nonce="{{.Nonce}}">...</script>
+//
+// Use the CSPNonce func to get the nonce from the context.
+type SecurityHeadersHandler struct {
+
+	// Defaults are the headers to set when no overrides match.
+	Defaults SecurityHeaders
+
+	// overrides are checked in order, and the first one that matches is used
+	// instead of the Defaults.
+	overrides []securityHeadersOverride
+}
+
+// NewSecurityHeadersHandler makes a new SecurityHeadersHandler that uses the
+// DefaultSecurityHeaders.
+func NewSecurityHeadersHandler() *SecurityHeadersHandler {
+	return &SecurityHeadersHandler{Defaults: DefaultSecurityHeaders}
+}
+
+// Override tells the SecurityHeadersHandler to use the specified headers instead of
+// the Defaults for requests matched by the MatcherFuncs.
+//
+// Like with mappings, the first MatcherFunc to return Match or NoMatch decides
+// whether the override is used or not.  If every MatcherFunc returns DontCare, the
+// override is not used.
+func (h *SecurityHeadersHandler) Override(headers SecurityHeaders, matcherFuncs ...MatcherFunc) {
+	h.overrides = append(h.overrides, securityHeadersOverride{headers, matcherFuncs})
+}
+
+// WillHandle always returns true, as every response needs the headers.
+func (h *SecurityHeadersHandler) WillHandle(context.Context) (bool, error) {
+	return true, nil
+}
+
+// Handle sets the headers on the response.
+func (h *SecurityHeadersHandler) Handle(ctx context.Context) (bool, error) {
+
+	headers, headersErr := h.headersFor(ctx)
+	if headersErr != nil {
+		return false, headersErr
+	}
+
+	csp := headers.ContentSecurityPolicy
+	if strings.Contains(csp, CSPNoncePlaceholder) {
+		nonce := newCSPNonce()
+		ctx.Data().Set(context.DataKeyCSPNonce, nonce)
+		csp = strings.Replace(csp, CSPNoncePlaceholder, nonce, -1)
+	}
+
+	responseHeader := ctx.HttpResponseWriter().Header()
+	for name, value := range map[string]string{
+		"Strict-Transport-Security": headers.StrictTransportSecurity,
+		"X-Content-Type-Options":    headers.ContentTypeOptions,
+		"X-Frame-Options":           headers.FrameOptions,
+		"Referrer-Policy":           headers.ReferrerPolicy,
+		"Content-Security-Policy":   csp} {
+		if len(value) > 0 {
+			responseHeader.Set(name, value)
+		}
+	}
+
+	return false, nil
+}
+
+// headersFor gets the SecurityHeaders to use for the specified context.
+func (h *SecurityHeadersHandler) headersFor(ctx context.Context) (SecurityHeaders, error) {
+
+	for _, override := range h.overrides {
+		for _, matcherFunc := range override.matcherFuncs {
+
+			decision, matcherFuncErr := matcherFunc(ctx)
+			if matcherFuncErr != nil {
+				return h.Defaults, matcherFuncErr
+			}
+
+			if decision == Match {
+				return override.headers, nil
+			}
+			if decision == NoMatch {
+				break
+			}
+
+		}
+	}
+
+	return h.Defaults, nil
+}
+
+// CSPNonce gets the Content-Security-Policy nonce for the request in the
+// specified context, or an empty string if there isn't one.
+func CSPNonce(ctx context.Context) string {
+	return ctx.Data().Get(context.DataKeyCSPNonce).Str()
+}
+
+// newCSPNonce generates a new random nonce.
+func newCSPNonce() string {
+	nonce := make([]byte, cspNonceLength)
+	rand.Read(nonce)
+	return base64.StdEncoding.EncodeToString(nonce)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/stretchr/goweb/context"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSecurityHeadersHandler_Interface(t *testing.T) {
+
+	assert.Implements(t, (*Handler)(nil), new(SecurityHeadersHandler))
+
+}
+
+func TestSecurityHeadersHandler_Defaults(t *testing.T) {
+
+	h := NewSecurityHeadersHandler()
+	ctx := context_test.MakeTestContext()
+
+	willHandle, _ := h.WillHandle(ctx)
+	assert.True(t, willHandle)
+
+	stop, err := h.Handle(ctx)
+	assert.False(t, stop)
+	assert.NoError(t, err)
+
+	header := ctx.HttpResponseWriter().Header()
+	assert.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	assert.Equal(t, "SAMEORIGIN", header.Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", header.Get("Referrer-Policy"))
+
+	nonce := CSPNonce(ctx)
+	assert.Equal(t, 24, len(nonce))
+	assert.Equal(t, nonce, ctx.Data().Get(context.DataKeyCSPNonce).Str())
+	assert.True(t, strings.Contains(header.Get("Content-Security-Policy"), "'nonce-"+nonce+"'"))
+
+}
+
+func TestSecurityHeadersHandler_NoncePerRequest(t *testing.T) {
+
+	h := NewSecurityHeadersHandler()
+
+	ctx1 := context_test.MakeTestContext()
+	h.Handle(ctx1)
+	ctx2 := context_test.MakeTestContext()
+	h.Handle(ctx2)
+
+	assert.NotEqual(t, CSPNonce(ctx1), CSPNonce(ctx2))
+
+}
+
+func TestSecurityHeadersHandler_NoNonceWithoutPlaceholder(t *testing.T) {
+
+	h := NewSecurityHeadersHandler()
+	h.Defaults.ContentSecurityPolicy = "default-src 'self'"
+	h.Defaults.StrictTransportSecurity = ""
+	ctx := context_test.MakeTestContext()
+
+	h.Handle(ctx)
+
+	assert.Equal(t, "", CSPNonce(ctx))
+	assert.Equal(t, "default-src 'self'", ctx.HttpResponseWriter().Header().Get("Content-Security-Policy"))
+	_, hasHSTS := ctx.HttpResponseWriter().Header()["Strict-Transport-Security"]
+	assert.False(t, hasHSTS)
+
+}
+
+func TestSecurityHeadersHandler_Override(t *testing.T) {
+
+	h := NewSecurityHeadersHandler()
+
+	embeddable := h.Defaults
+	embeddable.FrameOptions = ""
+	h.Override(embeddable, RegexPath(`^widgets/.*`))
+
+	ctx := context_test.MakeTestContextWithPath("widgets/clock")
+	h.Handle(ctx)
+	assert.Equal(t, "", ctx.HttpResponseWriter().Header().Get("X-Frame-Options"))
+	assert.Equal(t, "nosniff", ctx.HttpResponseWriter().Header().Get("X-Content-Type-Options"))
+
+	ctx = context_test.MakeTestContextWithPath("people")
+	h.Handle(ctx)
+	assert.Equal(t, "SAMEORIGIN", ctx.HttpResponseWriter().Header().Get("X-Frame-Options"))
+
+}
+
+func TestSecurityHeadersHandler_OverrideDontCare(t *testing.T) {
+
+	h := NewSecurityHeadersHandler()
+	h.Override(SecurityHeaders{}, func(ctx context.Context) (MatcherFuncDecision, error) {
+		return DontCare, nil
+	})
+
+	ctx := context_test.MakeTestContext()
+	h.Handle(ctx)
+	assert.Equal(t, "nosniff", ctx.HttpResponseWriter().Header().Get("X-Content-Type-Options"))
+
+}
+
+func TestSecurityHeadersHandler_OverrideError(t *testing.T) {
+
+	h := NewSecurityHeadersHandler()
+	h.Override(SecurityHeaders{}, func(ctx context.Context) (MatcherFuncDecision, error) {
+		return DontCare, errors.New("Test error")
+	})
+
+	_, err := h.Handle(context_test.MakeTestContext())
+	assert.Equal(t, "Test error", err.Error())
+
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// VersionSource represents a place in the request that a VersioningPolicy may
+// look for the version of the API the client wants.
+//
+// Sources can be combined with the | operator.
+type VersionSource int
+
+const (
+	// VersionFromPath reads the version from a prefix of the path, i.e.
+	// /v2/people.  The prefix is removed before the path is matched, so the
+	// mapping would be for "people".
+	VersionFromPath VersionSource = 1 << iota
+
+	// VersionFromHeader reads the version from the X-API-Version header.
+	VersionFromHeader
+
+	// VersionFromMediaType reads the version from the version parameter of the
+	// Accept header, i.e. application/vnd.acme+json; version=2.
+	VersionFromMediaType
+)
+
+const (
+	// DefaultVersionPathPrefix is the default prefix of versions in paths, that
+	// VersionFromPath reads.
+	DefaultVersionPathPrefix string = "v"
+
+	// DefaultVersionHeader is the default header that VersionFromHeader reads.
+	DefaultVersionHeader string = "X-API-Version"
+
+	// DefaultVersionMediaTypeParameter is the default media type parameter that
+	// VersionFromMediaType reads.
+	DefaultVersionMediaTypeParameter string = "version"
+)
+
+// Deprecation describes an old version of the API that clients should stop
+// using.
+type Deprecation struct {
+
+	// Sunset is when the version will stop working, or the zero time if that
+	// hasn't been decided.  It is sent in the Sunset header (RFC 8594).
+	Sunset time.Time
+
+	// Link is the URL of a page that explains the deprecation, or an empty
+	// string.  It is sent in a Link header with the "deprecation" relation.
+	Link string
+}
+
+// VersioningPolicy describes how the version of the API that a request is for
+// is decided, allowing different versions of handlers and controllers to be
+// mapped side by side:
+//
+//     policy := handlers.NewVersioningPolicy(handlers.VersionFromPath | handlers.VersionFromMediaType)
+//     policy.Versions = []string{"1", "2"}
+//     policy.Deprecations["1"] = handlers.Deprecation{Sunset: sunset}
+//     goweb.DefaultHttpHandler().VersioningPolicy = policy
+//
+//     goweb.MapController("people", peopleControllerV1, handlers.Version("1"))
+//     goweb.MapController("people", peopleControllerV2, handlers.Version("2"))
+//
+// Handlers mapped without a Version handle requests for every version.
+//
+// Requests that don't say which version they want, or ask for a version that
+// isn't one of the Versions, get the DefaultVersion.  The version is kept in
+// the context.Data with the context.DataKeyVersion key, and sent back to the
+// client in the HeaderName header.  Use the RequestVersion func to get it.
+type VersioningPolicy struct {
+
+	// Sources are the places in the request to look for the version.  If more
+	// than one source is allowed, the path is checked first, then the header, then
+	// the media type.
+	Sources VersionSource
+
+	// PathPrefix is the prefix of versions in paths, read by VersionFromPath.
+	PathPrefix string
+
+	// HeaderName is the name of the header read by VersionFromHeader.
+	HeaderName string
+
+	// MediaTypeParameter is the name of the Accept header parameter read by
+	// VersionFromMediaType.
+	MediaTypeParameter string
+
+	// Versions are all the versions of the API, oldest first.  If empty, any
+	// version is allowed.
+	Versions []string
+
+	// DefaultVersion is the version of requests that don't specify a known
+	// version.  If empty, the last of the Versions is used.
+	DefaultVersion string
+
+	// Deprecations describes the versions that are deprecated.  Responses to
+	// requests for them get Deprecation and Sunset headers.
+	Deprecations map[string]Deprecation
+}
+
+// NewVersioningPolicy makes a new VersioningPolicy that reads the version from
+// the specified sources.
+func NewVersioningPolicy(sources VersionSource) *VersioningPolicy {
+	return &VersioningPolicy{
+		Sources:            sources,
+		PathPrefix:         DefaultVersionPathPrefix,
+		HeaderName:         DefaultVersionHeader,
+		MediaTypeParameter: DefaultVersionMediaTypeParameter,
+		Deprecations:       make(map[string]Deprecation)}
+}
+
+// Resolve gets the version of the API the request is for.
+//
+// If the version was in the path, the prefix is removed from the request's URL.
+func (p *VersioningPolicy) Resolve(request *http.Request) string {
+
+	version := p.requestedVersion(request)
+
+	if len(version) == 0 || (len(p.Versions) > 0 && !containsVersion(p.Versions, version)) {
+		version = p.defaultVersion()
+	}
+
+	return version
+}
+
+// WriteHeaders tells the client which version their request was handled with,
+// and whether it is deprecated.
+func (p *VersioningPolicy) WriteHeaders(version string, header http.Header) {
+
+	if len(version) == 0 {
+		return
+	}
+
+	header.Set(p.HeaderName, version)
+
+	deprecation, deprecated := p.Deprecations[version]
+	if !deprecated {
+		return
+	}
+
+	header.Set("Deprecation", "true")
+	if !deprecation.Sunset.IsZero() {
+		header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if len(deprecation.Link) > 0 {
+		header.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", deprecation.Link))
+	}
+
+}
+
+// defaultVersion gets the version for requests that don't specify a known one.
+func (p *VersioningPolicy) defaultVersion() string {
+	if len(p.DefaultVersion) > 0 || len(p.Versions) == 0 {
+		return p.DefaultVersion
+	}
+	return p.Versions[len(p.Versions)-1]
+}
+
+// requestedVersion gets the version the client has asked for from the first
+// allowed source that has one.
+func (p *VersioningPolicy) requestedVersion(request *http.Request) string {
+
+	if p.Sources&VersionFromPath != 0 {
+		if version := p.versionFromPath(request); len(version) > 0 {
+			return version
+		}
+	}
+
+	if p.Sources&VersionFromHeader != 0 {
+		if version := normalizeVersion(request.Header.Get(p.HeaderName)); len(version) > 0 {
+			return version
+		}
+	}
+
+	if p.Sources&VersionFromMediaType != 0 {
+		for _, accept := range strings.Split(request.Header.Get("Accept"), ",") {
+			if _, params, err := mime.ParseMediaType(accept); err == nil {
+				if version := normalizeVersion(params[p.MediaTypeParameter]); len(version) > 0 {
+					return version
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// versionPattern matches the versions that can be used in paths.
+var versionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+
+// versionFromPath gets the version from the first segment of the request's path,
+// removing it if there is one.
+func (p *VersioningPolicy) versionFromPath(request *http.Request) string {
+
+	path := strings.TrimPrefix(request.URL.Path, "/")
+	segment := path
+	var rest string
+	if slash := strings.Index(path, "/"); slash != -1 {
+		segment, rest = path[:slash], path[slash:]
+	}
+
+	if !strings.HasPrefix(segment, p.PathPrefix) || !versionPattern.MatchString(segment[len(p.PathPrefix):]) {
+		return ""
+	}
+
+	request.URL.Path = "/" + strings.TrimPrefix(rest, "/")
+
+	// keep the escaped form of the rest of the path (i.e. %2F), which the
+	// version segment can't have any of
+	rawPath := strings.TrimPrefix(request.URL.RawPath, "/")
+	if strings.HasPrefix(rawPath, segment+"/") {
+		request.URL.RawPath = "/" + strings.TrimPrefix(rawPath[len(segment):], "/")
+	} else {
+		request.URL.RawPath = ""
+	}
+
+	return segment[len(p.PathPrefix):]
+}
+
+// normalizeVersion removes whitespace and any "v" prefix from the version.
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		version = version[1:]
+	}
+	return version
+}
+
+// containsVersion gets whether the versions contain the specified version.
+func containsVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Version is a HandlerOption that maps handlers (or controllers) for a
+// specific version of the API.  See VersioningPolicy.
+func Version(version string) HandlerOption {
+	return func(handler *PathMatchHandler) error {
+		handler.Version = normalizeVersion(version)
+		return nil
+	}
+}
+
+// RequestVersion gets the version of the API that the request in the specified
+// context is for, or an empty string if there is no VersioningPolicy.
+func RequestVersion(ctx context.Context) string {
+	return ctx.Data().Get(context.DataKeyVersion).Str()
+}
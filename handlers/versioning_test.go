@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	"github.com/stretchr/goweb/responders"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewVersioningPolicy(t *testing.T) {
+
+	p := NewVersioningPolicy(VersionFromHeader)
+
+	assert.Equal(t, VersionFromHeader, p.Sources)
+	assert.Equal(t, DefaultVersionPathPrefix, p.PathPrefix)
+	assert.Equal(t, DefaultVersionHeader, p.HeaderName)
+	assert.Equal(t, DefaultVersionMediaTypeParameter, p.MediaTypeParameter)
+
+}
+
+func TestVersioningPolicy_Resolve(t *testing.T) {
+
+	p := NewVersioningPolicy(VersionFromPath | VersionFromHeader | VersionFromMediaType)
+	p.Versions = []string{"1", "2"}
+
+	request, _ := http.NewRequest("GET", "http://goweb.org/v1/people/123", nil)
+	assert.Equal(t, "1", p.Resolve(request))
+	assert.Equal(t, "/people/123", request.URL.Path)
+
+	request, _ = http.NewRequest("GET", "http://goweb.org/v1", nil)
+	assert.Equal(t, "1", p.Resolve(request))
+	assert.Equal(t, "/", request.URL.Path)
+
+	request, _ = http.NewRequest("GET", "http://goweb.org/very/important", nil)
+	request.Header.Set("X-API-Version", "v1")
+	assert.Equal(t, "1", p.Resolve(request))
+	assert.Equal(t, "/very/important", request.URL.Path)
+
+	request, _ = http.NewRequest("GET", "http://goweb.org/people", nil)
+	request.Header.Set("Accept", "text/html, application/vnd.acme+json; version=1")
+	assert.Equal(t, "1", p.Resolve(request))
+
+	// fallbacks
+	request, _ = http.NewRequest("GET", "http://goweb.org/people", nil)
+	assert.Equal(t, "2", p.Resolve(request), "Latest version by default")
+
+	request, _ = http.NewRequest("GET", "http://goweb.org/people", nil)
+	request.Header.Set("X-API-Version", "3")
+	assert.Equal(t, "2", p.Resolve(request), "Unknown versions get the default")
+
+	p.DefaultVersion = "1"
+	assert.Equal(t, "1", p.Resolve(request))
+
+	// sources are respected
+	p = NewVersioningPolicy(VersionFromHeader)
+	request, _ = http.NewRequest("GET", "http://goweb.org/v1/people", nil)
+	assert.Equal(t, "", p.Resolve(request))
+	assert.Equal(t, "/v1/people", request.URL.Path)
+
+}
+
+func TestVersioningPolicy_WriteHeaders(t *testing.T) {
+
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p := NewVersioningPolicy(VersionFromHeader)
+	p.Deprecations["1"] = Deprecation{Sunset: sunset, Link: "http://goweb.org/v1-deprecated"}
+
+	header := make(http.Header)
+	p.WriteHeaders("2", header)
+	assert.Equal(t, "2", header.Get("X-API-Version"))
+	assert.Equal(t, "", header.Get("Deprecation"))
+
+	header = make(http.Header)
+	p.WriteHeaders("1", header)
+	assert.Equal(t, "true", header.Get("Deprecation"))
+	assert.Equal(t, "Tue, 01 Jan 2030 00:00:00 GMT", header.Get("Sunset"))
+	assert.Equal(t, "<http://goweb.org/v1-deprecated>; rel=\"deprecation\"", header.Get("Link"))
+
+}
+
+type testVersionedPeopleController struct {
+	version string
+	called  *string
+}
+
+func (c *testVersionedPeopleController) Read(id string, ctx context.Context) error {
+	*c.called = c.version + ":" + id
+	return nil
+}
+
+func TestVersioning(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.VersioningPolicy = NewVersioningPolicy(VersionFromPath | VersionFromHeader)
+	h.VersioningPolicy.Versions = []string{"1", "2"}
+
+	var called string
+	h.MapController("people", &testVersionedPeopleController{"1", &called}, Version("1"))
+	h.MapController("people", &testVersionedPeopleController{"2", &called}, Version("v2"))
+	h.Map("GET", "status", func(c context.Context) error {
+		called = "status:" + RequestVersion(c)
+		return nil
+	})
+
+	serveTestRequest(h, "GET", "http://goweb.org/v1/people/123")
+	assert.Equal(t, "1:123", called)
+
+	response := serveTestRequest(h, "GET", "http://goweb.org/v2/people/123")
+	assert.Equal(t, "2:123", called)
+	assert.Equal(t, "2", response.Header().Get("X-API-Version"))
+
+	serveTestRequest(h, "GET", "http://goweb.org/people/123")
+	assert.Equal(t, "2:123", called)
+
+	request, _ := http.NewRequest("GET", "http://goweb.org/people/123", nil)
+	request.Header.Set("X-API-Version", "1")
+	h.ServeHTTP(new(http_test.TestResponseWriter), request)
+	assert.Equal(t, "1:123", called)
+
+	// unversioned mappings handle every version
+	serveTestRequest(h, "GET", "http://goweb.org/v1/status")
+	assert.Equal(t, "status:1", called)
+
+	// routes show their versions
+	assert.Contains(t, h.String(), "people/{id} (version 1)")
+	assert.Contains(t, h.String(), "people/{id} (version 2)")
+
+}
+
+func TestVersioning_EncodedSlashes(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.VersioningPolicy = NewVersioningPolicy(VersionFromPath)
+	h.VersioningPolicy.Versions = []string{"1", "2"}
+	h.EncodedSlashes = true
+
+	var called string
+	h.Map("GET", "files/{name}", func(c context.Context) error {
+		called = RequestVersion(c) + ":" + c.PathValue("name")
+		return nil
+	})
+
+	serveTestRequest(h, "GET", "http://goweb.org/v2/files/a%2Fb")
+	assert.Equal(t, "2:a/b", called)
+
+	serveTestRequest(h, "GET", "http://goweb.org/v1/files/reports")
+	assert.Equal(t, "1:reports", called)
+
+	called = ""
+	serveTestRequest(h, "GET", "http://goweb.org/v2/files/a/b")
+	assert.Equal(t, "", called, "Unescaped slashes still separate segments")
+
+}
+
+func TestVersioning_ResourceLocation(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+	h.VersioningPolicy = NewVersioningPolicy(VersionFromPath)
+
+	api := responders.NewGowebAPIResponder(codecsservices.NewWebCodecService(), new(responders.GowebHTTPResponder))
+	h.MapController("people", controllers.NewResource[map[string]interface{}](controllers.NewMemoryRepository[map[string]interface{}](), api), Version("2"))
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/v2/people", strings.NewReader(`{"name":"Mat"}`))
+	request.RequestURI = "/v2/people"
+	request.Header.Set("Content-Type", "application/json")
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "/v2/people/1", response.Header().Get("Location"), "The Location keeps the version prefix")
+
+}
+
+func TestVersioning_NoPolicy(t *testing.T) {
+
+	h := NewHttpHandler(codecsservices.NewWebCodecService())
+
+	called := false
+	h.Map("GET", "people", func(c context.Context) error {
+		called = true
+		return nil
+	}, Version("1"))
+
+	serveTestRequest(h, "GET", "http://goweb.org/people")
+	assert.False(t, called)
+
+}
@@ -0,0 +1,21 @@
+// The idempotency package lets clients safely retry unsafe requests (i.e. POST
+// requests to RestfulCreator.Create actions) by sending an Idempotency-Key header.
+//
+// The first response to a request with a given key is stored, and replayed for
+// retries of it, so the action only runs once.  Retries that arrive while the
+// first request is still being processed get a 409 Conflict response, and reusing
+// a key for a request with a different body gets a 422 Unprocessable Entity
+// response.
+//
+// Keys are scoped to the principal (by default, whoever is identified by the
+// Authorization header), the HTTP method and the path, so different users and
+// endpoints never see each other's responses.
+//
+// To use it, make a Handler with a Store and install it in your HttpHandler:
+//
+//     idempotencyHandler := idempotency.NewHandler(idempotency.NewMemoryStore(24 * time.Hour))
+//     idempotencyHandler.Install(goweb.DefaultHttpHandler())
+//
+// Responses with 5xx status codes, or to requests that caused errors (or whose
+// handlers panicked), are not stored, so that clients can retry them.
+package idempotency
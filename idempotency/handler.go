@@ -0,0 +1,195 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"net/http"
+)
+
+const (
+	// KeyHeader is the HTTP header clients send the idempotency key in.
+	KeyHeader string = "Idempotency-Key"
+
+	// ReplayedHeader is the HTTP header that is set to "true" on replayed responses.
+	ReplayedHeader string = "Idempotent-Replayed"
+
+	// MaxKeyLength is the longest key the Handler will accept.
+	MaxKeyLength int = 255
+
+	// dataKeyRequest is the data key for the request being tracked.
+	dataKeyRequest string = "idempotencyrequest"
+)
+
+// trackedRequest is a request that is being processed for the first time.
+type trackedRequest struct {
+	key      string
+	recorder *recorder
+}
+
+// Handler is a pre handler (and an Observer) that stores the responses to requests
+// with an idempotency key, and replays them for retries.
+type Handler struct {
+
+	// Store keeps track of the requests.
+	Store Store
+
+	// Methods are the HTTP methods that idempotency keys are honoured for.
+	Methods []string
+
+	// Required indicates whether requests using Methods must have an idempotency
+	// key.  Requests without one get a 400 Bad Request response.
+	Required bool
+
+	// Principal gets a string identifying who made the request, so that keys chosen
+	// by different clients don't clash.
+	Principal func(ctx context.Context) string
+}
+
+// NewHandler makes a new Handler that uses the specified Store, honours keys for
+// POST and PATCH requests, and identifies principals by their Authorization header.
+func NewHandler(store Store) *Handler {
+	return &Handler{
+		Store:     store,
+		Methods:   []string{"POST", "PATCH"},
+		Principal: DefaultPrincipal}
+}
+
+// DefaultPrincipal identifies the principal by a hash of the Authorization header
+// of the request.
+func DefaultPrincipal(ctx context.Context) string {
+	return hash([]byte(ctx.HttpRequest().Header.Get("Authorization")))
+}
+
+// Install adds the Handler to the start of the pre handlers of the specified
+// HttpHandler, and as an observer so it can store the responses.
+func (h *Handler) Install(httpHandler *handlers.HttpHandler) {
+	httpHandler.PrependPreHandler(h)
+	httpHandler.AddObserver(h)
+}
+
+// WillHandle gets whether the request uses one of the Methods.
+func (h *Handler) WillHandle(ctx context.Context) (bool, error) {
+	for _, method := range h.Methods {
+		if method == ctx.MethodString() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Handle replays the stored response if the request has been seen before, or
+// starts recording the response if it hasn't.
+func (h *Handler) Handle(ctx context.Context) (bool, error) {
+
+	idempotencyKey := ctx.HttpRequest().Header.Get(KeyHeader)
+
+	if len(idempotencyKey) == 0 {
+		if h.Required {
+			h.reject(ctx, http.StatusBadRequest, fmt.Sprintf("%s header is required", KeyHeader))
+		}
+		return false, nil
+	}
+
+	if len(idempotencyKey) > MaxKeyLength {
+		h.reject(ctx, http.StatusBadRequest, fmt.Sprintf("%s header is too long", KeyHeader))
+		return false, nil
+	}
+
+	body, bodyErr := ctx.RequestBody()
+	if bodyErr != nil {
+		return false, bodyErr
+	}
+
+	var principal string
+	if h.Principal != nil {
+		principal = h.Principal(ctx)
+	}
+
+	key := hash([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s", idempotencyKey, principal, ctx.MethodString(), ctx.Path().RawPath)))
+
+	response, beginErr := h.Store.Begin(key, hash(body))
+
+	switch beginErr {
+	case nil:
+	case ErrInFlight:
+		h.reject(ctx, http.StatusConflict, beginErr.Error())
+		return false, nil
+	case ErrFingerprintMismatch:
+		h.reject(ctx, http.StatusUnprocessableEntity, beginErr.Error())
+		return false, nil
+	default:
+		return false, beginErr
+	}
+
+	if response != nil {
+		h.replay(ctx, response)
+		return false, nil
+	}
+
+	// record the response for next time
+	request := &trackedRequest{key: key, recorder: newRecorder(ctx.HttpResponseWriter())}
+	ctx.SetHttpResponseWriter(request.recorder)
+	ctx.Data().Set(dataKeyRequest, request)
+
+	return false, nil
+
+}
+
+// RequestStarted does nothing, as the work is done in Handle.
+func (h *Handler) RequestStarted(ctx context.Context) {}
+
+// RequestFinished stores the response to a request being processed for the first
+// time, or abandons it if it failed so that it may be retried.
+func (h *Handler) RequestFinished(ctx context.Context, err error) {
+
+	request, ok := ctx.Data().Get(dataKeyRequest).Data().(*trackedRequest)
+	if !ok {
+		return
+	}
+
+	response := request.recorder.response()
+
+	if err != nil || response.Status >= 500 {
+		h.Store.Abandon(request.key)
+		return
+	}
+
+	h.Store.Complete(request.key, response)
+
+}
+
+// replay writes the stored response, keeping any headers that have already been
+// set for this request (i.e. its request ID), and stops the request from being
+// processed any further.
+func (h *Handler) replay(ctx context.Context, response *Response) {
+
+	header := ctx.HttpResponseWriter().Header()
+	for name, values := range response.Header {
+		if _, exists := header[name]; !exists {
+			header[name] = append([]string(nil), values...)
+		}
+	}
+	header.Set(ReplayedHeader, "true")
+
+	ctx.HttpResponseWriter().WriteHeader(response.Status)
+	ctx.HttpResponseWriter().Write(response.Body)
+
+	handlers.Abort(ctx)
+
+}
+
+// reject responds with the specified status code and message, and stops the
+// request from being processed any further.
+func (h *Handler) reject(ctx context.Context, status int, message string) {
+	http.Error(ctx.HttpResponseWriter(), message, status)
+	handlers.Abort(ctx)
+}
+
+// hash gets the hex encoded SHA-256 hash of the data.
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,171 @@
+package idempotency
+
+import (
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeTestHttpHandler(creator func(ctx context.Context) error) (*handlers.HttpHandler, *Handler) {
+	httpHandler := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	httpHandler.Map("POST", "people", creator)
+	idempotencyHandler := NewHandler(NewMemoryStore(time.Hour))
+	idempotencyHandler.Install(httpHandler)
+	return httpHandler, idempotencyHandler
+}
+
+func serveTestRequest(h *handlers.HttpHandler, method, key, authorization, body string) *http_test.TestResponseWriter {
+	request, _ := http.NewRequest(method, "http://goweb.org/people", strings.NewReader(body))
+	if len(key) > 0 {
+		request.Header.Set(KeyHeader, key)
+	}
+	if len(authorization) > 0 {
+		request.Header.Set("Authorization", authorization)
+	}
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+	return response
+}
+
+func TestHandler_Replay(t *testing.T) {
+
+	created := 0
+	h, _ := makeTestHttpHandler(func(ctx context.Context) error {
+		created++
+		ctx.HttpResponseWriter().Header().Set("Location", "/people/1")
+		ctx.HttpResponseWriter().WriteHeader(201)
+		ctx.HttpResponseWriter().Write([]byte("Mat"))
+		return nil
+	})
+
+	response := serveTestRequest(h, "POST", "abc", "", `{"name":"Mat"}`)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, "", response.Header().Get(ReplayedHeader))
+
+	response = serveTestRequest(h, "POST", "abc", "", `{"name":"Mat"}`)
+	assert.Equal(t, 1, created, "Retries should not run the action again")
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, "/people/1", response.Header().Get("Location"))
+	assert.Equal(t, "true", response.Header().Get(ReplayedHeader))
+
+	// different principals don't share keys
+	serveTestRequest(h, "POST", "abc", "Bearer tyler", `{"name":"Mat"}`)
+	assert.Equal(t, 2, created)
+
+	// requests without keys are processed as normal
+	serveTestRequest(h, "POST", "", "", `{"name":"Mat"}`)
+	serveTestRequest(h, "POST", "", "", `{"name":"Mat"}`)
+	assert.Equal(t, 4, created)
+
+}
+
+func TestHandler_Mismatch(t *testing.T) {
+
+	created := 0
+	h, _ := makeTestHttpHandler(func(ctx context.Context) error {
+		created++
+		return nil
+	})
+
+	serveTestRequest(h, "POST", "abc", "", `{"name":"Mat"}`)
+	response := serveTestRequest(h, "POST", "abc", "", `{"name":"Tyler"}`)
+
+	assert.Equal(t, 1, created)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+
+}
+
+func TestHandler_InFlight(t *testing.T) {
+
+	var h *handlers.HttpHandler
+	var retry *http_test.TestResponseWriter
+	created := 0
+	h, _ = makeTestHttpHandler(func(ctx context.Context) error {
+		created++
+		if created == 1 {
+			// the client retries before we've finished
+			retry = serveTestRequest(h, "POST", "abc", "", `{"name":"Mat"}`)
+		}
+		return nil
+	})
+
+	serveTestRequest(h, "POST", "abc", "", `{"name":"Mat"}`)
+
+	assert.Equal(t, 1, created)
+	assert.Equal(t, http.StatusConflict, retry.StatusCode)
+
+}
+
+func TestHandler_Failures(t *testing.T) {
+
+	created := 0
+	h, _ := makeTestHttpHandler(func(ctx context.Context) error {
+		created++
+		if created == 1 {
+			return errors.New("database is down")
+		}
+		if created == 2 {
+			ctx.HttpResponseWriter().WriteHeader(503)
+		}
+		return nil
+	})
+
+	// errors and 5xx responses can be retried
+	serveTestRequest(h, "POST", "abc", "", `{}`)
+	serveTestRequest(h, "POST", "abc", "", `{}`)
+	serveTestRequest(h, "POST", "abc", "", `{}`)
+	serveTestRequest(h, "POST", "abc", "", `{}`)
+
+	assert.Equal(t, 3, created)
+
+}
+
+func TestHandler_Panic(t *testing.T) {
+
+	created := 0
+	h, _ := makeTestHttpHandler(func(ctx context.Context) error {
+		created++
+		if created == 1 {
+			panic("out of memory")
+		}
+		ctx.HttpResponseWriter().WriteHeader(201)
+		return nil
+	})
+
+	assert.Panics(t, func() {
+		serveTestRequest(h, "POST", "abc", "", `{}`)
+	})
+
+	// the request was abandoned, so it can be retried
+	response := serveTestRequest(h, "POST", "abc", "", `{}`)
+	assert.Equal(t, 2, created)
+	assert.Equal(t, 201, response.StatusCode)
+
+}
+
+func TestHandler_Required(t *testing.T) {
+
+	created := 0
+	h, idempotencyHandler := makeTestHttpHandler(func(ctx context.Context) error {
+		created++
+		return nil
+	})
+	idempotencyHandler.Required = true
+
+	response := serveTestRequest(h, "POST", "", "", `{}`)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response = serveTestRequest(h, "POST", strings.Repeat("a", MaxKeyLength+1), "", `{}`)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+}
@@ -0,0 +1,141 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInFlightTTL is how long a MemoryStore made by NewMemoryStore waits
+	// for an in progress request to complete before it is forgotten.
+	DefaultInFlightTTL time.Duration = 5 * time.Minute
+
+	// sweepInterval is how often a MemoryStore forgets all its expired requests.
+	sweepInterval time.Duration = time.Minute
+)
+
+// memoryRecord is a request known to a MemoryStore.
+type memoryRecord struct {
+	fingerprint string
+	response    *Response
+	expires     time.Time
+}
+
+// expired gets whether the record should be forgotten at the specified time.
+func (r *memoryRecord) expired(now time.Time) bool {
+	return !r.expires.IsZero() && now.After(r.expires)
+}
+
+// MemoryStore is a Store that keeps requests in memory, which is useful for
+// single process servers, prototyping and tests.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]*memoryRecord
+
+	// TTL is how long completed requests are remembered for.
+	TTL time.Duration
+
+	// InFlightTTL is how long in progress requests are remembered for, in case
+	// they never complete or are abandoned (i.e. the server is stopped half way
+	// through).  Zero means forever.
+	InFlightTTL time.Duration
+
+	// nextSweep is when expired requests will next be forgotten.
+	nextSweep time.Time
+
+	// now gets the current time.
+	now func() time.Time
+}
+
+// NewMemoryStore makes a new MemoryStore that remembers completed requests for
+// the specified duration, and in progress requests for DefaultInFlightTTL.
+//
+// Expired requests are forgotten when their key is next used, and all of them
+// are swept away from time to time, so the store doesn't keep growing.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{records: make(map[string]*memoryRecord), TTL: ttl, InFlightTTL: DefaultInFlightTTL, now: time.Now}
+}
+
+// Begin marks the request with the specified key as in progress, unless it has
+// been seen before.
+func (s *MemoryStore) Begin(key, fingerprint string) (*Response, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	record, exists := s.records[key]
+
+	if exists && record.expired(now) {
+		exists = false
+	}
+
+	if !exists {
+		record = &memoryRecord{fingerprint: fingerprint}
+		if s.InFlightTTL > 0 {
+			record.expires = now.Add(s.InFlightTTL)
+		}
+		s.records[key] = record
+		return nil, nil
+	}
+
+	if record.fingerprint != fingerprint {
+		return nil, ErrFingerprintMismatch
+	}
+
+	if record.response == nil {
+		return nil, ErrInFlight
+	}
+
+	return record.response, nil
+
+}
+
+// Complete stores the response to the request with the specified key.
+func (s *MemoryStore) Complete(key string, response *Response) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, exists := s.records[key]; exists {
+		record.response = response
+		record.expires = s.now().Add(s.TTL)
+	}
+
+	return nil
+
+}
+
+// Abandon forgets the in progress request with the specified key.
+func (s *MemoryStore) Abandon(key string) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, exists := s.records[key]; exists && record.response == nil {
+		delete(s.records, key)
+	}
+
+	return nil
+
+}
+
+// sweep forgets all the expired requests, unless it was done recently.  The
+// mutex must be locked.
+func (s *MemoryStore) sweep(now time.Time) {
+
+	if now.Before(s.nextSweep) {
+		return
+	}
+
+	for key, record := range s.records {
+		if record.expired(now) {
+			delete(s.records, key)
+		}
+	}
+
+	s.nextSweep = now.Add(sweepInterval)
+
+}
@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Interface(t *testing.T) {
+
+	assert.Implements(t, (*Store)(nil), NewMemoryStore(time.Hour))
+
+}
+
+func TestMemoryStore(t *testing.T) {
+
+	store := NewMemoryStore(time.Hour)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	response, err := store.Begin("key", "abc")
+	assert.Nil(t, response)
+	assert.NoError(t, err)
+
+	_, err = store.Begin("key", "abc")
+	assert.Equal(t, ErrInFlight, err)
+
+	_, err = store.Begin("key", "def")
+	assert.Equal(t, ErrFingerprintMismatch, err)
+
+	stored := &Response{Status: 201, Body: []byte("created")}
+	store.Complete("key", stored)
+
+	response, err = store.Begin("key", "abc")
+	assert.Equal(t, stored, response)
+	assert.NoError(t, err)
+
+	_, err = store.Begin("key", "def")
+	assert.Equal(t, ErrFingerprintMismatch, err)
+
+	// expired
+	now = now.Add(2 * time.Hour)
+	response, err = store.Begin("key", "def")
+	assert.Nil(t, response)
+	assert.NoError(t, err)
+
+}
+
+func TestMemoryStore_Abandon(t *testing.T) {
+
+	store := NewMemoryStore(time.Hour)
+
+	store.Begin("key", "abc")
+	store.Abandon("key")
+
+	response, err := store.Begin("key", "abc")
+	assert.Nil(t, response)
+	assert.NoError(t, err)
+
+	// completed requests can't be abandoned
+	store.Complete("key", &Response{Status: 200})
+	store.Abandon("key")
+
+	response, _ = store.Begin("key", "abc")
+	assert.NotNil(t, response)
+
+}
+
+func TestMemoryStore_InFlightTTL(t *testing.T) {
+
+	store := NewMemoryStore(time.Hour)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Begin("key", "abc")
+
+	_, err := store.Begin("key", "abc")
+	assert.Equal(t, ErrInFlight, err)
+
+	// the request never finished
+	now = now.Add(DefaultInFlightTTL + time.Second)
+	response, err := store.Begin("key", "abc")
+	assert.Nil(t, response)
+	assert.NoError(t, err)
+
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+
+	store := NewMemoryStore(time.Hour)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Begin("completed", "abc")
+	store.Complete("completed", &Response{Status: 201})
+	store.Begin("inflight", "abc")
+	assert.Equal(t, 2, len(store.records))
+
+	now = now.Add(2 * time.Hour)
+	store.Begin("new", "abc")
+	assert.Equal(t, 1, len(store.records), "Expired requests should be swept away")
+
+}
@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// recorder is an http.ResponseWriter that keeps a copy of the response as it
+// is written.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// newRecorder makes a new recorder that writes to the specified http.ResponseWriter.
+func newRecorder(responseWriter http.ResponseWriter) *recorder {
+	return &recorder{ResponseWriter: responseWriter}
+}
+
+// WriteHeader records the status code and passes it on.
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the data and passes it on.
+func (r *recorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// response gets the recorded Response.
+func (r *recorder) response() *Response {
+
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	header := make(http.Header)
+	for name, values := range r.Header() {
+		header[name] = append([]string(nil), values...)
+	}
+
+	return &Response{Status: status, Header: header, Body: r.body.Bytes()}
+
+}
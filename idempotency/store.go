@@ -0,0 +1,50 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrInFlight is returned by Store.Begin when a request with the same key is
+	// still being processed.
+	ErrInFlight = errors.New("idempotency: a request with this key is in progress")
+
+	// ErrFingerprintMismatch is returned by Store.Begin when the key has been used
+	// for a different request.
+	ErrFingerprintMismatch = errors.New("idempotency: this key was used for a different request")
+)
+
+// Response represents a stored response.
+type Response struct {
+	// Status is the HTTP status code.
+	Status int
+	// Header contains the response headers.
+	Header http.Header
+	// Body is the response body.
+	Body []byte
+}
+
+// Store represents an object that keeps track of requests and their responses by
+// idempotency key.
+//
+// Implementations must be safe for concurrent use, and Begin must be atomic, as it
+// is what prevents duplicate requests from being processed at the same time.
+type Store interface {
+
+	// Begin marks the request with the specified key as in progress, unless it has
+	// been seen before.
+	//
+	// If the key is new, Begin returns nil and nil.  If the request has already
+	// completed, its Response is returned.  Otherwise ErrFingerprintMismatch is
+	// returned if the fingerprint (a hash of the request body) is different to the
+	// original's, or ErrInFlight if the original is still in progress.
+	Begin(key, fingerprint string) (*Response, error)
+
+	// Complete stores the response to the request with the specified key.
+	Complete(key string, response *Response) error
+
+	// Abandon forgets the in progress request with the specified key, allowing it
+	// to be tried again.
+	Abandon(key string) error
+}
@@ -26,11 +26,40 @@ import (
 //
 //     (func [, matcherFuncs])
 //
+// Instead of a path pattern, a regular expression can be given as a handlers.Regexp.  The values
+// of its named capture groups become path parameters:
+//
+//     goweb.Map("GET", handlers.Regexp(`^people/(?P<id>[0-9]+)$`), readPersonHandler)
+//
 // Each matcherFunc argument can be one of three types:
 //     1) handlers.MatcherFunc
 //     2) []handlers.MatcherFunc
 //     3) func(context.Context) (MatcherFuncDecision, error)
 //
+// The handlers package has matcher funcs for common checks, such as handlers.HasHeader,
+// handlers.ContentType and handlers.HTTPS, which can be combined with handlers.And,
+// handlers.Or and handlers.Not:
+//
+//     goweb.Map("POST", "uploads", uploadHandler, handlers.Or(handlers.ContentType("image/*"), handlers.HasQuery("url")))
+//
+// HandlerOptions, such as handlers.Version, can be passed alongside the matcher funcs
+// to configure the handler that is mapped:
+//
+//     goweb.Map("GET", "people", peopleHandler, handlers.Version("2"))
+//
+// Use handlers.Host to map handlers for requests to particular hosts, and HttpHandler.Group
+// to map lots of handlers with the same options:
+//
+//     tenants := goweb.DefaultHttpHandler().Group(handlers.Host("{tenant}.example.com"))
+//     tenants.MapController("people", peopleController)
+//
+// Mappings are tried in the order they were made, so more specific paths should be mapped
+// first (or set OrderBySpecificity on the HttpHandler to have them tried in order of how
+// specific their paths are, so people/me is tried before people/{id}).  Map logs a warning
+// when a mapping can never be reached (i.e. people/{id}/photo mapped after people/***), or
+// when it is ambiguous with an earlier one.  Set StrictMapping
+// on the HttpHandler to make Map return these conflicts as errors instead.
+//
 // Examples
 //
 // The following code snippets are real examples of how to use the Map function:
@@ -136,6 +165,20 @@ func MapAfter(options ...interface{}) (handlers.Handler, error) {
 //     BeforeHandler.Before(context.Context) error
 //     AfterHandler.After(context.Context) error
 //
+// Controllers can also provide their own custom actions, such as `POST /orders/{id}/cancel`
+// or `GET /orders/search`, by returning a table of them:
+//
+//     RestfulActions.Actions() []controllers.Action
+//
+// See controllers.RestfulActions for details.
+//
+// HEAD requests are answered by the actions mapped for GET, with the body
+// discarded, unless the controller implements RestfulHead.
+//
+// Controllers that know the version of their resources can implement
+// RestfulETagger, and Goweb will handle If-None-Match and If-Match headers for
+// them.  See controllers.RestfulETagger.
+//
 // To implement any of these methods, you just need to provide a method with the
 // same name and signature.  For example, a simple RESTful controller that just
 // provides a simple GET might look like this:
@@ -163,17 +206,37 @@ func MapAfter(options ...interface{}) (handlers.Handler, error) {
 //
 // Optionally, you can pass matcherFuncs as optional additional arguments.  See
 // goweb.Map() for details on the types of arguments allowed.
+//
+// Controllers can be nested beneath the resources of other controllers; see
+// MapControllerMapping.
 func MapController(options ...interface{}) error {
 	return DefaultHttpHandler().MapController(options...)
 }
 
+// MapControllerMapping maps a controller like MapController, and returns the
+// ControllerMapping, which can be used to nest other controllers beneath a single
+// resource of this one:
+//
+//     people, _ := goweb.MapControllerMapping("people", peopleController)
+//     people.Nest("books", booksController)
+//
+// This maps paths such as `people/{personId}/books` and `people/{personId}/books/{id}`.
+// The nested controller's actions can get the IDs of the parent resources with
+// handlers.ParentIDs(ctx), and parent controllers that implement
+// controllers.RestfulExistenceChecker will be asked whether the parent exists first.
+// See handlers.ControllerMapping.Nest for more details.
+func MapControllerMapping(options ...interface{}) (*handlers.ControllerMapping, error) {
+	return DefaultHttpHandler().MapControllerMapping(options...)
+}
+
 // MapStatic maps static files from the specified systemPath to the
 // specified publicPath.
 //
 //     goweb.MapStatic("/static", "location/on/system/to/files")
 //
 // Goweb will automatically expand the above public path pattern from `/static` to
-// `/static/***` to ensure subfolders are automatcially mapped.
+// `/static/***filepath` to ensure subfolders are automatcially mapped.  The path of
+// the file is available as the "filepath" path parameter.
 //
 // Paths
 //
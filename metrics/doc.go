@@ -0,0 +1,15 @@
+// The metrics package collects per-route request metrics and exposes them in the
+// Prometheus text exposition format.
+//
+// Requests are labelled by HTTP method and by the raw path pattern of the mapping
+// that handled them (i.e. "people/{id}" rather than "people/123") to keep the number
+// of series small.  For the same reason, non-standard methods are all labelled
+// "OTHER".
+//
+// To collect metrics, add a Registry as an observer of your HttpHandler, and map
+// its ServeMetrics method to expose them:
+//
+//     registry := metrics.NewRegistry()
+//     goweb.DefaultHttpHandler().AddObserver(registry)
+//     goweb.Map("GET", "metrics", registry.ServeMetrics)
+package metrics
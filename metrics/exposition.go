@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType string = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelValueReplacer escapes label values.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteTo writes all of the metrics to the writer in the Prometheus text
+// exposition format.
+func (r *Registry) WriteTo(writer io.Writer) (int64, error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// sort the keys so the output is stable
+	keys := make([]seriesKey, 0, len(r.series))
+	for key := range r.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	var buf bytes.Buffer
+
+	requestsName := r.metricName("http_requests_total")
+	fmt.Fprintf(&buf, "# HELP %s Total number of HTTP requests.\n", requestsName)
+	fmt.Fprintf(&buf, "# TYPE %s counter\n", requestsName)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s%s %d\n", requestsName, labels(key, ""), r.series[key].requests)
+	}
+
+	errorsName := r.metricName("http_request_errors_total")
+	fmt.Fprintf(&buf, "# HELP %s Total number of HTTP requests that failed.\n", errorsName)
+	fmt.Fprintf(&buf, "# TYPE %s counter\n", errorsName)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s%s %d\n", errorsName, labels(key, ""), r.series[key].errors)
+	}
+
+	durationName := r.metricName("http_request_duration_seconds")
+	fmt.Fprintf(&buf, "# HELP %s Time taken to serve HTTP requests.\n", durationName)
+	fmt.Fprintf(&buf, "# TYPE %s histogram\n", durationName)
+	for _, key := range keys {
+		s := r.series[key]
+		for bucketIndex, upperBound := range r.Buckets {
+			fmt.Fprintf(&buf, "%s_bucket%s %d\n", durationName, labels(key, formatFloat(upperBound)), s.bucketCounts[bucketIndex])
+		}
+		fmt.Fprintf(&buf, "%s_bucket%s %d\n", durationName, labels(key, "+Inf"), s.count)
+		fmt.Fprintf(&buf, "%s_sum%s %s\n", durationName, labels(key, ""), formatFloat(s.sum))
+		fmt.Fprintf(&buf, "%s_count%s %d\n", durationName, labels(key, ""), s.count)
+	}
+
+	n, err := writer.Write(buf.Bytes())
+	return int64(n), err
+
+}
+
+// ServeMetrics writes the metrics to the response in the Prometheus text
+// exposition format.
+//
+// ServeMetrics can be mapped like any other handler func:
+//
+//     goweb.Map("GET", "metrics", registry.ServeMetrics)
+func (r *Registry) ServeMetrics(ctx context.Context) error {
+	ctx.HttpResponseWriter().Header().Set("Content-Type", ContentType)
+	ctx.HttpResponseWriter().WriteHeader(http.StatusOK)
+	_, err := r.WriteTo(ctx.HttpResponseWriter())
+	return err
+}
+
+// metricName gets the full name of the metric with the specified name.
+func (r *Registry) metricName(name string) string {
+	if len(r.Namespace) == 0 {
+		return name
+	}
+	return r.Namespace + "_" + name
+}
+
+// labels gets the label set for the specified key, including the le label
+// if one is specified.
+func labels(key seriesKey, le string) string {
+	l := fmt.Sprintf(`{method="%s",route="%s"`, labelValueReplacer.Replace(key.method), labelValueReplacer.Replace(key.route))
+	if len(le) > 0 {
+		l += fmt.Sprintf(`,le="%s"`, le)
+	}
+	return l + "}"
+}
+
+// formatFloat formats the float in the shortest accurate way.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"bytes"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+
+	r := NewRegistry()
+	r.Buckets = []float64{0.1, 1}
+	r.Observe("GET", "people/{id}", 50*time.Millisecond, false)
+	r.Observe("POST", "people", 2*time.Second, true)
+
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+
+	if assert.NoError(t, err) {
+		output := buf.String()
+		assert.Contains(t, output, "# TYPE goweb_http_requests_total counter\n")
+		assert.Contains(t, output, `goweb_http_requests_total{method="GET",route="people/{id}"} 1`)
+		assert.Contains(t, output, `goweb_http_request_errors_total{method="POST",route="people"} 1`)
+		assert.Contains(t, output, "# TYPE goweb_http_request_duration_seconds histogram\n")
+		assert.Contains(t, output, `goweb_http_request_duration_seconds_bucket{method="GET",route="people/{id}",le="0.1"} 1`)
+		assert.Contains(t, output, `goweb_http_request_duration_seconds_bucket{method="POST",route="people",le="1"} 0`)
+		assert.Contains(t, output, `goweb_http_request_duration_seconds_bucket{method="POST",route="people",le="+Inf"} 1`)
+		assert.Contains(t, output, `goweb_http_request_duration_seconds_sum{method="POST",route="people"} 2`)
+		assert.Contains(t, output, `goweb_http_request_duration_seconds_count{method="POST",route="people"} 1`)
+
+		// routes are sorted
+		assert.True(t, strings.Index(output, `route="people"`) < strings.Index(output, `route="people/{id}"`))
+	}
+
+}
+
+func TestRegistry_WriteTo_EscapesLabels(t *testing.T) {
+
+	r := NewRegistry()
+	r.Observe("GET", `say/"hello"`, time.Millisecond, false)
+
+	var buf bytes.Buffer
+	r.WriteTo(&buf)
+
+	assert.Contains(t, buf.String(), `route="say/\"hello\""`)
+
+}
+
+func TestRegistry_ServeMetrics(t *testing.T) {
+
+	r := NewRegistry()
+	r.Observe("GET", "people", time.Millisecond, false)
+
+	ctx := context_test.MakeTestContextWithPath("metrics")
+	assert.NoError(t, r.ServeMetrics(ctx))
+
+	assert.Equal(t, ContentType, context_test.TestResponseWriter.Header().Get("Content-Type"))
+	assert.Equal(t, 200, context_test.TestResponseWriter.StatusCode)
+	assert.Contains(t, context_test.TestResponseWriter.Output, `goweb_http_requests_total{method="GET",route="people"} 1`)
+
+}
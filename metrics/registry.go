@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"github.com/stretchr/goweb/context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNamespace is the default prefix for metric names.
+	DefaultNamespace string = "goweb"
+
+	// dataKeyRecorder is the data key for the statusRecorder of the current request.
+	dataKeyRecorder string = "metricsrecorder"
+)
+
+var (
+	// DefaultBuckets are the default upper bounds (in seconds) of the latency
+	// histogram buckets.
+	DefaultBuckets []float64 = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	// UnmatchedRoute is the route label used for requests that were not handled
+	// by any mapping.
+	UnmatchedRoute string = "unmatched"
+
+	// OtherMethod is the method label used for requests whose method is not one
+	// of the standard HTTP methods, so clients can't make a series for every
+	// method they care to invent.
+	OtherMethod string = "OTHER"
+)
+
+// standardMethods are the methods that are used as method labels.
+var standardMethods map[string]bool = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// seriesKey identifies a set of metrics.
+type seriesKey struct {
+	method string
+	route  string
+}
+
+// series holds the metrics for a single method and route.
+type series struct {
+	requests     uint64
+	errors       uint64
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// Registry collects request counts, error counts and latency histograms
+// for each route and HTTP method.
+//
+// Registry is a handlers.Observer, so to collect metrics for an HttpHandler
+// just add it:
+//
+//     handler.AddObserver(registry)
+type Registry struct {
+
+	// Namespace is the prefix of the metric names.  By default, "goweb".
+	Namespace string
+
+	// Buckets are the upper bounds (in seconds) of the latency histogram
+	// buckets, in increasing order.  Buckets should not be changed once
+	// requests have been observed.
+	Buckets []float64
+
+	// mutex protects the series map.
+	mutex sync.Mutex
+
+	// series holds the metrics by method and route.
+	series map[seriesKey]*series
+}
+
+// NewRegistry makes a new Registry with the default namespace and (a copy of)
+// the default buckets.
+func NewRegistry() *Registry {
+	r := new(Registry)
+	r.Namespace = DefaultNamespace
+	r.Buckets = append([]float64(nil), DefaultBuckets...)
+	r.series = make(map[seriesKey]*series)
+	return r
+}
+
+// Observe records a single request.
+//
+// Requests that failed (because a handler returned an error, or because the
+// response status was 5xx) should be marked as failed.  Methods other than the
+// standard HTTP methods are recorded as OtherMethod.
+func (r *Registry) Observe(method, route string, duration time.Duration, failed bool) {
+
+	if len(route) == 0 {
+		route = UnmatchedRoute
+	}
+	if !standardMethods[method] {
+		method = OtherMethod
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := seriesKey{method, route}
+	s, exists := r.series[key]
+	if !exists {
+		s = &series{bucketCounts: make([]uint64, len(r.Buckets))}
+		r.series[key] = s
+	}
+
+	seconds := duration.Seconds()
+
+	s.requests++
+	if failed {
+		s.errors++
+	}
+	for bucketIndex, upperBound := range r.Buckets {
+		if seconds <= upperBound {
+			s.bucketCounts[bucketIndex]++
+		}
+	}
+	s.sum += seconds
+	s.count++
+
+}
+
+// RequestStarted starts timing the request and begins recording the status
+// code of the response.
+func (r *Registry) RequestStarted(ctx context.Context) {
+	recorder := newStatusRecorder(ctx.HttpResponseWriter())
+	ctx.SetHttpResponseWriter(recorder)
+	ctx.Data().Set(dataKeyRecorder, recorder)
+}
+
+// RequestFinished records the request against the route that handled it.
+func (r *Registry) RequestFinished(ctx context.Context, err error) {
+
+	recorder, ok := ctx.Data().Get(dataKeyRecorder).Data().(*statusRecorder)
+	if !ok {
+		return
+	}
+
+	failed := err != nil || recorder.Status() >= http.StatusInternalServerError
+	route := ctx.Data().Get(context.DataKeyRoute).Str()
+
+	r.Observe(ctx.MethodString(), route, time.Since(recorder.started), failed)
+
+}
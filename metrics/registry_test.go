@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Interface(t *testing.T) {
+
+	assert.Implements(t, (*handlers.Observer)(nil), new(Registry))
+
+}
+
+func TestNewRegistry(t *testing.T) {
+
+	r := NewRegistry()
+
+	assert.Equal(t, DefaultNamespace, r.Namespace)
+	assert.Equal(t, DefaultBuckets, r.Buckets)
+
+	// the default buckets are copied, so changing them doesn't change others
+	r.Buckets[0] = 42
+	assert.NotEqual(t, 42.0, DefaultBuckets[0])
+	assert.NotEqual(t, 42.0, NewRegistry().Buckets[0])
+
+}
+
+func TestRegistry_Observe(t *testing.T) {
+
+	r := NewRegistry()
+	r.Buckets = []float64{0.1, 1}
+
+	r.Observe("GET", "people/{id}", 50*time.Millisecond, false)
+	r.Observe("GET", "people/{id}", 500*time.Millisecond, true)
+	r.Observe("GET", "", 5*time.Second, false)
+
+	s := r.series[seriesKey{"GET", "people/{id}"}]
+	if assert.NotNil(t, s) {
+		assert.Equal(t, uint64(2), s.requests)
+		assert.Equal(t, uint64(1), s.errors)
+		assert.Equal(t, []uint64{1, 2}, s.bucketCounts)
+		assert.Equal(t, uint64(2), s.count)
+	}
+
+	s = r.series[seriesKey{"GET", UnmatchedRoute}]
+	if assert.NotNil(t, s) {
+		assert.Equal(t, []uint64{0, 0}, s.bucketCounts)
+		assert.Equal(t, uint64(1), s.count)
+	}
+
+}
+
+func TestRegistry_Observe_OtherMethods(t *testing.T) {
+
+	r := NewRegistry()
+
+	r.Observe("PROPFIND", "people", time.Millisecond, false)
+	r.Observe("FOO-123", "people", time.Millisecond, false)
+	r.Observe("get", "people", time.Millisecond, false)
+	r.Observe("DELETE", "people", time.Millisecond, false)
+
+	assert.Equal(t, 2, len(r.series))
+	if s := r.series[seriesKey{OtherMethod, "people"}]; assert.NotNil(t, s) {
+		assert.Equal(t, uint64(3), s.requests)
+	}
+	assert.NotNil(t, r.series[seriesKey{"DELETE", "people"}])
+
+}
+
+func TestRegistry_ObservesHttpHandler(t *testing.T) {
+
+	codecService := codecsservices.NewWebCodecService()
+	h := handlers.NewHttpHandler(codecService)
+	r := NewRegistry()
+	h.AddObserver(r)
+
+	h.Map("GET", "people/{id}", func(c context.Context) error {
+		c.HttpResponseWriter().WriteHeader(http.StatusOK)
+		return nil
+	})
+	h.Map("GET", "broken", func(c context.Context) error {
+		return errors.New("broken")
+	})
+	h.Map("GET", "unavailable", func(c context.Context) error {
+		c.HttpResponseWriter().WriteHeader(http.StatusServiceUnavailable)
+		return nil
+	})
+
+	for _, path := range []string{"people/1", "people/2", "broken", "unavailable", "nothing-here"} {
+		request, _ := http.NewRequest("GET", "http://stretchr.org/"+path, nil)
+		h.ServeHTTP(new(http_test.TestResponseWriter), request)
+	}
+
+	if s := r.series[seriesKey{"GET", "people/{id}"}]; assert.NotNil(t, s) {
+		assert.Equal(t, uint64(2), s.requests)
+		assert.Equal(t, uint64(0), s.errors)
+	}
+	if s := r.series[seriesKey{"GET", "broken"}]; assert.NotNil(t, s) {
+		assert.Equal(t, uint64(1), s.requests)
+		assert.Equal(t, uint64(1), s.errors)
+	}
+	if s := r.series[seriesKey{"GET", "unavailable"}]; assert.NotNil(t, s) {
+		assert.Equal(t, uint64(1), s.errors)
+	}
+	if s := r.series[seriesKey{"GET", UnmatchedRoute}]; assert.NotNil(t, s) {
+		assert.Equal(t, uint64(1), s.requests)
+	}
+
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"github.com/stretchr/goweb/handlers"
+)
+
+// metadataKey is the key of Annotations in PathMatchHandler.Metadata.
+const metadataKey string = "openapi"
+
+// Annotation holds the information about a mapping that can't be worked out
+// from the mapping itself.
+type Annotation struct {
+
+	// Summary is a short summary of what the operation does.
+	Summary string
+
+	// Description is a longer explanation of the operation.
+	Description string
+
+	// OperationID is the unique name of the operation.
+	OperationID string
+
+	// Tags group the operation with others.
+	Tags []string
+
+	// Parameters are additional parameters (i.e. in the query or headers) of the
+	// operation.
+	Parameters []*Parameter
+
+	// Request is a value of the type of the request body, or nil if the operation
+	// has no body.
+	Request interface{}
+
+	// Response is a value of the type of the response data, or nil.
+	Response interface{}
+
+	// ResponseStatus is the status code of successful responses.  If zero, it is
+	// worked out from the action, or is 200.
+	ResponseStatus int
+
+	// Deprecated marks the operation as deprecated.
+	Deprecated bool
+
+	// Hidden leaves the operation out of the document.
+	Hidden bool
+}
+
+// AnnotatedController represents a controller that annotates its actions,
+// keyed by action name (i.e. controllers.ActionCreate or the name of a custom
+// action).
+type AnnotatedController interface {
+	OpenAPIAnnotations() map[string]Annotation
+}
+
+// Describe is a HandlerOption that annotates a mapping.
+func Describe(annotation Annotation) handlers.HandlerOption {
+	return func(handler *handlers.PathMatchHandler) error {
+		if handler.Metadata == nil {
+			handler.Metadata = make(map[string]interface{})
+		}
+		handler.Metadata[metadataKey] = annotation
+		return nil
+	}
+}
+
+// annotationFor gets the Annotation for the specified handler, from its Metadata
+// or its controller.
+func annotationFor(handler *handlers.PathMatchHandler) Annotation {
+
+	if annotation, ok := handler.Metadata[metadataKey].(Annotation); ok {
+		return annotation
+	}
+
+	if controller, ok := handler.Controller.(AnnotatedController); ok && len(handler.ActionName) > 0 {
+		return controller.OpenAPIAnnotations()[handler.ActionName]
+	}
+
+	return Annotation{}
+
+}
@@ -0,0 +1,27 @@
+// The openapi package generates OpenAPI 3 documents describing the handlers and
+// controllers mapped in an HttpHandler, so API documentation never drifts from
+// the code.  It can also load documents written by hand (in JSON or YAML) and
+// validate requests and responses against them; see Validator.
+//
+// Every mapping in the process pipe becomes an operation; {placeholders} become
+// path parameters, [optional] segments are expanded into two paths, and controller
+// actions get sensible summaries and responses.  Mappings with * or *** segments
+// can't be described, so they are left out.
+//
+// Annotate mappings with Describe to add summaries and request and response
+// schemas, which are reflected from Go types:
+//
+//     goweb.Map("POST", "people", createPerson, openapi.Describe(openapi.Annotation{
+//       Summary:  "Adds a person",
+//       Request:  Person{},
+//       Response: Person{},
+//     }))
+//
+// Controllers can annotate their actions by implementing AnnotatedController.
+//
+// To serve the document, map the func returned by Serve:
+//
+//     goweb.Map("GET", "openapi.json", openapi.Serve(goweb.DefaultHttpHandler(), openapi.Options{
+//       Info: openapi.Info{Title: "People API", Version: "1.0"},
+//     }))
+package openapi
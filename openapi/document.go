@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification documents conform to.
+const OpenAPIVersion string = "3.0.3"
+
+// Document represents an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+// Components holds reusable parts of the document, which schemas refer to with
+// $ref, i.e. "#/components/schemas/Person".
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server describes a server the API is available from.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem describes the operations available on a path, keyed by lowercase
+// HTTP method.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// Response describes a single response from an operation.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes the content of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema describes a data type.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+
+	// NoAdditionalProperties indicates that objects may only have the Properties,
+	// which documents write as "additionalProperties: false".
+	NoAdditionalProperties bool `json:"-"`
+}
+
+// schemaJSON is a Schema without its JSON methods.
+type schemaJSON Schema
+
+// MarshalJSON writes the schema, including "additionalProperties: false" when
+// NoAdditionalProperties is set.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+
+	if !s.NoAdditionalProperties {
+		return json.Marshal((*schemaJSON)(s))
+	}
+
+	return json.Marshal(&struct {
+		*schemaJSON
+		AdditionalProperties bool `json:"additionalProperties"`
+	}{schemaJSON: (*schemaJSON)(s)})
+
+}
+
+// UnmarshalJSON reads the schema, allowing additionalProperties to be either a
+// schema or a bool.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+
+	var raw struct {
+		*schemaJSON
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	raw.schemaJSON = (*schemaJSON)(s)
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	s.AdditionalProperties = nil
+	s.NoAdditionalProperties = false
+
+	switch strings.TrimSpace(string(raw.AdditionalProperties)) {
+	case "", "null", "true":
+	case "false":
+		s.NoAdditionalProperties = true
+	default:
+		s.AdditionalProperties = new(Schema)
+		return json.Unmarshal(raw.AdditionalProperties, s.AdditionalProperties)
+	}
+
+	return nil
+
+}
+
+// pathItemMethods are the keys of path items that hold operations.
+var pathItemMethods []string = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// UnmarshalJSON reads the operations of the path item.  Parameters described
+// for the whole path are added to each of its operations, unless the operation
+// describes them itself.  Other fields are ignored.
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var shared []*Parameter
+	if parameters, exists := raw["parameters"]; exists {
+		if err := json.Unmarshal(parameters, &shared); err != nil {
+			return err
+		}
+	}
+
+	*p = make(PathItem)
+
+	for _, method := range pathItemMethods {
+
+		operationJSON, exists := raw[method]
+		if !exists {
+			continue
+		}
+
+		operation := new(Operation)
+		if err := json.Unmarshal(operationJSON, operation); err != nil {
+			return err
+		}
+
+		for _, parameter := range shared {
+			if operation.parameter(parameter.In, parameter.Name) == nil {
+				operation.Parameters = append(operation.Parameters, parameter)
+			}
+		}
+
+		(*p)[method] = operation
+
+	}
+
+	return nil
+
+}
+
+// parameter gets the parameter of the operation with the specified location
+// and name, or nil if there isn't one.
+func (o *Operation) parameter(in, name string) *Parameter {
+	for _, parameter := range o.Parameters {
+		if parameter.In == in && parameter.Name == name {
+			return parameter
+		}
+	}
+	return nil
+}
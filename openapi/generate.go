@@ -0,0 +1,287 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	"github.com/stretchr/goweb/handlers"
+	"github.com/stretchr/goweb/http"
+	"github.com/stretchr/goweb/paths"
+	nethttp "net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of request and response bodies in documents.
+const ContentType string = "application/json"
+
+// anyMethods are the methods documented for mappings that handle any method.
+var anyMethods []string = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch}
+
+// Options control how documents are generated.
+type Options struct {
+
+	// Info describes the API.
+	Info Info
+
+	// Servers are the servers the API is available from.
+	Servers []Server
+
+	// Version, if not empty, limits the document to the handlers mapped for
+	// that version of the API (and those mapped for every version).
+	Version string
+
+	// Envelope describes responses as wrapped in the standard response object
+	// of the responders.GowebAPIResponder (with the default field names).
+	Envelope bool
+}
+
+// Generate generates the Document describing the handlers in the process pipe
+// of the specified HttpHandler.  Handlers mapped with regular expressions
+// (see handlers.Regexp) are left out, as they can't be described as paths.
+func Generate(httpHandler *handlers.HttpHandler, options Options) *Document {
+
+	document := &Document{
+		OpenAPI: OpenAPIVersion,
+		Info:    options.Info,
+		Servers: options.Servers,
+		Paths:   make(map[string]*PathItem)}
+
+	for _, handler := range pathMatchHandlers(httpHandler.HandlersPipe()) {
+
+		if len(options.Version) > 0 && len(handler.Version) > 0 && handler.Version != options.Version {
+			continue
+		}
+
+		// regular expressions can't be described as OpenAPI paths
+		if handler.PathPattern.IsRegexp() {
+			continue
+		}
+
+		annotation := annotationFor(handler)
+		if annotation.Hidden {
+			continue
+		}
+
+		methods := handler.HttpMethods
+		if len(methods) == 0 {
+			methods = anyMethods
+		}
+
+		for _, path := range expandPath(handler.PathPattern.RawPath) {
+
+			pathItem, exists := document.Paths[path]
+			if !exists {
+				pathItem = &PathItem{}
+				document.Paths[path] = pathItem
+			}
+
+			for _, method := range methods {
+
+				method = strings.ToLower(method)
+				if method == "options" {
+					continue
+				}
+
+				// the first mapping wins, as it would when handling requests
+				if _, exists := (*pathItem)[method]; !exists {
+					(*pathItem)[method] = operationFor(handler, path, annotation, options)
+				}
+
+			}
+
+		}
+
+	}
+
+	// remove paths without operations
+	for path, pathItem := range document.Paths {
+		if len(*pathItem) == 0 {
+			delete(document.Paths, path)
+		}
+	}
+
+	return document
+
+}
+
+// Serve gets a func that can be mapped to serve the Document for the specified
+// HttpHandler as JSON.
+//
+// The document is generated for each request, so it always describes the current
+// mappings.
+func Serve(httpHandler *handlers.HttpHandler, options Options) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+
+		output, marshalErr := json.MarshalIndent(Generate(httpHandler, options), "", "  ")
+		if marshalErr != nil {
+			return marshalErr
+		}
+
+		ctx.HttpResponseWriter().Header().Set("Content-Type", ContentType)
+		ctx.HttpResponseWriter().WriteHeader(nethttp.StatusOK)
+		_, writeErr := ctx.HttpResponseWriter().Write(output)
+		return writeErr
+
+	}
+}
+
+// pathMatchHandlers gets the PathMatchHandlers in the pipe, including those in
+// nested pipes.
+func pathMatchHandlers(pipe handlers.Pipe) []*handlers.PathMatchHandler {
+	var found []*handlers.PathMatchHandler
+	for _, handler := range pipe {
+		switch handler := handler.(type) {
+		case handlers.Pipe:
+			found = append(found, pathMatchHandlers(handler)...)
+		case *handlers.PathMatchHandler:
+			found = append(found, handler)
+		}
+	}
+	return found
+}
+
+// expandPath gets the OpenAPI paths for the specified raw path pattern, with
+// optional segments expanded, or nothing if the path has wildcards.
+func expandPath(rawPath string) []string {
+
+	// split the path ourselves, so file extensions are kept
+	segments := strings.Split(paths.NewPath(rawPath).RawPath, "/")
+	expanded := []string{""}
+
+	for i, segment := range segments {
+
+		switch {
+		case strings.HasPrefix(segment, "*") || strings.HasSuffix(segment, "...}"):
+			// * and *** segments, named or not
+			return nil
+		case strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]"):
+			withSegment := fmt.Sprintf("%s/{%s}", expanded[len(expanded)-1], strings.Trim(segment, "[]"))
+			if i == len(segments)-1 {
+				expanded = append(expanded, withSegment)
+			} else {
+				// optional segments can only really be last
+				return nil
+			}
+		case len(segment) > 0:
+			for j := range expanded {
+				expanded[j] = fmt.Sprintf("%s/%s", expanded[j], segment)
+			}
+		}
+
+	}
+
+	if expanded[0] == "" {
+		expanded[0] = "/"
+	}
+
+	return expanded
+
+}
+
+// operationFor makes the Operation for the handler on the specified path.
+func operationFor(handler *handlers.PathMatchHandler, path string, annotation Annotation, options Options) *Operation {
+
+	operation := &Operation{
+		Summary:     annotation.Summary,
+		Description: annotation.Description,
+		OperationID: annotation.OperationID,
+		Tags:        annotation.Tags,
+		Deprecated:  annotation.Deprecated,
+		Responses:   make(map[string]*Response)}
+
+	if len(operation.Summary) == 0 {
+		operation.Summary = handler.Description
+	}
+
+	// controller actions
+	status := annotation.ResponseStatus
+	if handler.Controller != nil && len(handler.ActionName) > 0 {
+
+		controllerName := reflect.Indirect(reflect.ValueOf(handler.Controller)).Type().Name()
+
+		if len(operation.Summary) == 0 {
+			operation.Summary = fmt.Sprintf("%s action", handler.ActionName)
+		}
+		if len(operation.OperationID) == 0 && len(controllerName) > 0 {
+			operation.OperationID = fmt.Sprintf("%s%s", controllerName, handler.ActionName)
+		}
+		if len(operation.Tags) == 0 {
+			if segments := paths.NewPath(handler.PathPattern.RawPath).Segments(); len(segments) > 0 {
+				operation.Tags = []string{segments[0]}
+			}
+		}
+
+		if status == 0 {
+			switch handler.ActionName {
+			case controllers.ActionCreate:
+				status = nethttp.StatusCreated
+			case controllers.ActionDelete, controllers.ActionDeleteMany:
+				status = nethttp.StatusNoContent
+			}
+		}
+
+	}
+
+	if status == 0 {
+		status = nethttp.StatusOK
+	}
+
+	// path parameters
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			operation.Parameters = append(operation.Parameters, &Parameter{
+				Name:     strings.Trim(segment, "{}"),
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"}})
+		}
+	}
+	operation.Parameters = append(operation.Parameters, annotation.Parameters...)
+
+	// request and response bodies
+	if annotation.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{ContentType: {Schema: SchemaFor(annotation.Request)}}}
+	}
+
+	response := &Response{Description: nethttp.StatusText(status)}
+	if annotation.Response != nil || options.Envelope {
+		var schema *Schema
+		if annotation.Response != nil {
+			schema = SchemaFor(annotation.Response)
+		}
+		if options.Envelope {
+			schema = envelope(schema)
+		}
+		if status != nethttp.StatusNoContent {
+			response.Content = map[string]*MediaType{ContentType: {Schema: schema}}
+		}
+	}
+	operation.Responses[strconv.Itoa(status)] = response
+
+	if strings.Contains(path, "{") {
+		operation.Responses["404"] = &Response{Description: nethttp.StatusText(nethttp.StatusNotFound)}
+	}
+
+	return operation
+
+}
+
+// envelope wraps the data schema in the standard response object.
+func envelope(data *Schema) *Schema {
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"s": {Type: "integer", Format: "int32", Description: "Status code"},
+			"e": {Type: "array", Items: &Schema{Type: "string"}, Description: "Errors"},
+		},
+		Required: []string{"s"}}
+	if data != nil {
+		schema.Properties["d"] = data
+	}
+	return schema
+}
@@ -0,0 +1,144 @@
+package openapi
+
+import (
+	"encoding/json"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/controllers"
+	"github.com/stretchr/goweb/handlers"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+)
+
+type PeopleController struct{}
+
+func (c *PeopleController) Create(ctx context.Context) error {
+	return nil
+}
+
+func (c *PeopleController) Read(id string, ctx context.Context) error {
+	return nil
+}
+
+func (c *PeopleController) ReadMany(ctx context.Context) error {
+	return nil
+}
+
+func (c *PeopleController) Delete(id string, ctx context.Context) error {
+	return nil
+}
+
+func (c *PeopleController) OpenAPIAnnotations() map[string]Annotation {
+	return map[string]Annotation{
+		controllers.ActionCreate: {Summary: "Adds a person", Request: testPerson{}, Response: testPerson{}},
+	}
+}
+
+func noop(ctx context.Context) error {
+	return nil
+}
+
+func TestExpandPath(t *testing.T) {
+
+	assert.Equal(t, []string{"/people"}, expandPath("people"))
+	assert.Equal(t, []string{"/people/{id}/books"}, expandPath("/people/{id}/books"))
+	assert.Equal(t, []string{"/people", "/people/{id}"}, expandPath("people/[id]"))
+	assert.Equal(t, []string{"/"}, expandPath("/"))
+	assert.Nil(t, expandPath("***"))
+	assert.Nil(t, expandPath("static/***"))
+	assert.Nil(t, expandPath("people/*/books"))
+
+}
+
+func TestGenerate(t *testing.T) {
+
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.MapController("people", new(PeopleController))
+	h.Map("GET", "status/[detail]", noop, Describe(Annotation{Summary: "Gets the status", Response: map[string]string{}}))
+	h.Map("GET", "secret", noop, Describe(Annotation{Hidden: true}))
+	h.Map("GET", "v2only", noop, handlers.Version("2"))
+	h.MapStatic("static", "/tmp")
+	h.MapBefore("people", noop)
+
+	document := Generate(h, Options{Info: Info{Title: "People", Version: "1.0"}, Version: "1"})
+
+	assert.Equal(t, OpenAPIVersion, document.OpenAPI)
+	assert.Equal(t, "People", document.Info.Title)
+
+	var pathNames []string
+	for path := range document.Paths {
+		pathNames = append(pathNames, path)
+	}
+	assert.ElementsMatch(t, []string{"/people", "/people/{id}", "/status", "/status/{detail}"}, pathNames)
+
+	// controller actions
+	people := *document.Paths["/people"]
+	assert.ElementsMatch(t, []string{"get", "post"}, keys(people))
+
+	create := people["post"]
+	assert.Equal(t, "Adds a person", create.Summary)
+	assert.Equal(t, "PeopleControllerCreate", create.OperationID)
+	assert.Equal(t, []string{"people"}, create.Tags)
+	assert.Equal(t, "object", create.RequestBody.Content[ContentType].Schema.Type)
+	assert.Equal(t, "Created", create.Responses["201"].Description)
+	assert.NotNil(t, create.Responses["201"].Content[ContentType].Schema.Properties["name"])
+
+	assert.Equal(t, "ReadMany action", people["get"].Summary)
+
+	person := *document.Paths["/people/{id}"]
+	assert.ElementsMatch(t, []string{"get", "delete"}, keys(person))
+	if assert.Equal(t, 1, len(person["get"].Parameters)) {
+		assert.Equal(t, &Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}, person["get"].Parameters[0])
+	}
+	assert.NotNil(t, person["get"].Responses["404"])
+	assert.NotNil(t, person["delete"].Responses["204"])
+
+	// annotated mappings
+	status := *document.Paths["/status/{detail}"]
+	assert.Equal(t, "Gets the status", status["get"].Summary)
+
+}
+
+func TestGenerate_Envelope(t *testing.T) {
+
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("GET", "people", noop, Describe(Annotation{Response: []testPerson{}}))
+
+	document := Generate(h, Options{Envelope: true})
+
+	schema := (*document.Paths["/people"])["get"].Responses["200"].Content[ContentType].Schema
+	assert.Equal(t, "array", schema.Properties["d"].Type)
+	assert.Equal(t, []string{"s"}, schema.Required)
+
+}
+
+func TestServe(t *testing.T) {
+
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("GET", "openapi.json", Serve(h, Options{Info: Info{Title: "People", Version: "1.0"}}))
+	h.Map("GET", "people", noop)
+
+	request, _ := http.NewRequest("GET", "http://goweb.org/openapi.json", nil)
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	assert.Equal(t, ContentType, response.Header().Get("Content-Type"))
+
+	var document Document
+	if assert.NoError(t, json.Unmarshal([]byte(response.Output), &document)) {
+		assert.Equal(t, "People", document.Info.Title)
+		assert.NotNil(t, document.Paths["/people"])
+		assert.NotNil(t, document.Paths["/openapi.json"])
+	}
+
+}
+
+func keys(pathItem PathItem) []string {
+	var methods []string
+	for method := range pathItem {
+		methods = append(methods, method)
+	}
+	return methods
+}
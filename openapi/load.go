@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+)
+
+// Load reads an OpenAPI document written in either JSON or YAML.
+//
+// An error is returned if the pattern of any schema isn't a valid regular
+// expression.
+func Load(data []byte) (*Document, error) {
+
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("openapi: Could not read YAML document: %s", err)
+		}
+
+		var err error
+		if data, err = json.Marshal(jsonCompatible(value)); err != nil {
+			return nil, fmt.Errorf("openapi: Could not read YAML document: %s", err)
+		}
+
+	}
+
+	document := new(Document)
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("openapi: Could not read document: %s", err)
+	}
+
+	if err := checkPatterns(document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+
+}
+
+// LoadFile reads an OpenAPI document from a JSON or YAML file.
+func LoadFile(filename string) (*Document, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(data)
+
+}
+
+// jsonCompatible converts maps decoded from YAML, which may have keys that
+// aren't strings (like response status codes), into maps that can be written
+// as JSON.
+func jsonCompatible(value interface{}) interface{} {
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = jsonCompatible(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range value {
+			value[i] = jsonCompatible(item)
+		}
+	}
+
+	return value
+
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testYAMLDocument string = `
+openapi: 3.0.3
+info:
+  title: People
+  version: "1.0"
+paths:
+  /people/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      responses:
+        200:
+          description: The person
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Person"
+components:
+  schemas:
+    Person:
+      type: object
+      additionalProperties: false
+      required: [name]
+      properties:
+        name:
+          type: string
+`
+
+func TestLoad_YAML(t *testing.T) {
+
+	document, err := Load([]byte(testYAMLDocument))
+
+	if assert.NoError(t, err) {
+
+		assert.Equal(t, "People", document.Info.Title)
+
+		operation := (*document.Paths["/people/{id}"])["get"]
+		if assert.NotNil(t, operation) {
+			if assert.Equal(t, 1, len(operation.Parameters), "Path parameters should be added to the operations") {
+				assert.Equal(t, "integer", operation.Parameters[0].Schema.Type)
+			}
+			assert.Equal(t, "#/components/schemas/Person", operation.Responses["200"].Content[ContentType].Schema.Ref)
+		}
+
+		person := document.Components.Schemas["Person"]
+		assert.True(t, person.NoAdditionalProperties)
+		assert.Nil(t, person.AdditionalProperties)
+
+	}
+
+}
+
+func TestLoad_JSON(t *testing.T) {
+
+	document, err := Load([]byte(`{"openapi": "3.0.3", "paths": {"/people": {"summary": "People", "get": {"responses": {"200": {"description": "OK"}}}}}}`))
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"get"}, keys(*document.Paths["/people"]))
+	}
+
+	_, err = Load([]byte(`{"openapi": `))
+	assert.Error(t, err)
+
+}
+
+func TestLoad_InvalidPattern(t *testing.T) {
+
+	_, err := Load([]byte(`{"openapi": "3.0.3", "paths": {"/people": {"get": {"parameters": [{"name": "q", "in": "query", "schema": {"type": "array", "items": {"type": "string", "pattern": "[0-9]++"}}}], "responses": {"200": {"description": "OK"}}}}}}`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `openapi: Invalid pattern "[0-9]++"`)
+	}
+
+	_, err = Load([]byte(`{"openapi": "3.0.3", "paths": {}, "components": {"schemas": {"Person": {"properties": {"name": {"type": "string", "pattern": "("}}}}}}`))
+	assert.Error(t, err)
+
+}
+
+func TestSchema_AdditionalProperties(t *testing.T) {
+
+	var schema Schema
+	if assert.NoError(t, json.Unmarshal([]byte(`{"type": "object", "additionalProperties": {"type": "string"}}`), &schema)) {
+		assert.Equal(t, "string", schema.AdditionalProperties.Type)
+		assert.False(t, schema.NoAdditionalProperties)
+	}
+
+	data, _ := json.Marshal(&Schema{Type: "object", NoAdditionalProperties: true})
+	assert.Equal(t, `{"type":"object","additionalProperties":false}`, string(data))
+
+}
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFor reflects the Schema of the type of the specified value.
+//
+// Struct fields are named after their json tags, and fields without the
+// omitempty option are required.  Values of interface types are described by
+// an empty Schema, which allows anything.
+func SchemaFor(value interface{}) *Schema {
+	if value == nil {
+		return &Schema{}
+	}
+	return schemaForType(reflect.TypeOf(value), make(map[reflect.Type]bool))
+}
+
+// schemaForType reflects the Schema of the specified type.  seen holds the
+// struct types being reflected, so recursive types don't recurse forever.
+func schemaForType(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaForType(t.Elem(), seen)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaForType(t.Elem(), seen)}
+	case reflect.Struct:
+		return schemaForStruct(t, seen)
+	}
+
+	// interfaces, funcs etc.
+	return &Schema{}
+
+}
+
+// schemaForStruct reflects the Schema of the specified struct type.
+func schemaForStruct(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+
+	schema := &Schema{Type: "object"}
+
+	if seen[t] {
+		return schema
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	schema.Properties = make(map[string]*Schema)
+
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 && !field.Anonymous {
+			// unexported
+			continue
+		}
+
+		name := field.Name
+		omitEmpty := false
+		if tag := field.Tag.Get("json"); len(tag) > 0 {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if len(parts[0]) > 0 {
+				name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				if option == "omitempty" {
+					omitEmpty = true
+				}
+			}
+		}
+
+		fieldSchema := schemaForType(field.Type, seen)
+
+		// embedded structs have their fields promoted
+		if field.Anonymous && len(field.Tag.Get("json")) == 0 && fieldSchema.Type == "object" && fieldSchema.Properties != nil {
+			for propertyName, property := range fieldSchema.Properties {
+				schema.Properties[propertyName] = property
+			}
+			schema.Required = append(schema.Required, fieldSchema.Required...)
+			continue
+		}
+
+		schema.Properties[name] = fieldSchema
+		if !omitEmpty && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+
+	}
+
+	return schema
+
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testAddress struct {
+	Street string `json:"street"`
+}
+
+type testAudit struct {
+	Created time.Time `json:"created"`
+}
+
+type testPerson struct {
+	testAudit
+	Name     string            `json:"name"`
+	Age      int               `json:"age,omitempty"`
+	Score    float64           `json:"score,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Address  *testAddress      `json:"address"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Friends  []*testPerson     `json:"friends,omitempty"`
+	Password string            `json:"-"`
+	secret   string
+}
+
+func TestSchemaFor(t *testing.T) {
+
+	assert.Equal(t, &Schema{Type: "string"}, SchemaFor(""))
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, SchemaFor(int64(1)))
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "boolean"}}, SchemaFor([]bool{}))
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, SchemaFor([]byte{}))
+	assert.Equal(t, &Schema{}, SchemaFor(nil))
+
+}
+
+func TestSchemaFor_Struct(t *testing.T) {
+
+	schema := SchemaFor(&testPerson{})
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"created", "name"}, schema.Required)
+
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, schema.Properties["created"])
+	assert.Equal(t, &Schema{Type: "integer", Format: "int32"}, schema.Properties["age"])
+	assert.Equal(t, &Schema{Type: "number", Format: "double"}, schema.Properties["score"])
+	assert.Equal(t, "object", schema.Properties["address"].Type)
+	assert.Equal(t, &Schema{Type: "string"}, schema.Properties["address"].Properties["street"])
+	assert.Equal(t, &Schema{Type: "string"}, schema.Properties["labels"].AdditionalProperties)
+
+	// recursive types stop
+	assert.Equal(t, &Schema{Type: "object"}, schema.Properties["friends"].Items)
+
+	_, hasPassword := schema.Properties["Password"]
+	assert.False(t, hasPassword)
+	_, hasSecret := schema.Properties["secret"]
+	assert.False(t, hasSecret)
+	assert.Equal(t, 8, len(schema.Properties))
+
+}
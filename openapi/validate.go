@@ -0,0 +1,468 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Locations of the values that violations are about.
+const (
+	InPath   string = "path"
+	InQuery  string = "query"
+	InHeader string = "header"
+	InBody   string = "body"
+
+	// InResponse is the location of values in the body of responses.
+	InResponse string = "response"
+)
+
+// Violation describes a way in which a request or response doesn't match the
+// document.
+type Violation struct {
+
+	// In is where the value is, i.e. InQuery.
+	In string `json:"in"`
+
+	// Name is the name of the parameter, or the location of the value in the
+	// body (i.e. "address.city"), which is empty for the body itself.
+	Name string `json:"name,omitempty"`
+
+	// Message describes what is wrong with the value.
+	Message string `json:"message"`
+}
+
+// Error gets a description of the violation.
+func (v Violation) Error() string {
+	if len(v.Name) == 0 {
+		return fmt.Sprintf("%s: %s", v.In, v.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", v.In, v.Name, v.Message)
+}
+
+// Violations is a list of Violation objects, which is also an error.
+type Violations []Violation
+
+// Error gets a description of all the violations.
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Error()
+	}
+	return fmt.Sprintf("openapi: %s", strings.Join(messages, "; "))
+}
+
+// schemaValidator checks values against the schemas in a document.
+type schemaValidator struct {
+	document *Document
+	in       string
+	patterns *patternCache
+}
+
+// compiledPattern is the result of compiling the pattern of a schema.
+type compiledPattern struct {
+	regexp *regexp.Regexp
+	err    error
+}
+
+// patternCache holds the compiled patterns of schemas, so each pattern is only
+// compiled once, however many values are checked against it.
+type patternCache struct {
+	patterns sync.Map
+}
+
+// compile gets the compiled pattern, compiling it if it hasn't been already.
+func (c *patternCache) compile(pattern string) (*regexp.Regexp, error) {
+
+	if compiled, exists := c.patterns.Load(pattern); exists {
+		return compiled.(compiledPattern).regexp, compiled.(compiledPattern).err
+	}
+
+	regex, err := regexp.Compile(pattern)
+	c.patterns.Store(pattern, compiledPattern{regexp: regex, err: err})
+	return regex, err
+
+}
+
+// walkSchemas calls visit with every schema in the document, including those
+// nested in other schemas, stopping at the first error.
+func walkSchemas(document *Document, visit func(schema *Schema) error) error {
+
+	seen := make(map[*Schema]bool)
+
+	var walk func(schema *Schema) error
+	walk = func(schema *Schema) error {
+
+		if schema == nil || seen[schema] {
+			return nil
+		}
+		seen[schema] = true
+
+		if err := visit(schema); err != nil {
+			return err
+		}
+
+		children := []*Schema{schema.Items, schema.AdditionalProperties}
+		children = append(children, schema.AllOf...)
+		children = append(children, schema.AnyOf...)
+		children = append(children, schema.OneOf...)
+		for _, property := range sortedSchemas(schema.Properties) {
+			children = append(children, property)
+		}
+
+		for _, child := range children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	}
+
+	if document.Components != nil {
+		for _, schema := range sortedSchemas(document.Components.Schemas) {
+			if err := walk(schema); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(document.Paths) {
+		if document.Paths[path] == nil {
+			continue
+		}
+		for _, method := range pathItemMethods {
+
+			operation := (*document.Paths[path])[method]
+			if operation == nil {
+				continue
+			}
+
+			var schemas []*Schema
+			for _, parameter := range operation.Parameters {
+				if parameter != nil {
+					schemas = append(schemas, parameter.Schema)
+				}
+			}
+			if operation.RequestBody != nil {
+				schemas = append(schemas, mediaTypeSchemas(operation.RequestBody.Content)...)
+			}
+			for _, status := range sortedKeys(operation.Responses) {
+				if response := operation.Responses[status]; response != nil {
+					schemas = append(schemas, mediaTypeSchemas(response.Content)...)
+				}
+			}
+
+			for _, schema := range schemas {
+				if err := walk(schema); err != nil {
+					return err
+				}
+			}
+
+		}
+	}
+
+	return nil
+
+}
+
+// sortedSchemas gets the schemas in the map, in the order of their keys.
+func sortedSchemas(schemas map[string]*Schema) []*Schema {
+	var sorted []*Schema
+	for _, key := range sortedKeys(schemas) {
+		sorted = append(sorted, schemas[key])
+	}
+	return sorted
+}
+
+// mediaTypeSchemas gets the schemas of the media types, in the order of their
+// names.
+func mediaTypeSchemas(content map[string]*MediaType) []*Schema {
+	var schemas []*Schema
+	for _, name := range sortedKeys(content) {
+		if mediaType := content[name]; mediaType != nil {
+			schemas = append(schemas, mediaType.Schema)
+		}
+	}
+	return schemas
+}
+
+// sortedKeys gets the keys of the map, in order.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkPatterns checks that the patterns of all the schemas in the document
+// are valid regular expressions.
+func checkPatterns(document *Document) error {
+	return walkSchemas(document, func(schema *Schema) error {
+		if len(schema.Pattern) == 0 {
+			return nil
+		}
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			return fmt.Errorf("openapi: Invalid pattern %q: %s", schema.Pattern, err)
+		}
+		return nil
+	})
+}
+
+// resolve follows the $ref of the schema, if it has one.
+func (v *schemaValidator) resolve(schema *Schema) (*Schema, error) {
+
+	for seen := 0; len(schema.Ref) > 0; seen++ {
+
+		const prefix = "#/components/schemas/"
+		if seen > 32 || !strings.HasPrefix(schema.Ref, prefix) {
+			return nil, fmt.Errorf("cannot resolve $ref %q", schema.Ref)
+		}
+
+		var found *Schema
+		if v.document.Components != nil {
+			found = v.document.Components.Schemas[strings.TrimPrefix(schema.Ref, prefix)]
+		}
+		if found == nil {
+			return nil, fmt.Errorf("cannot resolve $ref %q", schema.Ref)
+		}
+		schema = found
+
+	}
+
+	return schema, nil
+
+}
+
+// validate checks the value, as decoded from JSON, against the schema.
+func (v *schemaValidator) validate(schema *Schema, value interface{}, name string) Violations {
+
+	if schema == nil {
+		return nil
+	}
+
+	schema, err := v.resolve(schema)
+	if err != nil {
+		return v.violation(name, err.Error())
+	}
+
+	var violations Violations
+
+	for _, all := range schema.AllOf {
+		violations = append(violations, v.validate(all, value, name)...)
+	}
+	if len(schema.AnyOf) > 0 && v.matching(schema.AnyOf, value) == 0 {
+		violations = append(violations, v.violation(name, "does not match any of the allowed schemas")...)
+	}
+	if len(schema.OneOf) > 0 && v.matching(schema.OneOf, value) != 1 {
+		violations = append(violations, v.violation(name, "must match exactly one of the allowed schemas")...)
+	}
+
+	if value == nil {
+		if !schema.Nullable && len(schema.Type) > 0 {
+			violations = append(violations, v.violation(name, "must not be null")...)
+		}
+		return violations
+	}
+
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must be one of %v", schema.Enum))...)
+	}
+
+	switch schema.Type {
+	case "":
+	case "string":
+		if text, ok := value.(string); ok {
+			violations = append(violations, v.validateString(schema, text, name)...)
+		} else {
+			violations = append(violations, v.violation(name, "must be a string")...)
+		}
+	case "integer", "number":
+		if number, ok := value.(float64); ok && (schema.Type == "number" || number == math.Trunc(number)) {
+			violations = append(violations, v.validateNumber(schema, number, name)...)
+		} else {
+			violations = append(violations, v.violation(name, fmt.Sprintf("must be %s %s", article(schema.Type), schema.Type))...)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			violations = append(violations, v.violation(name, "must be a boolean")...)
+		}
+	case "array":
+		if items, ok := value.([]interface{}); ok {
+			violations = append(violations, v.validateArray(schema, items, name)...)
+		} else {
+			violations = append(violations, v.violation(name, "must be an array")...)
+		}
+	case "object":
+		if object, ok := value.(map[string]interface{}); ok {
+			violations = append(violations, v.validateObject(schema, object, name)...)
+		} else {
+			violations = append(violations, v.violation(name, "must be an object")...)
+		}
+	}
+
+	return violations
+
+}
+
+// validateString checks the length and pattern of a string.
+func (v *schemaValidator) validateString(schema *Schema, text string, name string) Violations {
+
+	var violations Violations
+	length := utf8.RuneCountInString(text)
+
+	if schema.MinLength != nil && length < *schema.MinLength {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must be at least %d characters long", *schema.MinLength))...)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must be at most %d characters long", *schema.MaxLength))...)
+	}
+	if len(schema.Pattern) > 0 {
+		if pattern, err := v.patterns.compile(schema.Pattern); err != nil {
+			violations = append(violations, v.violation(name, fmt.Sprintf("has an invalid pattern: %s", err))...)
+		} else if !pattern.MatchString(text) {
+			violations = append(violations, v.violation(name, fmt.Sprintf("must match %s", schema.Pattern))...)
+		}
+	}
+
+	return violations
+
+}
+
+// validateNumber checks the range of a number.
+func (v *schemaValidator) validateNumber(schema *Schema, number float64, name string) Violations {
+
+	var violations Violations
+
+	if schema.Minimum != nil && number < *schema.Minimum {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must be at least %v", *schema.Minimum))...)
+	}
+	if schema.Maximum != nil && number > *schema.Maximum {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must be at most %v", *schema.Maximum))...)
+	}
+
+	return violations
+
+}
+
+// validateArray checks the length and items of an array.
+func (v *schemaValidator) validateArray(schema *Schema, items []interface{}, name string) Violations {
+
+	var violations Violations
+
+	if schema.MinItems != nil && len(items) < *schema.MinItems {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must have at least %d items", *schema.MinItems))...)
+	}
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		violations = append(violations, v.violation(name, fmt.Sprintf("must have at most %d items", *schema.MaxItems))...)
+	}
+
+	for i, item := range items {
+		violations = append(violations, v.validate(schema.Items, item, fmt.Sprintf("%s[%d]", name, i))...)
+	}
+
+	return violations
+
+}
+
+// validateObject checks the properties of an object.
+func (v *schemaValidator) validateObject(schema *Schema, object map[string]interface{}, name string) Violations {
+
+	var violations Violations
+
+	for _, required := range schema.Required {
+		if _, exists := object[required]; !exists {
+			violations = append(violations, v.violation(propertyName(name, required), "is required")...)
+		}
+	}
+
+	// check the properties in order, so the violations are always the same
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if property, exists := schema.Properties[key]; exists {
+			violations = append(violations, v.validate(property, object[key], propertyName(name, key))...)
+		} else if schema.NoAdditionalProperties {
+			violations = append(violations, v.violation(propertyName(name, key), "is not allowed")...)
+		} else if schema.AdditionalProperties != nil {
+			violations = append(violations, v.validate(schema.AdditionalProperties, object[key], propertyName(name, key))...)
+		}
+	}
+
+	return violations
+
+}
+
+// matching counts how many of the schemas the value matches.
+func (v *schemaValidator) matching(schemas []*Schema, value interface{}) int {
+	count := 0
+	for _, schema := range schemas {
+		if len(v.validate(schema, value, "")) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// violation makes a list containing a single Violation.
+func (v *schemaValidator) violation(name, message string) Violations {
+	return Violations{{In: v.in, Name: name, Message: message}}
+}
+
+// propertyName gets the name of a property of the object with the specified name.
+func propertyName(object, property string) string {
+	if len(object) == 0 {
+		return property
+	}
+	return object + "." + property
+}
+
+// article gets the indefinite article for the type name.
+func article(typeName string) string {
+	if strings.IndexAny(typeName[:1], "aeiou") == 0 {
+		return "an"
+	}
+	return "a"
+}
+
+// containsValue gets whether the values contain the specified value.  Numbers
+// are compared by value, whatever their type.
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(jsonValue(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonValue gets the value as it would be decoded from JSON, i.e. with numbers
+// as float64 and structs as maps.
+func jsonValue(value interface{}) interface{} {
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return value
+	}
+
+	return decoded
+
+}
@@ -0,0 +1,418 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// validatorRoute is a path of the document that requests may be for.
+type validatorRoute struct {
+	segments []string
+	pathItem *PathItem
+	dynamic  int
+}
+
+// Validator is a pre handler that checks requests against a Document before they
+// are handled, rejecting those that don't match with a 400 Bad Request response
+// listing the violations.
+//
+// The path parameters, query parameters and headers described by the operation
+// are checked, as is the body if it is JSON.  Requests for paths or methods the
+// document doesn't describe are left alone.
+//
+//     document, err := openapi.LoadFile("api.yaml")
+//     ...
+//     openapi.NewValidator(document).Install(goweb.DefaultHttpHandler())
+//
+// In tests, the Validator can also check the responses written by the
+// responders.GowebAPIResponder, so they never drift from the document:
+//
+//     goweb.API.(*responders.GowebAPIResponder).ResponseValidator = validator.ValidateResponse
+type Validator struct {
+
+	// Document describes the requests and responses.
+	Document *Document
+
+	// Reject responds to requests that have violations.  By default, a 400 Bad
+	// Request response is written with a JSON object containing the violations.
+	Reject func(ctx context.Context, violations Violations) error
+
+	// routes are the paths of the document, most specific first.
+	routes []*validatorRoute
+
+	// patterns are the compiled patterns of the schemas.
+	patterns patternCache
+}
+
+// NewValidator makes a new Validator that checks requests against the specified
+// Document.  Changes made to the paths of the document after the Validator is
+// made are ignored.
+//
+// The patterns of the schemas are compiled when the Validator is made.  Load
+// reports invalid patterns, but documents made in code aren't checked, so
+// values checked against an invalid pattern get a violation saying so.
+func NewValidator(document *Document) *Validator {
+
+	validator := &Validator{Document: document, Reject: RejectWithViolations}
+
+	walkSchemas(document, func(schema *Schema) error {
+		if len(schema.Pattern) > 0 {
+			validator.patterns.compile(schema.Pattern)
+		}
+		return nil
+	})
+
+	for path, pathItem := range document.Paths {
+		route := &validatorRoute{segments: splitPath(path), pathItem: pathItem}
+		for _, segment := range route.segments {
+			if isParameterSegment(segment) {
+				route.dynamic++
+			}
+		}
+		validator.routes = append(validator.routes, route)
+	}
+
+	// paths without parameters win, i.e. /people/me before /people/{id}
+	sort.SliceStable(validator.routes, func(i, j int) bool {
+		if validator.routes[i].dynamic != validator.routes[j].dynamic {
+			return validator.routes[i].dynamic < validator.routes[j].dynamic
+		}
+		return strings.Join(validator.routes[i].segments, "/") < strings.Join(validator.routes[j].segments, "/")
+	})
+
+	return validator
+
+}
+
+// RejectWithViolations writes a 400 Bad Request response with a JSON object
+// containing the violations.
+func RejectWithViolations(ctx context.Context, violations Violations) error {
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message":    "The request does not match the API description",
+		"violations": violations})
+	if err != nil {
+		return err
+	}
+
+	ctx.HttpResponseWriter().Header().Set("Content-Type", ContentType)
+	ctx.HttpResponseWriter().WriteHeader(http.StatusBadRequest)
+	_, err = ctx.HttpResponseWriter().Write(body)
+	return err
+
+}
+
+// Install adds the Validator to the end of the pre handlers of the specified
+// HttpHandler.
+func (v *Validator) Install(httpHandler *handlers.HttpHandler) {
+	httpHandler.AppendPreHandler(v)
+}
+
+// WillHandle always returns true, as the Validator decides in Handle whether
+// the request is described by the document.
+func (v *Validator) WillHandle(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// Handle checks the request, rejecting it if it doesn't match the document.
+func (v *Validator) Handle(ctx context.Context) (bool, error) {
+
+	violations, err := v.ValidateRequest(ctx)
+	if err != nil || len(violations) == 0 {
+		return false, err
+	}
+
+	handlers.Abort(ctx)
+	return false, v.Reject(ctx, violations)
+
+}
+
+// ValidateRequest checks the request in the specified context, getting the
+// ways in which it doesn't match the document.
+func (v *Validator) ValidateRequest(ctx context.Context) (Violations, error) {
+
+	request := ctx.HttpRequest()
+
+	operation, pathParameters := v.operationFor(request)
+	if operation == nil {
+		return nil, nil
+	}
+
+	var violations Violations
+
+	for _, parameter := range operation.Parameters {
+
+		var values []string
+		switch parameter.In {
+		case InPath:
+			if value, exists := pathParameters[parameter.Name]; exists {
+				values = []string{value}
+			}
+		case InQuery:
+			values = request.URL.Query()[parameter.Name]
+		case InHeader:
+			values = request.Header[http.CanonicalHeaderKey(parameter.Name)]
+		default:
+			continue
+		}
+
+		violations = append(violations, v.validateParameter(parameter, values)...)
+
+	}
+
+	if operation.RequestBody != nil {
+
+		body, err := ctx.RequestBody()
+		if err != nil {
+			return nil, err
+		}
+
+		violations = append(violations, v.validateBody(operation.RequestBody, request.Header.Get("Content-Type"), body)...)
+
+	}
+
+	return violations, nil
+
+}
+
+// ValidateResponse checks a response object that is about to be written for
+// the request in the specified context, returning Violations if it doesn't
+// match the document.
+//
+// Its signature matches the responders.GowebAPIResponder.ResponseValidator field.
+func (v *Validator) ValidateResponse(ctx context.Context, status int, responseObject interface{}) error {
+
+	operation, _ := v.operationFor(ctx.HttpRequest())
+	if operation == nil {
+		return nil
+	}
+
+	validator := &schemaValidator{document: v.Document, in: InResponse, patterns: &v.patterns}
+
+	response := responseFor(operation, status)
+	if response == nil {
+		return validator.violation("", fmt.Sprintf("status %d is not described", status))
+	}
+
+	mediaType, exists := response.Content[ContentType]
+	if !exists || mediaType == nil {
+		return nil
+	}
+
+	if violations := validator.validate(mediaType.Schema, jsonValue(responseObject), ""); len(violations) > 0 {
+		return violations
+	}
+
+	return nil
+
+}
+
+// operationFor gets the operation the request is for, and the values of the
+// path parameters, or nil if the document doesn't describe it.
+func (v *Validator) operationFor(request *http.Request) (*Operation, map[string]string) {
+
+	method := strings.ToLower(request.Method)
+	requestSegments := splitPath(request.URL.Path)
+
+	// goweb ignores the file extension, so try without it first
+	withoutExtension := append([]string(nil), requestSegments...)
+	if last := len(withoutExtension) - 1; last >= 0 {
+		if dot := strings.LastIndex(withoutExtension[last], "."); dot > 0 {
+			withoutExtension[last] = withoutExtension[last][:dot]
+		}
+	}
+
+	for _, segments := range [][]string{withoutExtension, requestSegments} {
+		for _, route := range v.routes {
+
+			pathParameters, matches := route.match(segments)
+			if !matches {
+				continue
+			}
+
+			operation := (*route.pathItem)[method]
+			if operation == nil && method == "head" {
+				operation = (*route.pathItem)["get"]
+			}
+
+			if operation != nil {
+				return operation, pathParameters
+			}
+
+		}
+	}
+
+	return nil, nil
+
+}
+
+// match gets whether the route matches the path segments, and the values of
+// its parameters if it does.
+func (r *validatorRoute) match(segments []string) (map[string]string, bool) {
+
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	pathParameters := make(map[string]string)
+	for i, segment := range r.segments {
+		if isParameterSegment(segment) {
+			pathParameters[segment[1:len(segment)-1]] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return pathParameters, true
+
+}
+
+// validateParameter checks the values of a parameter against its schema.
+func (v *Validator) validateParameter(parameter *Parameter, values []string) Violations {
+
+	validator := &schemaValidator{document: v.Document, in: parameter.In, patterns: &v.patterns}
+
+	if len(values) == 0 {
+		if parameter.Required || parameter.In == InPath {
+			return validator.violation(parameter.Name, "is required")
+		}
+		return nil
+	}
+
+	if parameter.Schema == nil {
+		return nil
+	}
+
+	schema, err := validator.resolve(parameter.Schema)
+	if err != nil {
+		return validator.violation(parameter.Name, err.Error())
+	}
+
+	var value interface{}
+	if schema.Type == "array" {
+
+		// allow both ?id=1&id=2 and ?id=1,2
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+
+		items := make([]interface{}, len(values))
+		for i, item := range values {
+			items[i] = parameterValue(validator, schema.Items, item)
+		}
+		value = items
+
+	} else {
+		value = parameterValue(validator, schema, values[0])
+	}
+
+	return validator.validate(schema, value, parameter.Name)
+
+}
+
+// validateBody checks the body of a request.
+func (v *Validator) validateBody(requestBody *RequestBody, contentType string, body []byte) Violations {
+
+	validator := &schemaValidator{document: v.Document, in: InBody, patterns: &v.patterns}
+
+	if len(body) == 0 {
+		if requestBody.Required {
+			return validator.violation("", "is required")
+		}
+		return nil
+	}
+
+	if len(requestBody.Content) == 0 {
+		return nil
+	}
+
+	mediaType := ContentType
+	if len(contentType) > 0 {
+		mediaType, _, _ = mime.ParseMediaType(contentType)
+	}
+
+	content, exists := requestBody.Content[mediaType]
+	if !exists {
+		if content, exists = requestBody.Content["*/*"]; !exists {
+			return validator.violation("", fmt.Sprintf("content type %q is not supported", mediaType))
+		}
+	}
+
+	if content == nil || content.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return validator.violation("", fmt.Sprintf("is not valid JSON: %s", err))
+	}
+
+	return validator.validate(content.Schema, value, "")
+
+}
+
+// responseFor gets the response the operation describes for the status code,
+// trying the exact status, then its range (i.e. "4XX"), then the default.
+func responseFor(operation *Operation, status int) *Response {
+	for _, key := range []string{strconv.Itoa(status), fmt.Sprintf("%dXX", status/100), "default"} {
+		if response, exists := operation.Responses[key]; exists {
+			return response
+		}
+	}
+	return nil
+}
+
+// parameterValue converts the text of a parameter into the type its schema
+// describes.  Text that can't be converted is left as it is, so it will be
+// reported as the wrong type.
+func parameterValue(validator *schemaValidator, schema *Schema, text string) interface{} {
+
+	if schema == nil {
+		return text
+	}
+
+	schema, err := validator.resolve(schema)
+	if err != nil {
+		return text
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(text); err == nil {
+			return boolean
+		}
+	}
+
+	return text
+
+}
+
+// splitPath splits the path into its segments.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if len(path) == 0 {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// isParameterSegment gets whether the segment of a document path is a {parameter}.
+func isParameterSegment(segment string) bool {
+	return len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// isJSON gets whether the media type is JSON.
+func isJSON(mediaType string) bool {
+	return mediaType == ContentType || strings.HasSuffix(mediaType, "+json")
+}
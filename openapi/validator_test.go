@@ -0,0 +1,265 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"github.com/stretchr/goweb/responders"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testValidatorDocument string = `
+openapi: 3.0.3
+info:
+  title: People
+  version: "1.0"
+paths:
+  /people:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+        - name: X-Tenant
+          in: header
+          required: true
+      responses:
+        200:
+          description: The people
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Person"
+      responses:
+        201:
+          description: The new person
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Person"
+  /people/me:
+    get:
+      responses:
+        200:
+          description: The current person
+  /people/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      responses:
+        200:
+          description: The person
+components:
+  schemas:
+    Person:
+      type: object
+      additionalProperties: false
+      required: [name]
+      properties:
+        name:
+          type: string
+          minLength: 1
+        tags:
+          type: array
+          items:
+            type: string
+`
+
+func makeTestValidator(t *testing.T) *Validator {
+	document, err := Load([]byte(testValidatorDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewValidator(document)
+}
+
+func serveValidated(validator *Validator, method, url, body string, header map[string]string) (*http_test.TestResponseWriter, bool) {
+
+	called := false
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	validator.Install(h)
+	h.Map(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	request, _ := http.NewRequest(method, "http://goweb.org/"+url, strings.NewReader(body))
+	for name, value := range header {
+		request.Header.Set(name, value)
+	}
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	return response, called
+
+}
+
+func violationsIn(t *testing.T, response *http_test.TestResponseWriter) Violations {
+
+	var body struct {
+		Violations Violations `json:"violations"`
+	}
+	if !assert.NoError(t, json.Unmarshal([]byte(response.Output), &body)) {
+		return nil
+	}
+	return body.Violations
+
+}
+
+func TestValidator_ValidRequests(t *testing.T) {
+
+	validator := makeTestValidator(t)
+
+	_, called := serveValidated(validator, "GET", "people?limit=10", "", map[string]string{"X-Tenant": "acme"})
+	assert.True(t, called)
+
+	_, called = serveValidated(validator, "POST", "people", `{"name": "Mat", "tags": ["admin"]}`, map[string]string{"Content-Type": "application/json; charset=utf-8"})
+	assert.True(t, called)
+
+	_, called = serveValidated(validator, "GET", "people/me", "", nil)
+	assert.True(t, called, "Paths without parameters should be matched first")
+
+	_, called = serveValidated(validator, "GET", "people/123.json", "", nil)
+	assert.True(t, called, "File extensions should be ignored")
+
+	_, called = serveValidated(validator, "GET", "books", "", nil)
+	assert.True(t, called, "Requests the document doesn't describe should be left alone")
+
+}
+
+func TestValidator_Parameters(t *testing.T) {
+
+	validator := makeTestValidator(t)
+
+	response, called := serveValidated(validator, "GET", "people?limit=1000", "", nil)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, ContentType, response.Header().Get("Content-Type"))
+	assert.Equal(t, Violations{
+		{In: InQuery, Name: "limit", Message: "must be at most 100"},
+		{In: InHeader, Name: "X-Tenant", Message: "is required"},
+	}, violationsIn(t, response))
+
+	response, called = serveValidated(validator, "GET", "people/abc", "", nil)
+
+	assert.False(t, called)
+	assert.Equal(t, Violations{{In: InPath, Name: "id", Message: "must be an integer"}}, violationsIn(t, response))
+
+}
+
+func TestValidator_Body(t *testing.T) {
+
+	validator := makeTestValidator(t)
+
+	response, called := serveValidated(validator, "POST", "people", `{"name": "", "tags": [1], "age": 30}`, nil)
+
+	assert.False(t, called)
+	assert.Equal(t, Violations{
+		{In: InBody, Name: "age", Message: "is not allowed"},
+		{In: InBody, Name: "name", Message: "must be at least 1 characters long"},
+		{In: InBody, Name: "tags[0]", Message: "must be a string"},
+	}, violationsIn(t, response))
+
+	response, _ = serveValidated(validator, "POST", "people", "", nil)
+	assert.Equal(t, Violations{{In: InBody, Message: "is required"}}, violationsIn(t, response))
+
+	response, _ = serveValidated(validator, "POST", "people", "{", nil)
+	if violations := violationsIn(t, response); assert.Equal(t, 1, len(violations)) {
+		assert.Contains(t, violations[0].Message, "is not valid JSON")
+	}
+
+	response, _ = serveValidated(validator, "POST", "people", "name=Mat", map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+	assert.Equal(t, Violations{{In: InBody, Message: `content type "application/x-www-form-urlencoded" is not supported`}}, violationsIn(t, response))
+
+}
+
+func TestValidator_Reject(t *testing.T) {
+
+	validator := makeTestValidator(t)
+	validator.Reject = func(ctx context.Context, violations Violations) error {
+		return errors.New(violations.Error())
+	}
+
+	response, called := serveValidated(validator, "GET", "people/abc", "", nil)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	assert.Contains(t, response.Output, "openapi: path id: must be an integer")
+
+}
+
+func TestValidator_ValidateResponse(t *testing.T) {
+
+	validator := makeTestValidator(t)
+
+	api := responders.NewGowebAPIResponder(codecsservices.NewWebCodecService(), new(responders.GowebHTTPResponder))
+	api.AlwaysEnvelopResponse = false
+	api.ResponseValidator = validator.ValidateResponse
+
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.Map("POST", "people", func(ctx context.Context) error {
+		return api.WriteResponseObject(ctx, http.StatusCreated, map[string]interface{}{"name": 123})
+	})
+	h.Map("GET", "people/me", func(ctx context.Context) error {
+		return api.WriteResponseObject(ctx, http.StatusNotFound, nil)
+	})
+
+	request, _ := http.NewRequest("POST", "http://goweb.org/people", strings.NewReader(`{"name": "Mat"}`))
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	assert.Contains(t, response.Output, "openapi: response name: must be a string")
+
+	request, _ = http.NewRequest("GET", "http://goweb.org/people/me", nil)
+	response = new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	assert.Contains(t, response.Output, "openapi: response: status 404 is not described")
+
+}
+
+func TestValidator_Patterns(t *testing.T) {
+
+	document := &Document{
+		Paths: map[string]*PathItem{
+			"/people": {"get": &Operation{Parameters: []*Parameter{
+				{Name: "code", In: InQuery, Schema: &Schema{Type: "string", Pattern: `^[A-Z]{3}$`}},
+				{Name: "name", In: InQuery, Schema: &Schema{Type: "string", Pattern: `(`}},
+			}}},
+		},
+	}
+	validator := NewValidator(document)
+
+	compiled, exists := validator.patterns.patterns.Load(`^[A-Z]{3}$`)
+	if assert.True(t, exists, "Patterns are compiled when the Validator is made") {
+		assert.NoError(t, compiled.(compiledPattern).err)
+	}
+
+	_, called := serveValidated(validator, "GET", "people?code=ABC", "", nil)
+	assert.True(t, called)
+
+	response, called := serveValidated(validator, "GET", "people?code=abc&name=Mat", "", nil)
+	assert.False(t, called)
+	if violations := violationsIn(t, response); assert.Equal(t, 2, len(violations)) {
+		assert.Equal(t, Violation{In: InQuery, Name: "code", Message: "must match ^[A-Z]{3}$"}, violations[0])
+		assert.Contains(t, violations[1].Message, "has an invalid pattern")
+	}
+
+}
@@ -13,6 +13,7 @@ const segmentOptionalDynamicPrefix string = "["
 const segmentOptionalDynamicSuffix string = "]"
 const segmentWildcard string = "*"
 const segmentCatchAll string = "***"
+const segmentNamedCatchAllSuffix string = "...}"
 
 /*
   Public
@@ -0,0 +1,132 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/objx"
+	"net"
+	"strings"
+)
+
+// HostSeparator separates the labels of host names.
+const HostSeparator string = "."
+
+// HostPattern represents a host name that can contain special matching labels.
+//
+// Valid patterns include:
+//
+//     api.example.com        - matches only api.example.com
+//     {tenant}.example.com   - matches any subdomain, capturing it as the tenant parameter
+//     *.example.com          - matches any subdomain
+//
+// Host names are matched case insensitively, and any port is ignored.
+type HostPattern struct {
+
+	// RawHost is the raw host pattern.
+	RawHost string
+
+	labels []string
+}
+
+// NewHostPattern makes a new HostPattern from the specified pattern.
+func NewHostPattern(host string) (*HostPattern, error) {
+
+	host = strings.ToLower(strings.TrimSpace(host))
+	if len(host) == 0 {
+		return nil, errors.New("paths: Host pattern cannot be empty.")
+	}
+
+	labels := strings.Split(stripPort(host), HostSeparator)
+	for _, label := range labels {
+		if len(label) == 0 {
+			return nil, fmt.Errorf("paths: Host pattern \"%s\" has an empty label.", host)
+		}
+		if labelType := getSegmentType(label); labelType == segmentTypeDynamicOptional || labelType == segmentTypeCatchall {
+			return nil, fmt.Errorf("paths: Host pattern \"%s\" can only contain literal, {dynamic} and * labels.", host)
+		}
+	}
+
+	return &HostPattern{RawHost: host, labels: labels}, nil
+
+}
+
+func (p *HostPattern) String() string {
+	return fmt.Sprintf("{HostPattern:\"%s\"}", p.RawHost)
+}
+
+// Match gets whether the specified host (which may include a port) matches the
+// pattern, and the values of the {dynamic} labels if it does.
+func (p *HostPattern) Match(host string) (objx.Map, bool) {
+
+	labels := strings.Split(strings.ToLower(stripPort(host)), HostSeparator)
+	if len(labels) != len(p.labels) {
+		return nil, false
+	}
+
+	parameters := make(objx.Map)
+
+	for index, label := range p.labels {
+		switch getSegmentType(label) {
+		case segmentTypeDynamic:
+			parameters[cleanSegmentName(label)] = labels[index]
+		case segmentTypeWildcard:
+		default:
+			if label != labels[index] {
+				return nil, false
+			}
+		}
+	}
+
+	return parameters, true
+
+}
+
+// Covers gets whether every host matched by the other HostPattern is also
+// matched by this one, comparing them label by label.
+func (p *HostPattern) Covers(other *HostPattern) bool {
+
+	if len(p.labels) != len(other.labels) {
+		return false
+	}
+
+	for index, label := range p.labels {
+		if getSegmentType(label) != segmentTypeLiteral {
+			continue
+		}
+		if label != other.labels[index] {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// Overlaps gets whether there are hosts that both HostPatterns match.
+func (p *HostPattern) Overlaps(other *HostPattern) bool {
+
+	if len(p.labels) != len(other.labels) {
+		return false
+	}
+
+	for index, label := range p.labels {
+		otherLabel := other.labels[index]
+		if getSegmentType(label) != segmentTypeLiteral || getSegmentType(otherLabel) != segmentTypeLiteral {
+			continue
+		}
+		if label != otherLabel {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// stripPort removes any port from the host.
+func stripPort(host string) string {
+	if withoutPort, _, err := net.SplitHostPort(host); err == nil {
+		return withoutPort
+	}
+	return host
+}
@@ -0,0 +1,68 @@
+package paths
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewHostPattern(t *testing.T) {
+
+	pattern, err := NewHostPattern("{Tenant}.Example.com")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{tenant}.example.com", pattern.RawHost)
+		assert.Equal(t, "{HostPattern:\"{tenant}.example.com\"}", pattern.String())
+	}
+
+	for _, invalid := range []string{"", "api..example.com", "[tenant].example.com", "***.example.com"} {
+		_, err := NewHostPattern(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+}
+
+func TestHostPattern_Match(t *testing.T) {
+
+	pattern, _ := NewHostPattern("{tenant}.*.example.com")
+
+	parameters, matches := pattern.Match("ACME.eu.example.com:8080")
+	if assert.True(t, matches) {
+		assert.Equal(t, "acme", parameters.Get("tenant").Str())
+	}
+
+	_, matches = pattern.Match("acme.example.com")
+	assert.False(t, matches)
+
+	_, matches = pattern.Match("acme.eu.example.org")
+	assert.False(t, matches)
+
+	literal, _ := NewHostPattern("api.example.com")
+	_, matches = literal.Match("api.example.com")
+	assert.True(t, matches)
+
+}
+
+func TestHostPattern_Covers(t *testing.T) {
+
+	tenant, _ := NewHostPattern("{tenant}.example.com")
+	wildcard, _ := NewHostPattern("*.example.com")
+	api, _ := NewHostPattern("api.example.com")
+	apiAnywhere, _ := NewHostPattern("api.*.com")
+	other, _ := NewHostPattern("api.example.org")
+	short, _ := NewHostPattern("example.com")
+
+	assert.True(t, tenant.Covers(api))
+	assert.True(t, tenant.Covers(wildcard))
+	assert.True(t, api.Covers(api))
+	assert.False(t, api.Covers(tenant))
+	assert.False(t, tenant.Covers(apiAnywhere))
+	assert.False(t, tenant.Covers(other))
+	assert.False(t, tenant.Covers(short))
+
+	assert.True(t, tenant.Overlaps(api))
+	assert.True(t, api.Overlaps(tenant))
+	assert.True(t, tenant.Overlaps(apiAnywhere))
+	assert.False(t, api.Overlaps(other))
+	assert.False(t, tenant.Overlaps(other))
+	assert.False(t, tenant.Overlaps(short))
+
+}
@@ -0,0 +1,164 @@
+package paths
+
+// patternShape is one of the forms of path a PathPattern matches, with any
+// optional segment either present or not.
+type patternShape struct {
+
+	// segments are the literal segments, or an empty string for segments that
+	// match anything.
+	segments []string
+
+	// open is whether any number of extra segments may follow.
+	open bool
+
+	// caseSensitive is whether the literal segments only match segments with
+	// the same case.
+	caseSensitive bool
+}
+
+// shapes gets the forms of path the PathPattern matches, or false if the
+// pattern is too complicated to describe (i.e. it has a catch-all before its
+// last segment, or is a regular expression).
+func (p *PathPattern) shapes() ([]patternShape, bool) {
+
+	if p.regexp != nil {
+		return nil, false
+	}
+
+	segments := withoutRoot(p.path.Segments())
+
+	shape := patternShape{caseSensitive: p.CaseSensitive}
+	var shapes []patternShape
+
+	for index, segment := range segments {
+
+		last := index == len(segments)-1
+
+		switch getSegmentType(segment) {
+		case segmentTypeLiteral:
+			shape.segments = append(shape.segments, segment)
+		case segmentTypeCatchall:
+			if !last {
+				// catch-alls anywhere else can match any number of segments
+				return nil, false
+			}
+			shape.open = true
+		case segmentTypeDynamicOptional:
+			if last {
+				shapes = append(shapes, patternShape{segments: append([]string(nil), shape.segments...), caseSensitive: shape.caseSensitive})
+			}
+			shape.segments = append(shape.segments, "")
+		default:
+			shape.segments = append(shape.segments, "")
+		}
+
+	}
+
+	return append(shapes, shape), true
+
+}
+
+// covers gets whether every path of the other shape is a path of this one.
+func (s patternShape) covers(other patternShape) bool {
+
+	if other.open && !s.open {
+		return false
+	}
+	if len(other.segments) < len(s.segments) || (!s.open && len(other.segments) != len(s.segments)) {
+		return false
+	}
+
+	for index, segment := range s.segments {
+		if len(segment) == 0 {
+			continue
+		}
+		// case sensitive segments don't cover case insensitive ones
+		if s.caseSensitive && !other.caseSensitive {
+			return false
+		}
+		if !literalMatches(segment, other.segments[index], s.caseSensitive) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// overlaps gets whether some path is a path of both shapes.
+func (s patternShape) overlaps(other patternShape) bool {
+
+	switch {
+	case !s.open && !other.open && len(s.segments) != len(other.segments):
+		return false
+	case s.open && !other.open && len(other.segments) < len(s.segments):
+		return false
+	case other.open && !s.open && len(s.segments) < len(other.segments):
+		return false
+	}
+
+	for index := 0; index < len(s.segments) && index < len(other.segments); index++ {
+		segment, otherSegment := s.segments[index], other.segments[index]
+		if len(segment) > 0 && len(otherSegment) > 0 && !literalMatches(segment, otherSegment, s.caseSensitive && other.caseSensitive) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// Covers gets whether every path matched by the other PathPattern is also
+// matched by this one, meaning a handler mapped for this pattern would handle
+// all of the requests of one mapped for the other.
+//
+// Patterns with catch-alls before their last segment (i.e. ***/literal/***)
+// and regular expressions are too complicated to compare, so Covers returns false for them.
+func (p *PathPattern) Covers(other *PathPattern) bool {
+
+	shapes, ok := p.shapes()
+	otherShapes, otherOk := other.shapes()
+	if !ok || !otherOk {
+		return false
+	}
+
+	for _, otherShape := range otherShapes {
+		covered := false
+		for _, shape := range shapes {
+			if shape.covers(otherShape) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// Overlaps gets whether there are paths that both PathPatterns match.
+//
+// Patterns with catch-alls before their last segment (i.e. ***/literal/***)
+// and regular expressions are too complicated to compare, so Overlaps returns false for them.
+func (p *PathPattern) Overlaps(other *PathPattern) bool {
+
+	shapes, ok := p.shapes()
+	otherShapes, otherOk := other.shapes()
+	if !ok || !otherOk {
+		return false
+	}
+
+	for _, shape := range shapes {
+		for _, otherShape := range otherShapes {
+			if shape.overlaps(otherShape) {
+				return true
+			}
+		}
+	}
+
+	return false
+
+}
@@ -0,0 +1,82 @@
+package paths
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func mustPathPattern(path string) *PathPattern {
+	pattern, _ := NewPathPattern(path)
+	return pattern
+}
+
+func TestPathPattern_Covers(t *testing.T) {
+
+	tests := []struct {
+		pattern, other string
+		covers         bool
+	}{
+		{"***", "people/{id}/photo", true},
+		{"people/***", "people/{id}/photo", true},
+		{"people/***", "people", true},
+		{"people/{id}", "people/me", true},
+		{"people/{id}", "PEOPLE/{name}", true},
+		{"people/*", "people/[id]", false},
+		{"people/[id]", "people/*", true},
+		{"people/[id]", "people", true},
+		{"people/me", "people/{id}", false},
+		{"people/{id}", "people/{id}/photo", false},
+		{"people/{id}/***", "people/***", false},
+		{"***/people/***", "people", false},
+		{"people/***path", "people/{id}", true},
+		{"people/{path...}", "people/me", true},
+		{"people/***/photo", "people/me/photo", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.covers, mustPathPattern(test.pattern).Covers(mustPathPattern(test.other)), "%s covers %s", test.pattern, test.other)
+	}
+
+}
+
+func TestPathPattern_Covers_CaseSensitive(t *testing.T) {
+
+	sensitive := func(path string) *PathPattern {
+		pattern := mustPathPattern(path)
+		pattern.CaseSensitive = true
+		return pattern
+	}
+
+	assert.True(t, mustPathPattern("people/{id}").Covers(sensitive("People/me")))
+	assert.False(t, sensitive("people/{id}").Covers(mustPathPattern("people/me")), "people/{id} doesn't match People/me")
+	assert.True(t, sensitive("people/{id}").Covers(sensitive("people/me")))
+	assert.False(t, sensitive("people/{id}").Covers(sensitive("People/me")))
+
+	assert.True(t, sensitive("people/me").Overlaps(mustPathPattern("PEOPLE/{id}")))
+	assert.False(t, sensitive("people/me").Overlaps(sensitive("PEOPLE/{id}")))
+
+}
+
+func TestPathPattern_Overlaps(t *testing.T) {
+
+	tests := []struct {
+		pattern, other string
+		overlaps       bool
+	}{
+		{"people/{id}/photo", "people/me/{size}", true},
+		{"people/me", "people/{id}", true},
+		{"people/***", "people/{id}/photo", true},
+		{"people/{id}", "books/{id}", false},
+		{"people/{id}", "people/{id}/photo", false},
+		{"people/[id]", "people", true},
+		{"people/{id}/***", "people", false},
+		{"/", "people", false},
+		{"/", "***", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.overlaps, mustPathPattern(test.pattern).Overlaps(mustPathPattern(test.other)), "%s overlaps %s", test.pattern, test.other)
+		assert.Equal(t, test.overlaps, mustPathPattern(test.other).Overlaps(mustPathPattern(test.pattern)), "%s overlaps %s", test.other, test.pattern)
+	}
+
+}
@@ -2,6 +2,7 @@ package paths
 
 import (
 	"fmt"
+	"net/url"
 	"path"
 	"strings"
 )
@@ -14,6 +15,10 @@ type Path struct {
 	// segments holds the path segments.
 	segments []string
 
+	// fullSegments holds the path segments, with any file extension left on
+	// the last one.
+	fullSegments []string
+
 	// extension holds the file extension of this path.
 	extension string
 }
@@ -28,6 +33,68 @@ func NewPath(rawPath string) *Path {
 
 }
 
+// NewPathFromURL creates a new Path for the path of the URL.
+//
+// Unlike NewPath, the path is split into segments before it is unescaped, so
+// percent-encoded slashes (%2F) stay part of the segment they are in, i.e.
+// /files/a%2Fb has the segments "files" and "a/b".  Segments that would contain
+// "." or ".." once unescaped (i.e. ..%2Fetc) are left escaped, so they can't be
+// used to climb out of other paths.
+//
+// RawPath is the cleaned, unescaped path, exactly as with NewPath.
+func NewPathFromURL(u *url.URL) *Path {
+
+	var segments []string
+	for _, escaped := range strings.Split(u.EscapedPath(), PathSeperator) {
+
+		segment, err := url.PathUnescape(escaped)
+		if err != nil || hasDotSegments(segment) {
+			segment = escaped
+		}
+
+		// clean the path like path.Clean would
+		switch segment {
+		case "", ".":
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, segment)
+		}
+
+	}
+
+	if len(segments) == 0 {
+		segments = []string{""}
+	}
+
+	p := new(Path)
+	p.RawPath = cleanPath(u.Path)
+	p.fullSegments = segments
+	p.Segments()
+	return p
+
+}
+
+// hasDotSegments gets whether the unescaped segment contains slashes and "." or
+// ".." between them.
+func hasDotSegments(segment string) bool {
+
+	if !strings.Contains(segment, PathSeperator) {
+		return false
+	}
+
+	for _, part := range strings.Split(segment, PathSeperator) {
+		if part == "." || part == ".." {
+			return true
+		}
+	}
+
+	return false
+
+}
+
 // cleanPath cleans returns the cleaned version of the specified path.
 func cleanPath(rawPath string) string {
 	return strings.TrimRight(strings.TrimLeft(path.Clean(rawPath), PathSeperator), PathSeperator)
@@ -50,14 +117,18 @@ func (p *Path) Segments() []string {
 
 	if len(p.segments) == 0 {
 
-		p.segments = strings.Split(p.RawPath, "/")
+		if p.fullSegments == nil {
+			p.fullSegments = strings.Split(p.RawPath, "/")
+		}
+		p.segments = append([]string(nil), p.fullSegments...)
 
-		// handle the extension in the last segment
+		// handle the extension in the last segment, looking only at what
+		// follows any (encoded) slashes in it
 		lastSegment := p.segments[len(p.segments)-1]
-		lastDot := strings.LastIndex(lastSegment, FileExtensionSeparator)
-		if lastDot > -1 {
-			extsegs := strings.Split(lastSegment, FileExtensionSeparator)
-			p.segments[len(p.segments)-1] = extsegs[0]
+		nameStart := lastNameStart(lastSegment)
+		if name := lastSegment[nameStart:]; strings.Contains(name, FileExtensionSeparator) {
+			extsegs := strings.Split(name, FileExtensionSeparator)
+			p.segments[len(p.segments)-1] = lastSegment[:nameStart] + extsegs[0]
 			p.extension = extsegs[1]
 		}
 
@@ -66,6 +137,26 @@ func (p *Path) Segments() []string {
 	return p.segments
 }
 
+// lastNameStart gets the index in the segment after its last slash, or escaped
+// slash (%2F), or zero if there aren't any.
+func lastNameStart(segment string) int {
+
+	start := strings.LastIndex(segment, PathSeperator) + 1
+	if escaped := strings.LastIndex(strings.ToUpper(segment), "%2F"); escaped > -1 && escaped+3 > start {
+		start = escaped + 3
+	}
+
+	return start
+
+}
+
+// segmentsWithExtension gets the segments of the path, with any file extension
+// left on the last one.
+func (p *Path) segmentsWithExtension() []string {
+	p.Segments()
+	return p.fullSegments
+}
+
 // RealFilePath gets the real file path by assuming the current path is
 // the public prefix, the urlPath is the actual request and the systemPath
 // is the physical location where those files live.
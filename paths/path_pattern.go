@@ -19,25 +19,94 @@ import (
     /[optional placeholder]
     /* - matches like a placeholder but doesn't care what it is
     /something/*** - Matches the start plus anything after it
+    /something/***name or /something/{name...} - Matches the start plus anything
+      after it, with whatever that is (i.e. "a/b/c.txt") available as name
+    /something/*** /edit - catch-alls can also go in the middle of paths
+
+  PathPatterns can also be made from regular expressions (see NewRegexpPathPattern).
 
 */
 type PathPattern struct {
 
-	// RawPath is the raw path.
+	// RawPath is the raw path, or the regular expression for patterns made with
+	// NewRegexpPathPattern.
 	RawPath string
 
+	// CaseSensitive indicates whether literal segments only match segments of
+	// paths with the same case.  By default, people matches People and PEOPLE.
+	CaseSensitive bool
+
 	path *Path
+
+	// regexp is the regular expression paths must match, or nil for normal
+	// patterns.
+	regexp *regexp.Regexp
 }
 
 func NewPathPattern(path string) (*PathPattern, error) {
 
 	p := new(PathPattern)
 	p.RawPath = path
-	p.path = NewPath(path)
+
+	// {name...} is the same as ***name (which doesn't look like it has a file
+	// extension)
+	segments := strings.Split(path, PathSeperator)
+	for index, segment := range segments {
+		if strings.HasPrefix(segment, segmentDynamicPrefix) && strings.HasSuffix(segment, segmentNamedCatchAllSuffix) {
+			segments[index] = segmentCatchAll + cleanSegmentName(segment)
+		}
+	}
+	p.path = NewPath(strings.Join(segments, PathSeperator))
 
 	return p, nil
 }
 
+// NewRegexpPathPattern makes a new PathPattern that matches paths using the
+// specified regular expression.  The expression is matched against the path
+// without its leading slash (i.e. people/123.json), and the values of named
+// capture groups become path parameters:
+//
+//     paths.NewRegexpPathPattern(`^people/(?P<id>[0-9]+)$`)
+//
+// Unlike normal patterns, matching is case sensitive unless the expression
+// says otherwise (i.e. with (?i)).  An error is returned if the expression
+// isn't valid.
+func NewRegexpPathPattern(expr string) (*PathPattern, error) {
+
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PathPattern{RawPath: expr, regexp: compiled}, nil
+
+}
+
+// IsRegexp gets whether the PathPattern was made from a regular expression.
+func (p *PathPattern) IsRegexp() bool {
+	return p.regexp != nil
+}
+
+// regexpParameters gets the values of the named capture groups of the regular
+// expression in the string, or false if the regular expression doesn't match it.
+func regexpParameters(regex *regexp.Regexp, s string) (objx.Map, bool) {
+
+	submatches := regex.FindStringSubmatch(s)
+	if submatches == nil {
+		return nil, false
+	}
+
+	parameters := make(objx.Map)
+	for index, name := range regex.SubexpNames() {
+		if index > 0 && len(name) > 0 {
+			parameters[name] = submatches[index]
+		}
+	}
+
+	return parameters, true
+
+}
+
 func (p *PathPattern) String() string {
 	return stewstrings.MergeStrings("{PathPattern:\"", p.RawPath, "\"}")
 }
@@ -48,6 +117,15 @@ func (p *PathPattern) String() string {
 */
 func (p *PathPattern) GetPathMatch(path *Path) *PathMatch {
 
+	// regular expressions match the whole path
+	if p.regexp != nil {
+		parameters, matches := regexpParameters(p.regexp, path.RawPath)
+		if !matches {
+			return PathDoesntMatch
+		}
+		return &PathMatch{Matches: true, Parameters: parameters}
+	}
+
 	pathMatch := new(PathMatch)
 	pathMatch.Matches = true
 
@@ -58,99 +136,116 @@ func (p *PathPattern) GetPathMatch(path *Path) *PathMatch {
 
 	pathMatch.Parameters = make(objx.Map)
 
-	checkSegments := p.path.Segments()
-	pathSegments := path.Segments()
-
-	lastCheckSegment := checkSegments[len(checkSegments)-1]
-	lastCheckSegmentType := getSegmentType(lastCheckSegment)
-
-	// handle catchall prefix, prefix literal, etc
-	// https://github.com/stretchr/goweb/issues/53
-	if getSegmentType(checkSegments[0]) == segmentTypeCatchall {
-		//ensure we are working with only literals
-		for _, pathSegment := range pathSegments {
-			if getSegmentType(pathSegment) != segmentTypeLiteral {
-				return PathDoesntMatch
-			}
-		}
-
-		// build the regex to match against the raw path
-		regexString := strings.Join(checkSegments, "")
-		regexString = strings.Replace(regexString, segmentCatchAll, "(.*)?", -1)
-		regexString = "(?i)^" + regexString + "$"
-		pathRegex := regexp.MustCompile(regexString)
-
-		if pathRegex.MatchString(path.RawPath) {
-			return pathMatch
-		}
+	// catch-alls capture the segments as they were, with any file extension,
+	// whereas the other segments are matched without it
+	matcher := &segmentMatcher{
+		checkSegments: withoutRoot(p.path.Segments()),
+		pathSegments:  withoutRoot(path.Segments()),
+		rawSegments:   withoutRoot(path.segmentsWithExtension()),
+		caseSensitive: p.CaseSensitive,
+		parameters:    pathMatch.Parameters,
+	}
 
+	if !matcher.match(0, 0) {
 		return PathDoesntMatch
 	}
 
-	// make sure the segments match in length, or there is a catchall
-	// at the end of the check path
-	if len(checkSegments) < len(pathSegments) {
-
-		// check segments: /poeple/{something}
-		// path segments: /people/something/more
+	return pathMatch
 
-		// is the last segment a catchall?
-		if lastCheckSegmentType != segmentTypeCatchall {
-			return PathDoesntMatch
-		}
+}
 
-	} else if len(checkSegments) > len(pathSegments) {
+// withoutRoot gets the segments, or no segments if they are just those of the
+// root path.
+func withoutRoot(segments []string) []string {
+	if len(segments) == 1 && (len(segments[0]) == 0 || segments[0] == ".") {
+		return nil
+	}
+	return segments
+}
 
-		// check segments: /people/{id}
-		// path segments:  /people
+// segmentMatcher matches the segments of a path against those of a pattern.
+type segmentMatcher struct {
+	checkSegments []string
+	pathSegments  []string
+	rawSegments   []string
+	caseSensitive bool
+	parameters    objx.Map
+
+	// failed holds the catch-alls (and the path segments they started at) that
+	// have already failed to match, so patterns with many catch-alls don't
+	// try the same thing over and over again.
+	failed map[[2]int]bool
+}
 
-		// this situation is only OK if the last segment is optional, or
-		// if it's the catch-all.
-		if lastCheckSegmentType != segmentTypeDynamicOptional &&
-			lastCheckSegmentType != segmentTypeCatchall {
-			return PathDoesntMatch
-		}
+// match gets whether the check segments from checkIndex onwards match the path
+// segments from pathIndex onwards.  Catch-alls match as many segments as they
+// can while still letting the rest of the pattern match.  The parameters of the
+// match are only set once the rest of the pattern has matched.
+func (m *segmentMatcher) match(checkIndex, pathIndex int) bool {
 
+	if checkIndex == len(m.checkSegments) {
+		return pathIndex == len(m.pathSegments)
 	}
 
-	// check each segment
-	for segmentIndex, checkSegment := range checkSegments {
+	checkSegment := m.checkSegments[checkIndex]
+	name := cleanSegmentName(checkSegment)
+	remaining := len(m.pathSegments) - pathIndex
 
-		switch getSegmentType(checkSegment) {
-		case segmentTypeLiteral:
+	switch getSegmentType(checkSegment) {
+	case segmentTypeCatchall:
 
-			if segmentIndex < len(pathSegments) {
+		state := [2]int{checkIndex, pathIndex}
+		if m.failed[state] {
+			return false
+		}
 
-				pathSegment := pathSegments[segmentIndex]
-				if strings.ToLower(pathSegment) != strings.ToLower(checkSegment) {
-					return PathDoesntMatch
+		for count := remaining; count >= 0; count-- {
+			if m.match(checkIndex+1, pathIndex+count) {
+				if len(name) > 0 {
+					m.parameters[name] = strings.Join(m.rawSegments[pathIndex:pathIndex+count], PathSeperator)
 				}
-
-			} else {
-				return PathDoesntMatch
+				return true
 			}
+		}
 
-		case segmentTypeDynamic:
+		if m.failed == nil {
+			m.failed = make(map[[2]int]bool)
+		}
+		m.failed[state] = true
+		return false
 
-			if segmentIndex < len(pathSegments) {
-				// set the parameter value
-				pathMatch.Parameters[cleanSegmentName(checkSegment)] = pathSegments[segmentIndex]
-			} else {
-				// missing variable - and it's not optional - see https://github.com/stretchr/goweb/issues/77
-				return PathDoesntMatch
-			}
+	case segmentTypeDynamicOptional:
 
-		case segmentTypeDynamicOptional:
+		// optional segments are only left out at the end of the path
+		if remaining == 0 {
+			return m.match(checkIndex+1, pathIndex)
+		}
 
-			if segmentIndex < len(pathSegments) {
-				// set the parameter value
-				pathMatch.Parameters[cleanSegmentName(checkSegment)] = pathSegments[segmentIndex]
-			}
+	case segmentTypeLiteral:
 
+		if remaining == 0 || !literalMatches(checkSegment, m.pathSegments[pathIndex], m.caseSensitive) {
+			return false
 		}
+		return m.match(checkIndex+1, pathIndex+1)
 
 	}
 
-	return pathMatch
+	// {dynamic}, [optional] and * segments match a single segment
+	if remaining == 0 || !m.match(checkIndex+1, pathIndex+1) {
+		return false
+	}
+	if len(name) > 0 {
+		m.parameters[name] = m.pathSegments[pathIndex]
+	}
+	return true
 
 }
+
+// literalMatches gets whether the segment of a path matches a literal segment of
+// a pattern.
+func literalMatches(literal, segment string, caseSensitive bool) bool {
+	if caseSensitive {
+		return literal == segment
+	}
+	return strings.ToLower(literal) == strings.ToLower(segment)
+}
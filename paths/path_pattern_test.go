@@ -2,7 +2,10 @@ package paths
 
 import (
 	"github.com/stretchr/testify/assert"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewPathPattern(t *testing.T) {
@@ -167,3 +170,151 @@ func TestIssue81(t *testing.T) {
 	assert.True(t, p.GetPathMatch(NewPath("/prefix/static")).Matches)
 	assert.False(t, p.GetPathMatch(NewPath("/static")).Matches)
 }
+
+func TestPathPattern_GetPathMatch_NamedCatchalls(t *testing.T) {
+
+	for _, pattern := range []string{"/files/***path", "/files/{path...}"} {
+
+		gp, _ := NewPathPattern(pattern)
+
+		m := gp.GetPathMatch(NewPath("/files/docs/2013/Report.final.pdf"))
+		assert.True(t, m.Matches, pattern)
+		assert.Equal(t, "docs/2013/Report.final.pdf", m.Parameters["path"], "%s keeps the extension", pattern)
+
+		m = gp.GetPathMatch(NewPath("/files"))
+		assert.True(t, m.Matches, pattern)
+		assert.Equal(t, "", m.Parameters["path"], pattern)
+
+		assert.False(t, gp.GetPathMatch(NewPath("/people/123")).Matches, pattern)
+
+	}
+
+	// the whole path
+	gp, _ := NewPathPattern("***path")
+	assert.Equal(t, "people/123.json", gp.GetPathMatch(NewPath("/people/123.json")).Parameters["path"])
+	m := gp.GetPathMatch(NewPath("/"))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "", m.Parameters["path"])
+
+	// only a lone * is a wildcard; other segments starting with * are literals
+	gp, _ = NewPathPattern("/people/*id/books")
+	m = gp.GetPathMatch(NewPath("/people/*id/books"))
+	assert.True(t, m.Matches)
+	assert.Empty(t, m.Parameters)
+	assert.False(t, gp.GetPathMatch(NewPath("/people/123/books")).Matches)
+
+}
+
+func TestPathPattern_GetPathMatch_MiddleCatchalls(t *testing.T) {
+
+	gp, _ := NewPathPattern("/files/***path/edit")
+
+	m := gp.GetPathMatch(NewPath("/files/docs/report.txt/edit"))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "docs/report.txt", m.Parameters["path"])
+
+	m = gp.GetPathMatch(NewPath("/files/edit"))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "", m.Parameters["path"])
+
+	// catch-alls take as much as they can
+	m = gp.GetPathMatch(NewPath("/files/edit/edit/edit"))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "edit/edit", m.Parameters["path"])
+
+	assert.False(t, gp.GetPathMatch(NewPath("/files/docs/report.txt")).Matches)
+	assert.False(t, gp.GetPathMatch(NewPath("/files/docs/edit/more")).Matches)
+
+	// backtracking over more than one catch-all
+	gp, _ = NewPathPattern("/***owner/repos/{repo}/***path")
+	m = gp.GetPathMatch(NewPath("/acme/team/repos/goweb/src/paths/segments.go"))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "acme/team", m.Parameters["owner"])
+	assert.Equal(t, "goweb", m.Parameters["repo"])
+	assert.Equal(t, "src/paths/segments.go", m.Parameters["path"])
+
+	assert.False(t, gp.GetPathMatch(NewPath("/acme/team/repos")).Matches)
+
+}
+
+func TestPathPattern_GetPathMatch_ManyCatchalls(t *testing.T) {
+
+	gp, _ := NewPathPattern("/***/a/***/a/***/a/***/a/***/a/***/a/***/b")
+	path := NewPath(strings.Repeat("/a", 200))
+
+	started := time.Now()
+	assert.False(t, gp.GetPathMatch(path).Matches)
+	assert.True(t, time.Since(started) < time.Second, "Catch-alls that failed aren't tried again")
+
+	m := gp.GetPathMatch(NewPath(strings.Repeat("/a", 200) + "/b"))
+	assert.True(t, m.Matches)
+
+}
+
+func TestPathPattern_GetPathMatch_CaseSensitive(t *testing.T) {
+
+	gp, _ := NewPathPattern("/people/{id}/Books")
+	gp.CaseSensitive = true
+
+	m := gp.GetPathMatch(NewPath("/people/ABC/Books"))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "ABC", m.Parameters["id"])
+
+	assert.False(t, gp.GetPathMatch(NewPath("/People/ABC/Books")).Matches)
+	assert.False(t, gp.GetPathMatch(NewPath("/people/ABC/books")).Matches)
+
+}
+
+func TestPathPattern_GetPathMatch_EncodedSlashes(t *testing.T) {
+
+	gp, _ := NewPathPattern("/files/{name}/***rest")
+	u, _ := url.Parse("http://goweb.org/files/a%2Fb/c%2Fd/e.txt")
+
+	m := gp.GetPathMatch(NewPathFromURL(u))
+	assert.True(t, m.Matches)
+	assert.Equal(t, "a/b", m.Parameters["name"])
+	assert.Equal(t, "c/d/e.txt", m.Parameters["rest"])
+
+}
+
+func TestNewRegexpPathPattern(t *testing.T) {
+
+	gp, err := NewRegexpPathPattern(`^people/(?P<id>[0-9]+)(\.(?P<format>json|xml))?$`)
+
+	if assert.NoError(t, err) {
+
+		assert.True(t, gp.IsRegexp())
+		assert.Equal(t, `^people/(?P<id>[0-9]+)(\.(?P<format>json|xml))?$`, gp.RawPath)
+
+		m := gp.GetPathMatch(NewPath("/people/123.json"))
+		assert.True(t, m.Matches)
+		assert.Equal(t, "123", m.Parameters["id"])
+		assert.Equal(t, "json", m.Parameters["format"])
+		assert.Equal(t, 2, len(m.Parameters), "Unnamed groups are ignored")
+
+		m = gp.GetPathMatch(NewPath("/people/123"))
+		assert.True(t, m.Matches)
+		assert.Equal(t, "", m.Parameters["format"])
+
+		assert.False(t, gp.GetPathMatch(NewPath("/people/abc")).Matches)
+		assert.False(t, gp.GetPathMatch(NewPath("/People/123")).Matches, "Regular expressions are case sensitive")
+
+	}
+
+	_, err = NewRegexpPathPattern(`[0-9]++`)
+	assert.Error(t, err)
+
+}
+
+func TestRegexpPathPattern_Comparisons(t *testing.T) {
+
+	regex, _ := NewRegexpPathPattern(`^people/.*$`)
+	normal, _ := NewPathPattern("people/{id}")
+
+	assert.False(t, regex.Covers(normal))
+	assert.False(t, normal.Overlaps(regex))
+	assert.False(t, regex.MoreSpecificThan(normal))
+	assert.False(t, normal.MoreSpecificThan(regex))
+	assert.False(t, normal.IsRegexp())
+
+}
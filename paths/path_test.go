@@ -2,6 +2,7 @@ package paths
 
 import (
 	"github.com/stretchr/testify/assert"
+	"net/url"
 	"testing"
 )
 
@@ -66,3 +67,49 @@ func TestPath_RealFilePath(t *testing.T) {
 	assert.Equal(t, "/static-files/hello-world", p.RealFilePath(systemPath, urlPath))
 
 }
+
+func TestNewPathFromURL(t *testing.T) {
+
+	u, _ := url.Parse("http://goweb.org/files/docs%2F2013/report.pdf")
+	p := NewPathFromURL(u)
+	assert.Equal(t, []string{"files", "docs/2013", "report"}, p.Segments())
+	assert.Equal(t, "files/docs/2013/report.pdf", p.RawPath)
+
+	u, _ = url.Parse("http://goweb.org//people/./123/../456/")
+	p = NewPathFromURL(u)
+	assert.Equal(t, []string{"people", "456"}, p.Segments())
+	assert.Equal(t, "people/456", p.RawPath)
+
+	u, _ = url.Parse("http://goweb.org/")
+	assert.Equal(t, "", NewPathFromURL(u).RawPath)
+	assert.Equal(t, []string{""}, NewPathFromURL(u).Segments())
+
+	// encoded slashes can't be used to climb out of the path
+	u, _ = url.Parse("http://goweb.org/files/..%2F..%2Fetc%2Fpasswd/meta")
+	p = NewPathFromURL(u)
+	assert.Equal(t, []string{"files", "..%2F..%2Fetc%2Fpasswd", "meta"}, p.Segments())
+	assert.Equal(t, "etc/passwd/meta", p.RawPath)
+
+	u, _ = url.Parse("http://goweb.org/files/%2e%2e/etc")
+	assert.Equal(t, []string{"etc"}, NewPathFromURL(u).Segments())
+
+}
+
+func TestNewPathFromURL_Extension(t *testing.T) {
+
+	u, _ := url.Parse("http://goweb.org/docs/..%2F..%2Fx")
+	p := NewPathFromURL(u)
+	assert.Equal(t, []string{"docs", "..%2F..%2Fx"}, p.Segments())
+	assert.Equal(t, "", p.extension)
+
+	u, _ = url.Parse("http://goweb.org/versions/v1.2%2Fa")
+	p = NewPathFromURL(u)
+	assert.Equal(t, []string{"versions", "v1.2/a"}, p.Segments())
+	assert.Equal(t, "", p.extension)
+
+	u, _ = url.Parse("http://goweb.org/versions/v1.2%2Fa.json")
+	p = NewPathFromURL(u)
+	assert.Equal(t, []string{"versions", "v1.2/a"}, p.Segments())
+	assert.Equal(t, "json", p.extension)
+
+}
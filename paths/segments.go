@@ -16,6 +16,11 @@ const (
 
 func getSegmentType(segment string) segmentType {
 
+	// {name...}
+	if strings.HasPrefix(segment, segmentDynamicPrefix) && strings.HasSuffix(segment, segmentNamedCatchAllSuffix) {
+		return segmentTypeCatchall
+	}
+
 	if strings.HasPrefix(segment, segmentDynamicPrefix) && strings.HasSuffix(segment, segmentDynamicSuffix) {
 		return segmentTypeDynamic
 	}
@@ -24,18 +29,34 @@ func getSegmentType(segment string) segmentType {
 		return segmentTypeDynamicOptional
 	}
 
-	if segment == segmentWildcard {
-		return segmentTypeWildcard
+	// *** or ***name
+	if strings.HasPrefix(segment, segmentCatchAll) {
+		return segmentTypeCatchall
 	}
 
-	if segment == segmentCatchAll {
-		return segmentTypeCatchall
+	// only a lone * is a wildcard, so literal segments like *foo still match
+	// themselves
+	if segment == segmentWildcard {
+		return segmentTypeWildcard
 	}
 
 	return segmentTypeLiteral
 
 }
 
+// cleanSegmentName gets the name of the parameter a segment captures, which is
+// empty for * and unnamed *** segments.
 func cleanSegmentName(segment string) string {
+
+	switch {
+	case strings.HasPrefix(segment, segmentCatchAll):
+		return strings.TrimPrefix(segment, segmentCatchAll)
+	case segment == segmentWildcard:
+		return ""
+	case strings.HasSuffix(segment, segmentNamedCatchAllSuffix):
+		return strings.TrimSuffix(strings.TrimPrefix(segment, segmentDynamicPrefix), segmentNamedCatchAllSuffix)
+	}
+
 	return strings.Trim(segment, "{}[]")
+
 }
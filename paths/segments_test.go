@@ -12,6 +12,9 @@ func TestGetSegmentType(t *testing.T) {
 	assert.Equal(t, segmentType(segmentTypeDynamicOptional), getSegmentType("[id]"))
 	assert.Equal(t, segmentType(segmentTypeWildcard), getSegmentType(segmentWildcard))
 	assert.Equal(t, segmentType(segmentTypeCatchall), getSegmentType(segmentCatchAll))
+	assert.Equal(t, segmentType(segmentTypeLiteral), getSegmentType("*name"))
+	assert.Equal(t, segmentType(segmentTypeCatchall), getSegmentType("***path"))
+	assert.Equal(t, segmentType(segmentTypeCatchall), getSegmentType("{path...}"))
 
 }
 
@@ -20,5 +23,9 @@ func TestCleanSegmentName(t *testing.T) {
 	assert.Equal(t, "id", cleanSegmentName("id"))
 	assert.Equal(t, "id", cleanSegmentName("{id}"))
 	assert.Equal(t, "id", cleanSegmentName("[id]"))
+	assert.Equal(t, "path", cleanSegmentName("***path"))
+	assert.Equal(t, "path", cleanSegmentName("{path...}"))
+	assert.Equal(t, "", cleanSegmentName(segmentWildcard))
+	assert.Equal(t, "", cleanSegmentName(segmentCatchAll))
 
 }
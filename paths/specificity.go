@@ -0,0 +1,45 @@
+package paths
+
+// specificityRanks ranks the types of segment from the most specific (literal)
+// to the least specific (catch-all).
+var specificityRanks map[segmentType]int = map[segmentType]int{
+	segmentTypeLiteral:         0,
+	segmentTypeDynamic:         1,
+	segmentTypeDynamicOptional: 2,
+	segmentTypeWildcard:        3,
+	segmentTypeCatchall:        4,
+}
+
+// MoreSpecificThan gets whether this PathPattern is more specific than the other
+// one.  The segments are compared in turn, and at the first that differs, literal
+// segments beat {dynamic} ones, which beat [optional] ones, which beat *, which
+// beats ***.  Patterns that are equally specific aren't more specific than each
+// other, and neither are regular expressions, as they can't be compared.
+func (p *PathPattern) MoreSpecificThan(other *PathPattern) bool {
+
+	if p.regexp != nil || other.regexp != nil {
+		return false
+	}
+
+	segments, otherSegments := p.path.Segments(), other.path.Segments()
+
+	for index := 0; index < len(segments) || index < len(otherSegments); index++ {
+
+		// the end of the pattern is as specific as a literal segment
+		var rank, otherRank int
+		if index < len(segments) {
+			rank = specificityRanks[getSegmentType(segments[index])]
+		}
+		if index < len(otherSegments) {
+			otherRank = specificityRanks[getSegmentType(otherSegments[index])]
+		}
+
+		if rank != otherRank {
+			return rank < otherRank
+		}
+
+	}
+
+	return false
+
+}
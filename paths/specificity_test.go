@@ -0,0 +1,31 @@
+package paths
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPathPattern_MoreSpecificThan(t *testing.T) {
+
+	// each pattern is more specific than the ones after it
+	ordered := []string{
+		"people/me",
+		"people/{id}",
+		"people/[id]",
+		"people/*",
+		"people/***",
+		"{collection}/{id}",
+		"***",
+	}
+
+	for i, pattern := range ordered {
+		for j, other := range ordered {
+			assert.Equal(t, i < j, mustPathPattern(pattern).MoreSpecificThan(mustPathPattern(other)), "%s more specific than %s", pattern, other)
+		}
+	}
+
+	assert.False(t, mustPathPattern("people/{id}").MoreSpecificThan(mustPathPattern("books/{name}")), "Equally specific patterns")
+	assert.False(t, mustPathPattern("people/{id}/photo").MoreSpecificThan(mustPathPattern("people/{id}")), "The end of a pattern is as specific as a literal")
+	assert.True(t, mustPathPattern("people").MoreSpecificThan(mustPathPattern("people/***")))
+
+}
@@ -0,0 +1,30 @@
+// The query package parses the pagination, sorting and filtering parameters of
+// requests for lists of resources (i.e. RestfulManyReader.ReadMany actions).
+//
+// Inside an action, the ListQuery is parsed from the context:
+//
+//     func (c *PeopleController) ReadMany(ctx context.Context) error {
+//
+//       q, err := query.FromContext(ctx)
+//       if err != nil {
+//         return goweb.API.RespondWithError(ctx, http.StatusBadRequest, err.Error())
+//       }
+//
+//       people, total := c.findPeople(q.Offset, q.Limit, q.Sort, q.Filters)
+//       return responders.RespondWithPage(goweb.API, ctx, people, q.OffsetPage(total))
+//
+//     }
+//
+// By default, the following parameters are understood:
+//
+//     ?page=2&limit=10        -  the second page of ten items
+//     ?offset=10&limit=10     -  the same page, by offset
+//     ?sort=-age,name         -  oldest first, then by name
+//     ?name=Mat&age[gte]=18   -  filtered by name and age (see Operator)
+//
+// The Options control the names of the parameters, the maximum limit, whether
+// pages are found by offset or by cursor, and which fields may be sorted and
+// filtered on.  Set the Options for every request by putting them in the
+// HttpHandler's Data with the context.DataKeyListQueryOptions key, or for
+// specific routes by setting them in a before handler.
+package query
@@ -0,0 +1,228 @@
+package query
+
+import (
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Operator represents the way a Filter compares a field with its value.
+type Operator string
+
+const (
+	// Equal matches items whose field equals the value (i.e. ?name=Mat).
+	Equal Operator = "eq"
+	// NotEqual matches items whose field doesn't equal the value (i.e. ?name[ne]=Mat).
+	NotEqual Operator = "ne"
+	// GreaterThan matches items whose field is greater than the value (i.e. ?age[gt]=18).
+	GreaterThan Operator = "gt"
+	// GreaterThanOrEqual matches items whose field is at least the value (i.e. ?age[gte]=18).
+	GreaterThanOrEqual Operator = "gte"
+	// LessThan matches items whose field is less than the value (i.e. ?age[lt]=65).
+	LessThan Operator = "lt"
+	// LessThanOrEqual matches items whose field is at most the value (i.e. ?age[lte]=65).
+	LessThanOrEqual Operator = "lte"
+	// In matches items whose field is one of the comma separated values
+	// (i.e. ?status[in]=open,pending).
+	In Operator = "in"
+	// Contains matches items whose field contains the value (i.e. ?name[contains]=at).
+	Contains Operator = "contains"
+)
+
+// Operators are all the Operators, in the order in which filters using them appear
+// in a ListQuery.
+var Operators []Operator = []Operator{Equal, NotEqual, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual, In, Contains}
+
+// SortField represents a field to sort a list by.
+type SortField struct {
+	// Field is the name of the field.
+	Field string
+	// Descending indicates whether the largest values come first or not.
+	Descending bool
+}
+
+// Filter represents a condition items in a list must meet.
+type Filter struct {
+	// Field is the name of the field.
+	Field string
+	// Operator is the way the field is compared to the Value.
+	Operator Operator
+	// Value is the value to compare the field with.
+	Value string
+}
+
+// Values gets the comma separated values of an In filter.
+func (f Filter) Values() []string {
+	return strings.Split(f.Value, ",")
+}
+
+// Error represents a problem with a parameter of a list query.
+type Error struct {
+	// Parameter is the name of the offending parameter.
+	Parameter string
+	// Message describes the problem.
+	Message string
+}
+
+// Error gets the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("query: invalid %s parameter: %s", e.Parameter, e.Message)
+}
+
+// ListQuery represents the page, sort order and filters asked for by a request
+// for a list of items.
+type ListQuery struct {
+
+	// Limit is the maximum number of items to return.
+	Limit int
+
+	// Page is the number of the page (starting at 1) in OffsetMode.  If the client
+	// asked for an offset that isn't at the start of a page, it is the page containing
+	// that offset.
+	Page int
+
+	// Offset is the number of items to skip in OffsetMode.
+	Offset int
+
+	// Cursor is the cursor of the page in CursorMode, or an empty string for the
+	// first page.
+	Cursor string
+
+	// Sort are the fields to sort the items by, in order.
+	Sort []SortField
+
+	// Filters are the conditions items must meet.
+	Filters []Filter
+
+	// options are the Options the query was parsed with.
+	options *Options
+}
+
+// Parse parses the ListQuery from the specified query values, using the specified
+// Options (or DefaultOptions if nil).
+//
+// If any of the parameters are invalid, an *Error is returned.
+func Parse(values url.Values, options *Options) (*ListQuery, error) {
+
+	if options == nil {
+		options = DefaultOptions
+	}
+
+	q := &ListQuery{Limit: options.DefaultLimit, Page: 1, options: options}
+	if q.Limit < 1 {
+		q.Limit = DefaultLimit
+	}
+
+	// limit
+	if limitStr := values.Get(options.LimitParameter); len(limitStr) > 0 {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, &Error{options.LimitParameter, "must be a positive number"}
+		}
+		q.Limit = limit
+	}
+	if options.MaxLimit > 0 && q.Limit > options.MaxLimit {
+		q.Limit = options.MaxLimit
+	}
+
+	// page
+	switch options.Mode {
+	case CursorMode:
+		q.Cursor = values.Get(options.CursorParameter)
+	default:
+		if offsetStr := values.Get(options.OffsetParameter); len(offsetStr) > 0 {
+			offset, err := strconv.Atoi(offsetStr)
+			if err != nil || offset < 0 {
+				return nil, &Error{options.OffsetParameter, "must be zero or a positive number"}
+			}
+			q.Offset = offset
+			q.Page = offset/q.Limit + 1
+		} else if pageStr := values.Get(options.PageParameter); len(pageStr) > 0 {
+			page, err := strconv.Atoi(pageStr)
+			// the offset of the page must fit in an int too
+			if err != nil || page < 1 || page-1 > math.MaxInt/q.Limit {
+				return nil, &Error{options.PageParameter, "must be a positive number"}
+			}
+			q.Page = page
+			q.Offset = (page - 1) * q.Limit
+		}
+	}
+
+	// sort
+	for _, sortStr := range values[options.SortParameter] {
+		for _, field := range strings.Split(sortStr, ",") {
+
+			field = strings.TrimSpace(field)
+			if len(field) == 0 {
+				continue
+			}
+
+			sortField := SortField{Field: field}
+			if strings.HasPrefix(field, "-") {
+				sortField = SortField{Field: field[1:], Descending: true}
+			}
+
+			if !options.sortable(sortField.Field) {
+				return nil, &Error{options.SortParameter, fmt.Sprintf("cannot sort by %s", sortField.Field)}
+			}
+
+			q.Sort = append(q.Sort, sortField)
+
+		}
+	}
+
+	// filters
+	for _, field := range options.FilterableFields {
+		for _, operator := range Operators {
+
+			parameter := field
+			if operator != Equal {
+				parameter = fmt.Sprintf("%s[%s]", field, operator)
+			}
+
+			for _, value := range values[parameter] {
+				q.Filters = append(q.Filters, Filter{field, operator, value})
+			}
+
+		}
+	}
+
+	return q, nil
+
+}
+
+// FromContext parses the ListQuery from the URL query of the request in the
+// specified context, using the *Options in its Data() with the
+// context.DataKeyListQueryOptions key, or DefaultOptions.
+//
+// If any of the parameters are invalid, an *Error is returned.
+func FromContext(ctx context.Context) (*ListQuery, error) {
+
+	options, _ := ctx.Data().Get(context.DataKeyListQueryOptions).Data().(*Options)
+
+	return Parse(ctx.HttpRequest().URL.Query(), options)
+
+}
+
+// Options gets the Options this ListQuery was parsed with, or DefaultOptions if
+// it wasn't made by Parse.
+func (q *ListQuery) Options() *Options {
+	if q.options == nil {
+		return DefaultOptions
+	}
+	return q.options
+}
+
+// FiltersFor gets the Filters on the specified field.
+func (q *ListQuery) FiltersFor(field string) []Filter {
+	var filters []Filter
+	for _, filter := range q.Filters {
+		if filter.Field == field {
+			filters = append(filters, filter)
+		}
+	}
+	return filters
+}
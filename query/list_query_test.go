@@ -0,0 +1,172 @@
+package query
+
+import (
+	"github.com/stretchr/goweb/context"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func parseTestQuery(t *testing.T, rawQuery string, options *Options) (*ListQuery, error) {
+	values, err := url.ParseQuery(rawQuery)
+	assert.NoError(t, err)
+	return Parse(values, options)
+}
+
+func TestParse_Defaults(t *testing.T) {
+
+	q, err := parseTestQuery(t, "", nil)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DefaultLimit, q.Limit)
+		assert.Equal(t, 1, q.Page)
+		assert.Equal(t, 0, q.Offset)
+		assert.Nil(t, q.Sort)
+		assert.Nil(t, q.Filters)
+		assert.Equal(t, DefaultOptions, q.Options())
+	}
+
+}
+
+func TestParse_Pages(t *testing.T) {
+
+	q, _ := parseTestQuery(t, "page=3&limit=10", nil)
+	assert.Equal(t, 10, q.Limit)
+	assert.Equal(t, 3, q.Page)
+	assert.Equal(t, 20, q.Offset)
+
+	q, _ = parseTestQuery(t, "offset=25&limit=10", nil)
+	assert.Equal(t, 3, q.Page)
+	assert.Equal(t, 25, q.Offset)
+
+	// the limit is capped
+	q, _ = parseTestQuery(t, "limit=1000", nil)
+	assert.Equal(t, DefaultMaxLimit, q.Limit)
+
+	for _, invalid := range []string{"page=0", "page=first", "limit=0", "limit=-1", "offset=-5"} {
+		_, err := parseTestQuery(t, invalid, nil)
+		if assert.Error(t, err, invalid) {
+			assert.IsType(t, &Error{}, err)
+		}
+	}
+
+}
+
+func TestParse_PageOverflow(t *testing.T) {
+
+	_, err := parseTestQuery(t, "page=922337203685477580&limit=20", nil)
+	if assert.Error(t, err) {
+		assert.Equal(t, &Error{"page", "must be a positive number"}, err)
+	}
+
+	// the last page whose offset fits is fine
+	lastPage := math.MaxInt/20 + 1
+	q, err := parseTestQuery(t, "limit=20&page="+strconv.Itoa(lastPage), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, (lastPage-1)*20, q.Offset)
+		assert.True(t, q.Offset >= 0)
+	}
+
+	_, err = parseTestQuery(t, "limit=20&page="+strconv.Itoa(lastPage+1), nil)
+	assert.Error(t, err)
+
+}
+
+func TestParse_ZeroDefaultLimit(t *testing.T) {
+
+	q, err := parseTestQuery(t, "offset=25", &Options{OffsetParameter: "offset"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, DefaultLimit, q.Limit)
+		assert.Equal(t, 2, q.Page)
+	}
+
+	q, err = parseTestQuery(t, "", &Options{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, DefaultLimit, q.Limit)
+	}
+
+}
+
+func TestParse_Cursor(t *testing.T) {
+
+	options := NewOptions(CursorMode)
+	options.CursorParameter = "after"
+
+	q, err := parseTestQuery(t, "after=abc&page=3", options)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abc", q.Cursor)
+		assert.Equal(t, 0, q.Offset)
+	}
+
+}
+
+func TestParse_Sort(t *testing.T) {
+
+	q, err := parseTestQuery(t, "sort=-age,name&sort=id", nil)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []SortField{{"age", true}, {"name", false}, {"id", false}}, q.Sort)
+	}
+
+	options := NewOptions(OffsetMode)
+	options.SortableFields = []string{"name"}
+
+	_, err = parseTestQuery(t, "sort=name", options)
+	assert.NoError(t, err)
+
+	_, err = parseTestQuery(t, "sort=-password", options)
+	if assert.Error(t, err) {
+		assert.Equal(t, "query: invalid sort parameter: cannot sort by password", err.Error())
+	}
+
+}
+
+func TestParse_Filters(t *testing.T) {
+
+	options := NewOptions(OffsetMode)
+	options.FilterableFields = []string{"name", "age", "status"}
+
+	q, err := parseTestQuery(t, "name=Mat&age[gte]=18&age[lt]=65&status[in]=open,pending&password=secret", options)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Filter{
+			{"name", Equal, "Mat"},
+			{"age", GreaterThanOrEqual, "18"},
+			{"age", LessThan, "65"},
+			{"status", In, "open,pending"},
+		}, q.Filters)
+
+		assert.Equal(t, 2, len(q.FiltersFor("age")))
+		assert.Equal(t, []string{"open", "pending"}, q.FiltersFor("status")[0].Values())
+	}
+
+	// no filterable fields means no filters
+	q, _ = parseTestQuery(t, "name=Mat", nil)
+	assert.Nil(t, q.Filters)
+
+}
+
+func TestFromContext(t *testing.T) {
+
+	ctx := context_test.MakeTestContextWithPath("people?page=2&limit=10&sort=-age&name=Mat")
+
+	q, err := FromContext(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 10, q.Offset)
+		assert.Equal(t, []SortField{{Field: "age", Descending: true}}, q.Sort)
+		assert.Nil(t, q.Filters)
+	}
+
+	options := NewOptions(OffsetMode)
+	options.FilterableFields = []string{"name"}
+	options.SortableFields = []string{"name"}
+	ctx.Data().Set(context.DataKeyListQueryOptions, options)
+
+	_, err = FromContext(ctx)
+	assert.Error(t, err)
+
+}
@@ -0,0 +1,98 @@
+package query
+
+// Mode represents the way the client chooses which page of items they want.
+type Mode int
+
+const (
+	// OffsetMode indicates that pages are chosen by number (starting at 1),
+	// or by the offset of the first item.
+	OffsetMode Mode = iota
+	// CursorMode indicates that pages are chosen by an opaque cursor, given
+	// to the client along with the previous page.
+	CursorMode
+)
+
+const (
+	// DefaultLimit is the default number of items in a page.
+	DefaultLimit int = 20
+	// DefaultMaxLimit is the default maximum number of items a client may ask for.
+	DefaultMaxLimit int = 100
+
+	// DefaultPageParameter is the default name of the page number parameter.
+	DefaultPageParameter string = "page"
+	// DefaultOffsetParameter is the default name of the offset parameter.
+	DefaultOffsetParameter string = "offset"
+	// DefaultLimitParameter is the default name of the limit parameter.
+	DefaultLimitParameter string = "limit"
+	// DefaultCursorParameter is the default name of the cursor parameter.
+	DefaultCursorParameter string = "cursor"
+	// DefaultSortParameter is the default name of the sort parameter.
+	DefaultSortParameter string = "sort"
+)
+
+// Options control how ListQuery objects are parsed.
+type Options struct {
+
+	// Mode is the way clients choose which page they want.
+	Mode Mode
+
+	// DefaultLimit is the number of items in a page if the client doesn't say.
+	// If it is zero or less, the DefaultLimit constant is used.
+	DefaultLimit int
+
+	// MaxLimit is the maximum number of items in a page.  Clients asking for
+	// more will get MaxLimit items.
+	MaxLimit int
+
+	// SortableFields are the fields that clients may sort by.  If nil, any field
+	// may be sorted by.
+	SortableFields []string
+
+	// FilterableFields are the fields that clients may filter on.  Only the query
+	// parameters named after these fields are treated as filters, so if nil, no
+	// filtering is possible.
+	FilterableFields []string
+
+	// PageParameter is the name of the page number parameter.
+	PageParameter string
+	// OffsetParameter is the name of the offset parameter.
+	OffsetParameter string
+	// LimitParameter is the name of the limit parameter.
+	LimitParameter string
+	// CursorParameter is the name of the cursor parameter.
+	CursorParameter string
+	// SortParameter is the name of the sort parameter.
+	SortParameter string
+}
+
+// NewOptions makes a new Options object with the default settings, using the
+// specified mode.
+func NewOptions(mode Mode) *Options {
+	return &Options{
+		Mode:            mode,
+		DefaultLimit:    DefaultLimit,
+		MaxLimit:        DefaultMaxLimit,
+		PageParameter:   DefaultPageParameter,
+		OffsetParameter: DefaultOffsetParameter,
+		LimitParameter:  DefaultLimitParameter,
+		CursorParameter: DefaultCursorParameter,
+		SortParameter:   DefaultSortParameter}
+}
+
+// DefaultOptions are the Options used when none have been set.
+var DefaultOptions *Options = NewOptions(OffsetMode)
+
+// sortable gets whether the specified field may be sorted by.
+func (o *Options) sortable(field string) bool {
+	return o.SortableFields == nil || contains(o.SortableFields, field)
+}
+
+// contains gets whether the list contains the specified string.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// UnknownTotal is the Total of a Page when the total number of items isn't known.
+const UnknownTotal int = -1
+
+// Page describes the page of items being returned for a ListQuery, so that
+// responders can tell clients how to get the other pages.
+type Page struct {
+
+	// Query is the ListQuery the page is for.
+	Query *ListQuery
+
+	// Total is the total number of items in the list, or UnknownTotal.
+	Total int
+
+	// NextCursor is the cursor of the next page in CursorMode, or an empty string
+	// if this is the last page.
+	NextCursor string
+
+	// PreviousCursor is the cursor of the previous page in CursorMode, or an empty
+	// string if this is the first page.
+	PreviousCursor string
+}
+
+// Link represents a link to another page, as written in Link headers (see RFC 8288).
+type Link struct {
+	// Rel is the relation of the page to this one ("first", "prev", "next" or "last").
+	Rel string
+	// URL is the URL of the page.
+	URL string
+}
+
+// OffsetPage makes a Page for this query in OffsetMode.  total is the total number
+// of items in the list; without it (i.e. UnknownTotal) the next and last pages
+// cannot be linked to.
+func (q *ListQuery) OffsetPage(total int) *Page {
+	return &Page{Query: q, Total: total}
+}
+
+// CursorPage makes a Page for this query in CursorMode, with the cursors of the
+// next and previous pages (empty strings if there are no such pages).
+func (q *ListQuery) CursorPage(next, previous string) *Page {
+	return &Page{Query: q, Total: UnknownTotal, NextCursor: next, PreviousCursor: previous}
+}
+
+// Pages gets the number of pages in OffsetMode, or UnknownTotal.  If the query
+// has no Limit, all the items are in one page.
+func (p *Page) Pages() int {
+	if p.Total < 0 {
+		return UnknownTotal
+	}
+	if p.Query.Limit < 1 {
+		if p.Total == 0 {
+			return 0
+		}
+		return 1
+	}
+	return (p.Total + p.Query.Limit - 1) / p.Query.Limit
+}
+
+// Metadata gets the information about the page to put in response envelopes.
+func (p *Page) Metadata() map[string]interface{} {
+
+	metadata := map[string]interface{}{"limit": p.Query.Limit}
+
+	if p.Total >= 0 {
+		metadata["total"] = p.Total
+	}
+
+	if p.Query.Options().Mode == CursorMode {
+		if len(p.NextCursor) > 0 {
+			metadata["next"] = p.NextCursor
+		}
+		if len(p.PreviousCursor) > 0 {
+			metadata["prev"] = p.PreviousCursor
+		}
+	} else {
+		metadata["page"] = p.Query.Page
+		metadata["offset"] = p.Query.Offset
+		if p.Total >= 0 {
+			metadata["pages"] = p.Pages()
+		}
+	}
+
+	return metadata
+
+}
+
+// Links gets the links to the other pages, based on the URL of the request for
+// this one.
+func (p *Page) Links(u *url.URL) []Link {
+
+	var links []Link
+	q := p.Query
+	options := q.Options()
+
+	if options.Mode == CursorMode {
+
+		if len(p.PreviousCursor) > 0 {
+			links = append(links, Link{"prev", p.pageURL(u, options.CursorParameter, p.PreviousCursor)})
+		}
+		if len(p.NextCursor) > 0 {
+			links = append(links, Link{"next", p.pageURL(u, options.CursorParameter, p.NextCursor)})
+		}
+
+		return links
+	}
+
+	links = append(links, Link{"first", p.pageURL(u, options.PageParameter, "1")})
+
+	if q.Page > 1 {
+		links = append(links, Link{"prev", p.pageURL(u, options.PageParameter, strconv.Itoa(q.Page-1))})
+	}
+
+	if p.Total >= 0 {
+		if q.Page < p.Pages() {
+			links = append(links, Link{"next", p.pageURL(u, options.PageParameter, strconv.Itoa(q.Page+1))})
+		}
+		last := p.Pages()
+		if last < 1 {
+			last = 1
+		}
+		links = append(links, Link{"last", p.pageURL(u, options.PageParameter, strconv.Itoa(last))})
+	}
+
+	return links
+
+}
+
+// LinkHeader gets the value of the Link header for the links to the other pages,
+// or an empty string if there are none.
+func (p *Page) LinkHeader(u *url.URL) string {
+
+	links := p.Links(u)
+	values := make([]string, len(links))
+	for i, link := range links {
+		values[i] = fmt.Sprintf("<%s>; rel=\"%s\"", link.URL, link.Rel)
+	}
+
+	return strings.Join(values, ", ")
+
+}
+
+// pageURL gets a copy of the specified URL with the page parameter changed.
+func (p *Page) pageURL(u *url.URL, parameter, value string) string {
+
+	options := p.Query.Options()
+
+	values := u.Query()
+	values.Del(options.OffsetParameter)
+	values.Del(options.PageParameter)
+	values.Del(options.CursorParameter)
+	values.Set(parameter, value)
+	values.Set(options.LimitParameter, strconv.Itoa(p.Query.Limit))
+
+	pageURL := *u
+	pageURL.RawQuery = values.Encode()
+
+	return pageURL.String()
+
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestPage_Offset(t *testing.T) {
+
+	u, _ := url.Parse("/people?page=2&limit=10&name=Mat")
+	q, _ := Parse(u.Query(), nil)
+	page := q.OffsetPage(35)
+
+	assert.Equal(t, 4, page.Pages())
+	assert.Equal(t, map[string]interface{}{"limit": 10, "page": 2, "offset": 10, "total": 35, "pages": 4}, page.Metadata())
+
+	assert.Equal(t, []Link{
+		{"first", "/people?limit=10&name=Mat&page=1"},
+		{"prev", "/people?limit=10&name=Mat&page=1"},
+		{"next", "/people?limit=10&name=Mat&page=3"},
+		{"last", "/people?limit=10&name=Mat&page=4"},
+	}, page.Links(u))
+
+	assert.Equal(t, `</people?limit=10&name=Mat&page=1>; rel="first", </people?limit=10&name=Mat&page=1>; rel="prev", </people?limit=10&name=Mat&page=3>; rel="next", </people?limit=10&name=Mat&page=4>; rel="last"`, page.LinkHeader(u))
+
+}
+
+func TestPage_Offset_UnknownTotal(t *testing.T) {
+
+	u, _ := url.Parse("/people?offset=5&limit=5")
+	q, _ := Parse(u.Query(), nil)
+	page := q.OffsetPage(UnknownTotal)
+
+	assert.Equal(t, UnknownTotal, page.Pages())
+	assert.Equal(t, map[string]interface{}{"limit": 5, "page": 2, "offset": 5}, page.Metadata())
+	assert.Equal(t, []Link{
+		{"first", "/people?limit=5&page=1"},
+		{"prev", "/people?limit=5&page=1"},
+	}, page.Links(u))
+
+}
+
+func TestPage_QueryWithoutOptions(t *testing.T) {
+
+	u, _ := url.Parse("/people")
+	page := (&ListQuery{Limit: 10, Page: 2, Offset: 10}).OffsetPage(25)
+
+	assert.Equal(t, map[string]interface{}{"limit": 10, "page": 2, "offset": 10, "total": 25, "pages": 3}, page.Metadata())
+	assert.Equal(t, []Link{
+		{"first", "/people?limit=10&page=1"},
+		{"prev", "/people?limit=10&page=1"},
+		{"next", "/people?limit=10&page=3"},
+		{"last", "/people?limit=10&page=3"},
+	}, page.Links(u))
+
+}
+
+func TestPage_Pages_NoLimit(t *testing.T) {
+
+	q := &ListQuery{Page: 1, options: DefaultOptions}
+
+	assert.Equal(t, 1, q.OffsetPage(35).Pages())
+	assert.Equal(t, 0, q.OffsetPage(0).Pages())
+
+}
+
+func TestPage_Cursor(t *testing.T) {
+
+	u, _ := url.Parse("/people?cursor=b")
+	q, _ := Parse(u.Query(), NewOptions(CursorMode))
+
+	page := q.CursorPage("c", "a")
+	assert.Equal(t, map[string]interface{}{"limit": DefaultLimit, "next": "c", "prev": "a"}, page.Metadata())
+	assert.Equal(t, []Link{
+		{"prev", "/people?cursor=a&limit=20"},
+		{"next", "/people?cursor=c&limit=20"},
+	}, page.Links(u))
+
+	page = q.CursorPage("", "")
+	assert.Equal(t, "", page.LinkHeader(u))
+
+}
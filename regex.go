@@ -10,11 +10,13 @@ import (
 */
 
 // RegexPath returns a MatcherFunc that mathces the path based on the specified
-// Regex pattern.
+// Regex pattern.  The values of named capture groups become path parameters.
 //
 // To match a path that contains only numbers, you could do:
 //
 //     goweb.Map(executionFunc, goweb.RegexPath(`^[0-9]+$`))
+//
+// To have invalid patterns reported by Map, pass a handlers.Regexp as the path instead.
 func RegexPath(regexpPattern string) handlers.MatcherFunc {
 	return handlers.RegexPath(regexpPattern)
 }
@@ -2,7 +2,9 @@ package responders
 
 import (
 	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/bulk"
 	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/query"
 )
 
 const (
@@ -72,3 +74,58 @@ type APIResponder interface {
 	// RespondWithError responds with the specified error message and status code.
 	RespondWithError(ctx context.Context, status int, err string) error
 }
+
+// PageResponder represents an APIResponder that can respond with pages of data
+// itself.  See RespondWithPage.
+type PageResponder interface {
+
+	// RespondWithPage responds with the specified page of data and a 200 StatusOK
+	// response, telling the client about the other pages with a Link header (and
+	// in the response object, if there is one).
+	RespondWithPage(ctx context.Context, data interface{}, page *query.Page) error
+}
+
+// BulkResponder represents an APIResponder that can respond with the results of
+// bulk requests itself.  See RespondWithBulk.
+type BulkResponder interface {
+
+	// RespondWithBulk responds with the results of a bulk request, and a 207
+	// StatusMultiStatus response.
+	RespondWithBulk(ctx context.Context, response *bulk.Response) error
+}
+
+// RespondWithPage responds with the specified page of data and a 200 StatusOK
+// response, using the responder's RespondWithPage method if it is a
+// PageResponder.
+//
+// Otherwise, the links to the other pages are written in a Link header, and the
+// data is written with RespondWithData.
+func RespondWithPage(responder APIResponder, ctx context.Context, data interface{}, page *query.Page) error {
+
+	if pageResponder, ok := responder.(PageResponder); ok {
+		return pageResponder.RespondWithPage(ctx, data, page)
+	}
+
+	if links := page.LinkHeader(ctx.HttpRequest().URL); len(links) > 0 {
+		ctx.HttpResponseWriter().Header().Set("Link", links)
+	}
+
+	return responder.RespondWithData(ctx, data)
+
+}
+
+// RespondWithBulk responds with the results of a bulk request, and a 207
+// StatusMultiStatus response, using the responder's RespondWithBulk method if it
+// is a BulkResponder.
+//
+// Otherwise, the objects for each of the results are written with
+// WriteResponseObject.
+func RespondWithBulk(responder APIResponder, ctx context.Context, response *bulk.Response) error {
+
+	if bulkResponder, ok := responder.(BulkResponder); ok {
+		return bulkResponder.RespondWithBulk(ctx, response)
+	}
+
+	return responder.WriteResponseObject(ctx, response.Status(), response.Objects())
+
+}
@@ -0,0 +1,68 @@
+package responders
+
+import (
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/bulk"
+	"github.com/stretchr/goweb/query"
+	context_test "github.com/stretchr/goweb/webcontext/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// basicAPIResponder is an APIResponder that is neither a PageResponder nor a
+// BulkResponder.
+type basicAPIResponder struct {
+	APIResponder
+}
+
+func TestGowebAPIResponder_OptionalInterfaces(t *testing.T) {
+
+	assert.Implements(t, (*PageResponder)(nil), new(GowebAPIResponder))
+	assert.Implements(t, (*BulkResponder)(nil), new(GowebAPIResponder))
+
+}
+
+func TestRespondWithPage(t *testing.T) {
+
+	API := NewGowebAPIResponder(codecsservices.NewWebCodecService(), new(GowebHTTPResponder))
+
+	ctx := context_test.MakeTestContextWithPath("people?page=2&limit=1")
+	q, _ := query.FromContext(ctx)
+	RespondWithPage(API, ctx, []string{"Mat"}, q.OffsetPage(2))
+
+	assert.Contains(t, context_test.TestResponseWriter.Output, "\"p\":{")
+	assert.Contains(t, context_test.TestResponseWriter.Header().Get("Link"), "rel=\"first\"")
+
+	// other responders just get the Link header
+	ctx = context_test.MakeTestContextWithPath("people?page=2&limit=1")
+	q, _ = query.FromContext(ctx)
+	RespondWithPage(&basicAPIResponder{API}, ctx, []string{"Mat"}, q.OffsetPage(2))
+
+	assert.Equal(t, "{\"d\":[\"Mat\"],\"s\":200}", context_test.TestResponseWriter.Output)
+	assert.Contains(t, context_test.TestResponseWriter.Header().Get("Link"), "rel=\"first\"")
+
+}
+
+func TestRespondWithBulk(t *testing.T) {
+
+	API := NewGowebAPIResponder(codecsservices.NewWebCodecService(), new(GowebHTTPResponder))
+	response := &bulk.Response{Results: []bulk.Result{
+		bulk.Success("1", 200, nil),
+		bulk.Failure("2", 404, errors.New("not found")),
+	}}
+
+	ctx := context_test.MakeTestContext()
+	RespondWithBulk(API, ctx, response)
+
+	assert.Equal(t, 207, context_test.TestResponseWriter.StatusCode)
+	assert.Equal(t, "{\"d\":[{\"id\":\"1\",\"status\":200},{\"errors\":[\"not found\"],\"id\":\"2\",\"status\":404}],\"s\":207}", context_test.TestResponseWriter.Output)
+
+	// other responders write the results without an envelope
+	ctx = context_test.MakeTestContext()
+	RespondWithBulk(&basicAPIResponder{API}, ctx, response)
+
+	assert.Equal(t, 207, context_test.TestResponseWriter.StatusCode)
+	assert.Equal(t, "[{\"id\":\"1\",\"status\":200},{\"errors\":[\"not found\"],\"id\":\"2\",\"status\":404}]", context_test.TestResponseWriter.Output)
+
+}
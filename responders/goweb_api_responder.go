@@ -4,7 +4,9 @@ import (
 	"github.com/stretchr/codecs"
 	"github.com/stretchr/codecs/constants"
 	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/bulk"
 	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/query"
 	"net/http"
 )
 
@@ -15,6 +17,10 @@ const (
 	DefaultStandardFieldStatusKey string = "s"
 	// DefaultStandardFieldErrorsKey is the default response object field for the errors.
 	DefaultStandardFieldErrorsKey string = "e"
+	// DefaultStandardFieldRequestIDKey is the default response object field for the request ID.
+	DefaultStandardFieldRequestIDKey string = "r"
+	// DefaultStandardFieldPageKey is the default response object field for the page metadata.
+	DefaultStandardFieldPageKey string = "p"
 )
 
 type GowebAPIResponder struct {
@@ -41,8 +47,24 @@ type GowebAPIResponder struct {
 	// StandardFieldErrorsKey is the response object field name for the errors.
 	StandardFieldErrorsKey string
 
+	// StandardFieldRequestIDKey is the response object field name for the ID of the
+	// request (see handlers.RequestIDHandler).  The field is only included when the
+	// request has an ID.  Set to an empty string to never include it.
+	StandardFieldRequestIDKey string
+
+	// StandardFieldPageKey is the response object field name for the metadata about
+	// the page of data, when responding with RespondWithPage.  Set to an empty string
+	// to never include it.
+	StandardFieldPageKey string
+
 	// AlwaysEnvelopeResponse tells Goweb whether to envelope the response or not
 	AlwaysEnvelopResponse bool
+
+	// ResponseValidator, if set, is called with every response object before it is
+	// written.  If it returns an error, nothing is written and the error is returned
+	// instead.  It is intended for tests, to check responses match the API
+	// description (see openapi.Validator.ValidateResponse).
+	ResponseValidator func(ctx context.Context, status int, responseObject interface{}) error
 }
 
 func NewGowebAPIResponder(codecService codecsservices.CodecService, httpResponder HTTPResponder) *GowebAPIResponder {
@@ -52,6 +74,8 @@ func NewGowebAPIResponder(codecService codecsservices.CodecService, httpResponde
 	api.StandardFieldDataKey = DefaultStandardFieldDataKey
 	api.StandardFieldStatusKey = DefaultStandardFieldStatusKey
 	api.StandardFieldErrorsKey = DefaultStandardFieldErrorsKey
+	api.StandardFieldRequestIDKey = DefaultStandardFieldRequestIDKey
+	api.StandardFieldPageKey = DefaultStandardFieldPageKey
 	api.AlwaysEnvelopResponse = true // True because of existing code, should be changed to false when breaking of backward compatibility is allowed
 
 	return api
@@ -97,6 +121,12 @@ func (a *GowebAPIResponder) SetStandardResponseObjectTransformer(transformer fun
 // the API, but other Respond* methods are recommended.
 func (a *GowebAPIResponder) WriteResponseObject(ctx context.Context, status int, responseObject interface{}) error {
 
+	if a.ResponseValidator != nil {
+		if err := a.ResponseValidator(ctx, status, responseObject); err != nil {
+			return err
+		}
+	}
+
 	service := a.GetCodecService()
 
 	acceptHeader := ctx.HttpRequest().Header.Get("Accept")
@@ -132,6 +162,12 @@ func (a *GowebAPIResponder) WriteResponseObject(ctx context.Context, status int,
 
 // Responds to the Context with the specified status, data and errors.
 func (a *GowebAPIResponder) Respond(ctx context.Context, status int, data interface{}, errors []string) error {
+	return a.respond(ctx, status, data, errors, nil)
+}
+
+// respond responds to the Context with the specified status, data, errors and
+// page metadata (which may be nil).
+func (a *GowebAPIResponder) respond(ctx context.Context, status int, data interface{}, errors []string, pageMetadata map[string]interface{}) error {
 
 	if data != nil {
 
@@ -158,6 +194,16 @@ func (a *GowebAPIResponder) Respond(ctx context.Context, status int, data interf
 			sro[a.StandardFieldErrorsKey] = errors
 		}
 
+		if len(a.StandardFieldRequestIDKey) > 0 {
+			if requestID := ctx.Data().Get(context.DataKeyRequestID).Str(); len(requestID) > 0 {
+				sro[a.StandardFieldRequestIDKey] = requestID
+			}
+		}
+
+		if pageMetadata != nil && len(a.StandardFieldPageKey) > 0 {
+			sro[a.StandardFieldPageKey] = pageMetadata
+		}
+
 		data = sro
 	}
 
@@ -182,3 +228,26 @@ func (a *GowebAPIResponder) RespondWithData(ctx context.Context, data interface{
 func (a *GowebAPIResponder) RespondWithError(ctx context.Context, status int, err string) error {
 	return a.Respond(ctx, status, nil, []string{err})
 }
+
+// RespondWithPage responds with the specified page of data and a 200 StatusOK response.
+//
+// Links to the other pages are written in a Link header, and the metadata about the
+// page (see query.Page.Metadata) is included in the response object.
+func (a *GowebAPIResponder) RespondWithPage(ctx context.Context, data interface{}, page *query.Page) error {
+
+	if links := page.LinkHeader(ctx.HttpRequest().URL); len(links) > 0 {
+		ctx.HttpResponseWriter().Header().Set("Link", links)
+	}
+
+	return a.respond(ctx, http.StatusOK, data, nil, page.Metadata())
+
+}
+
+// RespondWithBulk responds with the results of a bulk request, and a 207
+// StatusMultiStatus response.
+//
+// The data is an array containing an object for each operation, with its "id",
+// "status" and any "data" and "errors".
+func (a *GowebAPIResponder) RespondWithBulk(ctx context.Context, response *bulk.Response) error {
+	return a.Respond(ctx, response.Status(), response.Objects(), nil)
+}
@@ -1,8 +1,11 @@
 package responders
 
 import (
+	"errors"
 	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/bulk"
 	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/query"
 	context_test "github.com/stretchr/goweb/webcontext/test"
 	"github.com/stretchr/testify/assert"
 	"net/url"
@@ -28,6 +31,7 @@ func TestNewGowebAPIResponder(t *testing.T) {
 	assert.Equal(t, api.StandardFieldStatusKey, "s")
 	assert.Equal(t, api.StandardFieldDataKey, "d")
 	assert.Equal(t, api.StandardFieldErrorsKey, "e")
+	assert.Equal(t, api.StandardFieldRequestIDKey, "r")
 
 }
 
@@ -131,6 +135,30 @@ func TestRespondWithCustomFieldnames(t *testing.T) {
 
 }
 
+func TestRespondWithRequestID(t *testing.T) {
+
+	http := new(GowebHTTPResponder)
+	codecService := codecsservices.NewWebCodecService()
+	API := NewGowebAPIResponder(codecService, http)
+	ctx := context_test.MakeTestContext()
+	ctx.Data().Set(context.DataKeyRequestID, "abc123")
+	data := map[string]interface{}{"name": "Mat"}
+
+	API.Respond(ctx, 200, data, nil)
+
+	assert.Equal(t, context_test.TestResponseWriter.Output, "{\"d\":{\"name\":\"Mat\"},\"r\":\"abc123\",\"s\":200}")
+
+	// turn it off
+	API.StandardFieldRequestIDKey = ""
+	ctx = context_test.MakeTestContext()
+	ctx.Data().Set(context.DataKeyRequestID, "abc123")
+
+	API.Respond(ctx, 200, data, nil)
+
+	assert.Equal(t, context_test.TestResponseWriter.Output, "{\"d\":{\"name\":\"Mat\"},\"s\":200}")
+
+}
+
 func TestWriteResponseObject(t *testing.T) {
 
 	http := new(GowebHTTPResponder)
@@ -145,6 +173,29 @@ func TestWriteResponseObject(t *testing.T) {
 
 }
 
+func TestWriteResponseObject_ResponseValidator(t *testing.T) {
+
+	http := new(GowebHTTPResponder)
+	codecService := codecsservices.NewWebCodecService()
+	API := NewGowebAPIResponder(codecService, http)
+	ctx := context_test.MakeTestContext()
+
+	var validatedStatus int
+	API.ResponseValidator = func(ctx context.Context, status int, responseObject interface{}) error {
+		validatedStatus = status
+		return errors.New("does not match")
+	}
+
+	err := API.WriteResponseObject(ctx, 201, map[string]interface{}{"name": "Mat"})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "does not match", err.Error())
+	}
+	assert.Equal(t, 201, validatedStatus)
+	assert.Equal(t, "", context_test.TestResponseWriter.Output)
+
+}
+
 // https://github.com/stretchr/goweb/issues/20
 func TestWriteResponseObject_ContentNegotiation_AcceptHeader(t *testing.T) {
 
@@ -298,3 +349,46 @@ func TestAPI_RespondWithError(t *testing.T) {
 	assert.Equal(t, context_test.TestResponseWriter.Output, "{\"e\":[\"error message\"],\"s\":500}")
 
 }
+
+func TestAPI_RespondWithPage(t *testing.T) {
+
+	http := new(GowebHTTPResponder)
+	codecService := codecsservices.NewWebCodecService()
+	API := NewGowebAPIResponder(codecService, http)
+	ctx := context_test.MakeTestContextWithPath("people?page=2&limit=1")
+	q, _ := query.FromContext(ctx)
+
+	API.RespondWithPage(ctx, []string{"Mat"}, q.OffsetPage(2))
+
+	assert.Equal(t, "{\"d\":[\"Mat\"],\"p\":{\"limit\":1,\"offset\":1,\"page\":2,\"pages\":2,\"total\":2},\"s\":200}", context_test.TestResponseWriter.Output)
+	assert.Equal(t, "<http://stretchr.org/people?limit=1&page=1>; rel=\"first\", <http://stretchr.org/people?limit=1&page=1>; rel=\"prev\", <http://stretchr.org/people?limit=1&page=2>; rel=\"last\"", context_test.TestResponseWriter.Header().Get("Link"))
+
+	// without the envelope, only the Link header tells the client about the pages
+	ctx = context_test.MakeTestContextWithPath("people?page=2&limit=1&envelop=false")
+	q, _ = query.FromContext(ctx)
+
+	API.RespondWithPage(ctx, []string{"Mat"}, q.OffsetPage(2))
+
+	assert.Equal(t, "[\"Mat\"]", context_test.TestResponseWriter.Output)
+	assert.Contains(t, context_test.TestResponseWriter.Header().Get("Link"), "envelop=false")
+
+}
+
+func TestAPI_RespondWithBulk(t *testing.T) {
+
+	http := new(GowebHTTPResponder)
+	codecService := codecsservices.NewWebCodecService()
+	API := NewGowebAPIResponder(codecService, http)
+	ctx := context_test.MakeTestContext()
+
+	response := &bulk.Response{Results: []bulk.Result{
+		bulk.Success("1", 200, nil),
+		bulk.Failure("2", 404, errors.New("not found")),
+	}}
+
+	API.RespondWithBulk(ctx, response)
+
+	assert.Equal(t, 207, context_test.TestResponseWriter.StatusCode)
+	assert.Equal(t, "{\"d\":[{\"id\":\"1\",\"status\":200},{\"errors\":[\"not found\"],\"id\":\"2\",\"status\":404}],\"s\":207}", context_test.TestResponseWriter.Output)
+
+}
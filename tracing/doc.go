@@ -0,0 +1,16 @@
+// The tracing package provides distributed tracing for Goweb, propagating trace
+// context using the W3C Trace Context headers (traceparent and tracestate).
+//
+// A Tracer creates a span for each request, and a child span for each handler that
+// runs (including the pre, process and post pipes themselves), and hands finished
+// spans to a SpanExporter.
+//
+// To trace requests, add a Tracer as an observer of your HttpHandler:
+//
+//     tracer := tracing.NewTracer(tracing.NewStdoutExporter())
+//     goweb.DefaultHttpHandler().AddObserver(tracer)
+//
+// Inside your handlers, the current span is available via tracing.CurrentSpan:
+//
+//     tracing.CurrentSpan(ctx).SetAttribute("people.count", len(people))
+package tracing
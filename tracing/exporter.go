@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// SpanExporter represents an object capable of sending finished spans
+// somewhere, such as a tracing backend.
+type SpanExporter interface {
+	// ExportSpan exports a finished span.
+	ExportSpan(span *Span) error
+}
+
+// InMemoryExporter is a SpanExporter that keeps finished spans in memory.
+//
+// It is useful for writing tests.
+type InMemoryExporter struct {
+	mutex sync.Mutex
+	spans []*Span
+}
+
+// NewInMemoryExporter makes a new InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return new(InMemoryExporter)
+}
+
+// ExportSpan keeps the span in memory.
+func (e *InMemoryExporter) ExportSpan(span *Span) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = append(e.spans, span)
+	return nil
+}
+
+// Spans gets the spans that have been exported, in the order in which they
+// finished.
+func (e *InMemoryExporter) Spans() []*Span {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	spans := make([]*Span, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+// Reset forgets all the spans that have been exported.
+func (e *InMemoryExporter) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = nil
+}
+
+// JSONExporter is a SpanExporter that writes each span as a line of JSON.
+type JSONExporter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewJSONExporter makes a new JSONExporter that writes to the specified writer.
+func NewJSONExporter(writer io.Writer) *JSONExporter {
+	return &JSONExporter{writer: writer}
+}
+
+// NewStdoutExporter makes a new JSONExporter that writes to os.Stdout.
+func NewStdoutExporter() *JSONExporter {
+	return NewJSONExporter(os.Stdout)
+}
+
+// ExportSpan writes the span as a line of JSON.
+func (e *JSONExporter) ExportSpan(span *Span) error {
+
+	output, marshalErr := json.Marshal(span)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	_, writeErr := e.writer.Write(append(output, '\n'))
+	return writeErr
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestInMemoryExporter(t *testing.T) {
+
+	e := NewInMemoryExporter()
+	span := newSpan("test", SpanContext{})
+
+	assert.NoError(t, e.ExportSpan(span))
+	if assert.Equal(t, 1, len(e.Spans())) {
+		assert.Equal(t, span, e.Spans()[0])
+	}
+
+	e.Reset()
+	assert.Equal(t, 0, len(e.Spans()))
+
+}
+
+func TestJSONExporter(t *testing.T) {
+
+	var buf bytes.Buffer
+	e := NewJSONExporter(&buf)
+
+	parent := newSpan("parent", SpanContext{})
+	span := newSpan("child", parent.SpanContext())
+	span.SetAttribute("http.status_code", 200)
+
+	assert.NoError(t, e.ExportSpan(span))
+	assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+
+	var output map[string]interface{}
+	if assert.NoError(t, json.Unmarshal(buf.Bytes(), &output)) {
+		assert.Equal(t, "child", output["name"])
+		assert.Equal(t, parent.TraceID.String(), output["trace_id"])
+		assert.Equal(t, span.SpanID.String(), output["span_id"])
+		assert.Equal(t, parent.SpanID.String(), output["parent_span_id"])
+		assert.Equal(t, float64(200), output["attributes"].(map[string]interface{})["http.status_code"])
+	}
+
+}
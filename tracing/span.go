@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Span represents a single timed operation within a trace.
+type Span struct {
+
+	// Name is the name of the operation.
+	Name string `json:"name"`
+
+	// TraceID is the ID of the trace this span belongs to.
+	TraceID TraceID `json:"-"`
+
+	// SpanID is the ID of this span.
+	SpanID SpanID `json:"-"`
+
+	// ParentSpanID is the ID of the parent span, which may be a span in
+	// another service.  It will be invalid for root spans.
+	ParentSpanID SpanID `json:"-"`
+
+	// Flags are the W3C trace-flags.
+	Flags byte `json:"flags"`
+
+	// TraceState is the vendor specific tracestate to propagate.
+	TraceState string `json:"tracestate,omitempty"`
+
+	// Start is when the operation started.
+	Start time.Time `json:"start"`
+
+	// End is when the operation finished.
+	End time.Time `json:"end"`
+
+	// Attributes holds additional information about the operation.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// Error is the error message if the operation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// newSpan makes a new Span with the specified name as a child of the
+// specified parent SpanContext.  If the parent is invalid, the span will be
+// the root of a new trace.
+func newSpan(name string, parent SpanContext) *Span {
+
+	s := new(Span)
+	s.Name = name
+	s.Start = time.Now()
+	s.SpanID = newSpanID()
+	s.Attributes = make(map[string]interface{})
+
+	if parent.IsValid() {
+		s.TraceID = parent.TraceID
+		s.ParentSpanID = parent.SpanID
+		s.Flags = parent.Flags
+		s.TraceState = parent.TraceState
+	} else {
+		s.TraceID = newTraceID()
+		s.Flags = flagSampled
+	}
+
+	return s
+}
+
+// SpanContext gets the SpanContext for this span, that should be propagated
+// to other services.
+func (s *Span) SpanContext() SpanContext {
+	return SpanContext{TraceID: s.TraceID, SpanID: s.SpanID, Flags: s.Flags, TraceState: s.TraceState}
+}
+
+// SetAttribute sets an attribute on the span.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.Attributes[key] = value
+}
+
+// SetError records the error on the span.
+func (s *Span) SetError(err error) {
+	if err != nil {
+		s.Error = err.Error()
+	}
+}
+
+// Duration gets how long the operation took, or how long it has taken so
+// far if it hasn't finished yet.
+func (s *Span) Duration() time.Duration {
+	if s.End.IsZero() {
+		return time.Since(s.Start)
+	}
+	return s.End.Sub(s.Start)
+}
+
+// MarshalJSON includes the IDs in their hex form.
+func (s *Span) MarshalJSON() ([]byte, error) {
+
+	// alias to avoid recursion
+	type span Span
+
+	var parentSpanID string
+	if s.ParentSpanID.IsValid() {
+		parentSpanID = s.ParentSpanID.String()
+	}
+
+	return json.Marshal(struct {
+		TraceID      string `json:"trace_id"`
+		SpanID       string `json:"span_id"`
+		ParentSpanID string `json:"parent_span_id,omitempty"`
+		*span
+	}{s.TraceID.String(), s.SpanID.String(), parentSpanID, (*span)(s)})
+
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"net/http"
+)
+
+// statusRecorder is an http.ResponseWriter that remembers the status code
+// written to it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// newStatusRecorder makes a new statusRecorder that writes to the specified
+// http.ResponseWriter.
+func newStatusRecorder(responseWriter http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: responseWriter}
+}
+
+// WriteHeader records the status code and passes it on.
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the data, recording an implicit http.StatusOK if no status
+// code has been written yet.
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// Status gets the status code that was written, or http.StatusOK if
+// nothing has been written.
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
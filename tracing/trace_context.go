@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// TraceparentHeader is the name of the W3C traceparent header.
+	TraceparentHeader string = "traceparent"
+
+	// TracestateHeader is the name of the W3C tracestate header.
+	TracestateHeader string = "tracestate"
+
+	// supportedVersion is the traceparent version this package writes.
+	supportedVersion string = "00"
+
+	// flagSampled is the trace-flags bit indicating the trace is sampled.
+	flagSampled byte = 0x01
+
+	// maxTracestateMembers is the maximum number of list members allowed
+	// in the tracestate header.
+	maxTracestateMembers int = 32
+)
+
+// ErrInvalidTraceparent is returned by ParseTraceparent when the value is not
+// a valid traceparent header.
+var ErrInvalidTraceparent = errors.New("tracing: invalid traceparent")
+
+// TraceID identifies a whole trace.
+type TraceID [16]byte
+
+// String gets the lowercase hex representation of the TraceID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid gets whether the TraceID is valid (i.e. not all zeros).
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID identifies a single span within a trace.
+type SpanID [8]byte
+
+// String gets the lowercase hex representation of the SpanID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid gets whether the SpanID is valid (i.e. not all zeros).
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext holds the parts of a span that are propagated between services.
+type SpanContext struct {
+	// TraceID is the ID of the trace the span belongs to.
+	TraceID TraceID
+	// SpanID is the ID of the span.
+	SpanID SpanID
+	// Flags are the W3C trace-flags.
+	Flags byte
+	// TraceState is the vendor specific tracestate header value.
+	TraceState string
+}
+
+// IsValid gets whether the SpanContext has a valid TraceID and SpanID.
+func (c SpanContext) IsValid() bool {
+	return c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+
+// IsSampled gets whether the sampled flag is set.
+func (c SpanContext) IsSampled() bool {
+	return c.Flags&flagSampled == flagSampled
+}
+
+// Traceparent gets the traceparent header value for this SpanContext.
+func (c SpanContext) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", supportedVersion, c.TraceID, c.SpanID, c.Flags)
+}
+
+// ParseTraceparent parses a traceparent header value.
+//
+// Values of future versions are accepted as long as they begin with a
+// valid version 00 value, as required by the W3C specification.
+func ParseTraceparent(value string) (SpanContext, error) {
+
+	var spanContext SpanContext
+
+	value = strings.TrimSpace(value)
+
+	// version-traceid-parentid-flags is 55 characters
+	if len(value) < 55 || value != strings.ToLower(value) {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	version, versionErr := hex.DecodeString(value[0:2])
+	if versionErr != nil || version[0] == 0xff {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	if version[0] == 0x00 && len(value) != 55 {
+		return spanContext, ErrInvalidTraceparent
+	}
+	if len(value) > 55 && value[55] != '-' {
+		return spanContext, ErrInvalidTraceparent
+	}
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	if _, err := hex.Decode(spanContext.TraceID[:], []byte(value[3:35])); err != nil {
+		return spanContext, ErrInvalidTraceparent
+	}
+	if _, err := hex.Decode(spanContext.SpanID[:], []byte(value[36:52])); err != nil {
+		return spanContext, ErrInvalidTraceparent
+	}
+	flags, flagsErr := hex.DecodeString(value[53:55])
+	if flagsErr != nil {
+		return spanContext, ErrInvalidTraceparent
+	}
+	spanContext.Flags = flags[0]
+
+	if !spanContext.IsValid() {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	return spanContext, nil
+}
+
+// cleanTracestate gets the tracestate header value to propagate, or an empty
+// string if the value is not acceptable.
+func cleanTracestate(value string) string {
+
+	var members []string
+
+	for _, member := range strings.Split(value, ",") {
+		member = strings.TrimSpace(member)
+		if len(member) == 0 {
+			continue
+		}
+		if equals := strings.Index(member, "="); equals < 1 || equals == len(member)-1 {
+			// malformed list members invalidate the whole header
+			return ""
+		}
+		members = append(members, member)
+	}
+
+	if len(members) > maxTracestateMembers {
+		return ""
+	}
+
+	return strings.Join(members, ",")
+}
+
+// newTraceID makes a new random TraceID.
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// newSpanID makes a new random SpanID.
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
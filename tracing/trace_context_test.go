@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+
+	c, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", c.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", c.SpanID.String())
+		assert.True(t, c.IsValid())
+		assert.True(t, c.IsSampled())
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", c.Traceparent())
+	}
+
+	c, err = ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if assert.NoError(t, err) {
+		assert.False(t, c.IsSampled())
+	}
+
+	// future versions may have extra fields
+	c, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "00f067aa0ba902b7", c.SpanID.String())
+	}
+
+}
+
+func TestParseTraceparent_Invalid(t *testing.T) {
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",
+	}
+
+	for _, value := range invalid {
+		_, err := ParseTraceparent(value)
+		assert.Equal(t, ErrInvalidTraceparent, err, value)
+	}
+
+}
+
+func TestCleanTracestate(t *testing.T) {
+
+	assert.Equal(t, "", cleanTracestate(""))
+	assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", cleanTracestate("congo=t61rcWkgMzE, rojo=00f067aa0ba902b7"))
+	assert.Equal(t, "congo=t61rcWkgMzE", cleanTracestate("congo=t61rcWkgMzE,,"))
+	assert.Equal(t, "", cleanTracestate("congo=t61rcWkgMzE,nonsense"))
+
+}
+
+func TestNewIDs(t *testing.T) {
+
+	assert.True(t, newTraceID().IsValid())
+	assert.True(t, newSpanID().IsValid())
+	assert.NotEqual(t, newSpanID(), newSpanID())
+
+}
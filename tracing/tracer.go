@@ -0,0 +1,190 @@
+package tracing
+
+import (
+	"fmt"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// dataKeyRequestTrace is the data key for the requestTrace of the current request.
+	dataKeyRequestTrace string = "requesttrace"
+)
+
+var (
+	// PhaseNames are the span names given to the pre, process and post pipes
+	// of the HttpHandler.
+	PhaseNames []string = []string{"pre", "process", "post"}
+)
+
+// requestTrace keeps track of the spans for a single request.
+type requestTrace struct {
+	// spans is the stack of open spans, starting with the request span.
+	spans []*Span
+	// phases counts the pipes that have started directly beneath the
+	// request span.
+	phases int
+	// recorder records the response status code.
+	recorder *statusRecorder
+}
+
+// current gets the innermost open span.
+func (t *requestTrace) current() *Span {
+	return t.spans[len(t.spans)-1]
+}
+
+// Tracer creates spans for requests, and the handlers that handle them.
+//
+// Tracer is a handlers.HandlerObserver, so to trace requests just add it to the
+// HttpHandler:
+//
+//     handler.AddObserver(tracer)
+//
+// When a request starts, the Tracer reads the incoming traceparent and tracestate
+// headers (if present and valid) and starts a span for the request as a child of
+// the remote parent.  The traceparent and tracestate headers are written to the
+// response so clients can correlate their requests.
+type Tracer struct {
+	// Exporter is the SpanExporter that finished spans are handed to.
+	Exporter SpanExporter
+}
+
+// NewTracer makes a new Tracer that exports spans to the specified SpanExporter.
+func NewTracer(exporter SpanExporter) *Tracer {
+	return &Tracer{Exporter: exporter}
+}
+
+// CurrentSpan gets the innermost span for the request in the specified context,
+// or nil if the request is not being traced.
+func CurrentSpan(ctx context.Context) *Span {
+	span, _ := ctx.Data().Get(context.DataKeySpan).Data().(*Span)
+	return span
+}
+
+// traceFor gets the requestTrace for the specified context.
+func traceFor(ctx context.Context) *requestTrace {
+	trace, _ := ctx.Data().Get(dataKeyRequestTrace).Data().(*requestTrace)
+	return trace
+}
+
+// RequestStarted starts the request span.
+func (t *Tracer) RequestStarted(ctx context.Context) {
+
+	request := ctx.HttpRequest()
+
+	// continue the trace from the client if we can
+	parent, parseErr := ParseTraceparent(request.Header.Get(TraceparentHeader))
+	if parseErr == nil {
+		parent.TraceState = cleanTracestate(strings.Join(request.Header[http.CanonicalHeaderKey(TracestateHeader)], ","))
+	}
+
+	span := newSpan(fmt.Sprintf("%s %s", ctx.MethodString(), ctx.Path().RawPath), parent)
+	span.SetAttribute("http.method", ctx.MethodString())
+	span.SetAttribute("http.target", request.URL.RequestURI())
+
+	trace := &requestTrace{spans: []*Span{span}}
+	trace.recorder = newStatusRecorder(ctx.HttpResponseWriter())
+	ctx.SetHttpResponseWriter(trace.recorder)
+
+	ctx.Data().Set(dataKeyRequestTrace, trace)
+	ctx.Data().Set(context.DataKeySpan, span)
+
+	// tell the client about the trace
+	header := ctx.HttpResponseWriter().Header()
+	header.Set(TraceparentHeader, span.SpanContext().Traceparent())
+	if len(span.TraceState) > 0 {
+		header.Set(TracestateHeader, span.TraceState)
+	}
+
+}
+
+// HandlerStarted starts a span for the handler.
+func (t *Tracer) HandlerStarted(ctx context.Context, handler handlers.Handler) {
+
+	trace := traceFor(ctx)
+	if trace == nil {
+		return
+	}
+
+	var name string
+	switch h := handler.(type) {
+	case handlers.Pipe:
+		if len(trace.spans) == 1 && trace.phases < len(PhaseNames) {
+			name = PhaseNames[trace.phases]
+		} else {
+			name = "pipe"
+		}
+		if len(trace.spans) == 1 {
+			trace.phases++
+		}
+	case *handlers.PathMatchHandler:
+		if len(h.HttpMethods) > 0 {
+			name = fmt.Sprintf("%s %s", strings.Join(h.HttpMethods, "|"), h.PathPattern.RawPath)
+		} else {
+			name = h.PathPattern.RawPath
+		}
+	default:
+		name = fmt.Sprintf("%T", handler)
+	}
+
+	span := newSpan(name, trace.current().SpanContext())
+
+	if pathMatchHandler, ok := handler.(*handlers.PathMatchHandler); ok {
+		span.SetAttribute("http.route", pathMatchHandler.PathPattern.RawPath)
+		if len(pathMatchHandler.Description) > 0 {
+			span.SetAttribute("goweb.description", pathMatchHandler.Description)
+		}
+	}
+
+	trace.spans = append(trace.spans, span)
+	ctx.Data().Set(context.DataKeySpan, span)
+
+}
+
+// HandlerFinished finishes the handler's span.
+func (t *Tracer) HandlerFinished(ctx context.Context, handler handlers.Handler, err error) {
+
+	trace := traceFor(ctx)
+	if trace == nil || len(trace.spans) < 2 {
+		return
+	}
+
+	span := trace.current()
+	trace.spans = trace.spans[:len(trace.spans)-1]
+	ctx.Data().Set(context.DataKeySpan, trace.current())
+
+	span.SetError(err)
+	t.finish(span)
+
+}
+
+// RequestFinished finishes the request span.
+func (t *Tracer) RequestFinished(ctx context.Context, err error) {
+
+	trace := traceFor(ctx)
+	if trace == nil {
+		return
+	}
+
+	span := trace.spans[0]
+
+	if route := ctx.Data().Get(context.DataKeyRoute).Str(); len(route) > 0 {
+		span.SetAttribute("http.route", route)
+	}
+	span.SetAttribute("http.status_code", trace.recorder.Status())
+	span.SetError(err)
+
+	t.finish(span)
+
+}
+
+// finish ends the span and exports it.
+func (t *Tracer) finish(span *Span) {
+	span.End = time.Now()
+	if t.Exporter != nil {
+		t.Exporter.ExportSpan(span)
+	}
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"errors"
+	codecsservices "github.com/stretchr/codecs/services"
+	"github.com/stretchr/goweb/context"
+	"github.com/stretchr/goweb/handlers"
+	"github.com/stretchr/testify/assert"
+	http_test "github.com/stretchr/testify/http"
+	"net/http"
+	"testing"
+)
+
+func TestTracer_Interface(t *testing.T) {
+
+	assert.Implements(t, (*handlers.HandlerObserver)(nil), new(Tracer))
+
+}
+
+func TestTracer_CreatesSpans(t *testing.T) {
+
+	exporter := NewInMemoryExporter()
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.AddObserver(NewTracer(exporter))
+
+	var currentSpan *Span
+	h.Map("GET", "people/{id}", func(c context.Context) error {
+		currentSpan = CurrentSpan(c)
+		c.HttpResponseWriter().WriteHeader(http.StatusCreated)
+		return nil
+	})
+
+	request, _ := http.NewRequest("GET", "http://stretchr.org/people/123", nil)
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	spans := exporter.Spans()
+
+	// route, pre, process, post, request
+	if assert.Equal(t, 5, len(spans)) {
+
+		root := spans[4]
+		assert.Equal(t, "GET people/123", root.Name)
+		assert.False(t, root.ParentSpanID.IsValid())
+		assert.Equal(t, "people/{id}", root.Attributes["http.route"])
+		assert.Equal(t, http.StatusCreated, root.Attributes["http.status_code"])
+		assert.False(t, root.End.IsZero())
+
+		assert.Equal(t, "pre", spans[0].Name)
+		assert.Equal(t, "GET people/{id}", spans[1].Name)
+		assert.Equal(t, "process", spans[2].Name)
+		assert.Equal(t, "post", spans[3].Name)
+
+		assert.Equal(t, root.SpanID, spans[2].ParentSpanID)
+		assert.Equal(t, spans[2].SpanID, spans[1].ParentSpanID)
+		assert.Equal(t, "people/{id}", spans[1].Attributes["http.route"])
+
+		for _, span := range spans {
+			assert.Equal(t, root.TraceID, span.TraceID)
+		}
+
+		assert.Equal(t, spans[1], currentSpan)
+		assert.Equal(t, root.SpanContext().Traceparent(), response.Header().Get(TraceparentHeader))
+
+	}
+
+}
+
+func TestTracer_ContinuesTrace(t *testing.T) {
+
+	exporter := NewInMemoryExporter()
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.AddObserver(NewTracer(exporter))
+
+	h.Map("people", func(c context.Context) error {
+		return nil
+	})
+
+	request, _ := http.NewRequest("GET", "http://stretchr.org/people", nil)
+	request.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	request.Header.Set(TracestateHeader, "congo=t61rcWkgMzE")
+	response := new(http_test.TestResponseWriter)
+	h.ServeHTTP(response, request)
+
+	spans := exporter.Spans()
+	root := spans[len(spans)-1]
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", root.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", root.ParentSpanID.String())
+	assert.Equal(t, "congo=t61rcWkgMzE", root.TraceState)
+
+	assert.Equal(t, root.SpanContext().Traceparent(), response.Header().Get(TraceparentHeader))
+	assert.Equal(t, "congo=t61rcWkgMzE", response.Header().Get(TracestateHeader))
+
+}
+
+func TestTracer_RecordsErrors(t *testing.T) {
+
+	exporter := NewInMemoryExporter()
+	h := handlers.NewHttpHandler(codecsservices.NewWebCodecService())
+	h.AddObserver(NewTracer(exporter))
+
+	h.Map("people", func(c context.Context) error {
+		return errors.New("Test error")
+	})
+
+	request, _ := http.NewRequest("GET", "http://stretchr.org/people", nil)
+	h.ServeHTTP(new(http_test.TestResponseWriter), request)
+
+	spans := exporter.Spans()
+	root := spans[len(spans)-1]
+
+	assert.Equal(t, "people", spans[1].Name)
+	assert.Equal(t, "Test error", spans[1].Error)
+	assert.Equal(t, "Test error", root.Error)
+	assert.Equal(t, http.StatusInternalServerError, root.Attributes["http.status_code"])
+
+}
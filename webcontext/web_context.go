@@ -1,6 +1,7 @@
 package webcontext
 
 import (
+	"errors"
 	codecsservices "github.com/stretchr/codecs/services"
 	"github.com/stretchr/goweb/context"
 	"github.com/stretchr/goweb/paths"
@@ -114,7 +115,12 @@ func (c *WebContext) RequestDataArray() ([]interface{}, error) {
 		return nil, err
 	}
 
-	return obj.([]interface{}), nil
+	array, ok := obj.([]interface{})
+	if !ok {
+		return nil, errors.New("goweb: request data is not an array")
+	}
+
+	return array, nil
 
 }
 
@@ -145,15 +151,10 @@ func (c *WebContext) RequestBody() ([]byte, error) {
 
 // MethodString gets the HTTP method of this request as an uppercase string.
 //
-// If a "method" parameter is specified in the URL, it will be used. Otherwise,
-// the method of the HTTP request itself will be used.
+// To let clients override the method (for example with a "method" URL
+// parameter) see handlers.MethodOverridePolicy.
 func (c *WebContext) MethodString() string {
-	method := c.QueryValue("method")
-	if method == "" {
-		method = strings.ToUpper(c.HttpRequest().Method)
-	}
-
-	return method
+	return strings.ToUpper(c.HttpRequest().Method)
 }
 
 // HttpRequest gets the underlying http.Request that this Context represents.
@@ -183,6 +184,13 @@ func (c *WebContext) SetHttpResponseWriter(responseWriter http.ResponseWriter) {
 	c.httpResponseWriter = responseWriter
 }
 
+// SetPath sets the paths.Path of the request.  This is set automatically by Goweb,
+// but can be overridden for advanced cases, such as keeping encoded slashes in
+// path segments (see paths.NewPathFromURL).
+func (c *WebContext) SetPath(path *paths.Path) {
+	c.path = path
+}
+
 // SetHttpRequest sets the HttpRequest that represents the original request that
 // issued the interaction.  This is set automatically by Goweb, but can be overridden for
 // advanced cases.
@@ -118,7 +118,8 @@ func TestMethodString(t *testing.T) {
 
 	c = NewWebContext(responseWriter, testRequest, codecService)
 
-	assert.Equal(t, "PATCH", c.MethodString())
+	// the method parameter is only honoured by a handlers.MethodOverridePolicy
+	assert.Equal(t, "GET", c.MethodString())
 
 }
 